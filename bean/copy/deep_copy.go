@@ -0,0 +1,266 @@
+package copier
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// fieldPair is a pair of exported fields with the same name in the source and destination struct.
+type fieldPair struct {
+	name   string
+	sIndex int // source index
+	dIndex int // destination index
+}
+
+// visitKey identifies a pointer, slice or map copied to a destination type.
+type visitKey struct {
+	ptr    uintptr
+	len    int
+	srcTyp reflect.Type
+	dstTyp reflect.Type
+}
+
+// deepCopier copies values recursively in deep-copy mode, it is created for each copy.
+type deepCopier struct {
+	cc          copyConf
+	atomicTypes []reflect.Type
+
+	structFields *sync.Map // map[[2]reflect.Type][]fieldPair, shared by the copies of the same RefCopier
+	visited      map[visitKey]reflect.Value
+}
+
+func newDeepCopier(structFields *sync.Map, atomicTypes []reflect.Type, cc copyConf) *deepCopier {
+	return &deepCopier{
+		cc:           cc,
+		atomicTypes:  atomicTypes,
+		structFields: structFields,
+		visited:      map[visitKey]reflect.Value{},
+	}
+}
+
+// seen returns the copy of src to dst's type if src has been copied.
+func (dc *deepCopier) seen(src, dst reflect.Value) (reflect.Value, bool) {
+	copied, ok := dc.visited[dc.visitKey(src, dst)]
+	return copied, ok
+}
+
+// visit records copied as the copy of src to dst's type.
+func (dc *deepCopier) visit(src, dst, copied reflect.Value) {
+	dc.visited[dc.visitKey(src, dst)] = copied
+}
+
+func (dc *deepCopier) visitKey(src, dst reflect.Value) visitKey {
+	key := visitKey{ptr: src.Pointer(), srcTyp: src.Type(), dstTyp: dst.Type()}
+	if src.Kind() == reflect.Slice {
+		key.len = src.Len()
+	}
+	return key
+}
+
+// copy copies src to dst recursively, name is the name of the field being copied.
+func (dc *deepCopier) copy(name string, src, dst reflect.Value) error {
+	if src.IsZero() {
+		return nil
+	}
+
+	srcTyp := src.Type()
+	dstTyp := dst.Type()
+
+	if slices.Contains(dc.atomicTypes, srcTyp) {
+		if srcTyp != dstTyp {
+			return errFieldTypeMismatch(name, srcTyp, dstTyp)
+		}
+		dst.Set(src)
+		return nil
+	}
+
+	// pointers are dereferenced independently like the field tree
+	if src.Kind() == reflect.Pointer && dst.Kind() != reflect.Pointer {
+		return dc.copy(name, src.Elem(), dst)
+	}
+	if dst.Kind() == reflect.Pointer && src.Kind() != reflect.Pointer {
+		val := reflect.New(dstTyp.Elem())
+		if err := dc.copy(name, src, val.Elem()); err != nil {
+			return err
+		}
+		dst.Set(val)
+		return nil
+	}
+
+	if src.Kind() != dst.Kind() {
+		return errFieldTypeMismatch(name, srcTyp, dstTyp)
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		return dc.copyPointer(name, src, dst)
+	case reflect.Struct:
+		return dc.copyStruct(src, dst)
+	case reflect.Slice:
+		return dc.copySlice(name, src, dst)
+	case reflect.Array:
+		return dc.copyArray(name, src, dst)
+	case reflect.Map:
+		return dc.copyMap(name, src, dst)
+	case reflect.Interface:
+		return dc.copyInterface(name, src, dst)
+	default:
+		// basic types, channels and functions
+		if srcTyp != dstTyp {
+			return errFieldTypeMismatch(name, srcTyp, dstTyp)
+		}
+		dst.Set(src)
+		return nil
+	}
+}
+
+func (dc *deepCopier) copyPointer(name string, src, dst reflect.Value) error {
+	if copied, ok := dc.seen(src, dst); ok {
+		dst.Set(copied)
+		return nil
+	}
+
+	val := reflect.New(dst.Type().Elem())
+	dc.visit(src, dst, val)
+	if err := dc.copy(name, src.Elem(), val.Elem()); err != nil {
+		return err
+	}
+	dst.Set(val)
+	return nil
+}
+
+func (dc *deepCopier) copyStruct(src, dst reflect.Value) error {
+	for _, fd := range dc.fieldPairs(src.Type(), dst.Type()) {
+		if dc.cc.InIgnore(fd.name) {
+			continue
+		}
+
+		srcFdVal := src.Field(fd.sIndex)
+		dstFdVal := dst.Field(fd.dIndex)
+
+		convertFunc, ok := dc.cc.covertFds[fd.name]
+		if !ok {
+			if err := dc.copy(fd.name, srcFdVal, dstFdVal); err != nil {
+				return err
+			}
+			continue
+		}
+
+		srcConverted, err := convertFunc(srcFdVal.Interface())
+		if err != nil {
+			return err
+		}
+
+		srcConvTyp := reflect.TypeOf(srcConverted)
+		if srcConvTyp != dstFdVal.Type() {
+			return errFieldTypeMismatch(fd.name, srcConvTyp, dstFdVal.Type())
+		}
+		dstFdVal.Set(reflect.ValueOf(srcConverted))
+	}
+	return nil
+}
+
+func (dc *deepCopier) copySlice(name string, src, dst reflect.Value) error {
+	if copied, ok := dc.seen(src, dst); ok {
+		dst.Set(copied)
+		return nil
+	}
+
+	val := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+	dc.visit(src, dst, val)
+	for i := range src.Len() {
+		if err := dc.copy(name, src.Index(i), val.Index(i)); err != nil {
+			return err
+		}
+	}
+	dst.Set(val)
+	return nil
+}
+
+func (dc *deepCopier) copyArray(name string, src, dst reflect.Value) error {
+	if src.Len() != dst.Len() {
+		return errFieldTypeMismatch(name, src.Type(), dst.Type())
+	}
+
+	for i := range src.Len() {
+		if err := dc.copy(name, src.Index(i), dst.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dc *deepCopier) copyMap(name string, src, dst reflect.Value) error {
+	if copied, ok := dc.seen(src, dst); ok {
+		dst.Set(copied)
+		return nil
+	}
+
+	dstTyp := dst.Type()
+	val := reflect.MakeMapWithSize(dstTyp, src.Len())
+	dc.visit(src, dst, val)
+
+	iter := src.MapRange()
+	for iter.Next() {
+		key := reflect.New(dstTyp.Key()).Elem()
+		if err := dc.copy(name, iter.Key(), key); err != nil {
+			return err
+		}
+
+		elem := reflect.New(dstTyp.Elem()).Elem()
+		if err := dc.copy(name, iter.Value(), elem); err != nil {
+			return err
+		}
+
+		val.SetMapIndex(key, elem)
+	}
+	dst.Set(val)
+	return nil
+}
+
+func (dc *deepCopier) copyInterface(name string, src, dst reflect.Value) error {
+	// copy the dynamic value to a value of the same type
+	elem := src.Elem()
+	val := reflect.New(elem.Type()).Elem()
+	if err := dc.copy(name, elem, val); err != nil {
+		return err
+	}
+
+	if !val.Type().AssignableTo(dst.Type()) {
+		return errFieldTypeMismatch(name, val.Type(), dst.Type())
+	}
+	dst.Set(val)
+	return nil
+}
+
+// fieldPairs returns the exported fields with the same name in srcTyp and dstTyp.
+func (dc *deepCopier) fieldPairs(srcTyp, dstTyp reflect.Type) []fieldPair {
+	key := [2]reflect.Type{srcTyp, dstTyp}
+	if pairs, ok := dc.structFields.Load(key); ok {
+		return pairs.([]fieldPair)
+	}
+
+	srcMap := map[string]int{}
+	for i := range srcTyp.NumField() {
+		fd := srcTyp.Field(i)
+		if fd.IsExported() {
+			srcMap[fd.Name] = i
+		}
+	}
+
+	pairs := make([]fieldPair, 0, len(srcMap))
+	for dstIdx := range dstTyp.NumField() {
+		dstFd := dstTyp.Field(dstIdx)
+		if !dstFd.IsExported() {
+			continue
+		}
+
+		if srcIdx, ok := srcMap[dstFd.Name]; ok {
+			pairs = append(pairs, fieldPair{name: dstFd.Name, sIndex: srcIdx, dIndex: dstIdx})
+		}
+	}
+
+	dc.structFields.Store(key, pairs)
+	return pairs
+}
@@ -4,6 +4,7 @@ import (
 	"maps"
 	"reflect"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/JrMarcco/jit/bean/option"
@@ -15,9 +16,13 @@ type RefCopier[S any, D any] struct {
 	atomicTypes []reflect.Type
 
 	defaultConf copyConf
+
+	structFields sync.Map // map[[2]reflect.Type][]fieldPair, field pairs of the structs copied in deep-copy mode
 }
 
-func (rc *RefCopier[S, D]) createFieldNode(srcTyp, dstTyp reflect.Type, root *fieldNode) error {
+// createFieldNode creates the field tree of srcTyp and dstTyp,
+// visiting holds the struct types being created to stop at recursive types.
+func (rc *RefCopier[S, D]) createFieldNode(srcTyp, dstTyp reflect.Type, root *fieldNode, visiting []reflect.Type) error {
 	srcMap := map[string]int{}
 	for i := range srcTyp.NumField() {
 		fd := srcTyp.Field(i)
@@ -69,9 +74,12 @@ func (rc *RefCopier[S, D]) createFieldNode(srcTyp, dstTyp reflect.Type, root *fi
 			} else if rc.isAtomicType(srcFdTyp) {
 				// is atomic type, node is leaf node
 			} else if srcFdTyp.Kind() == reflect.Struct {
-				// is struct type
-				if err := rc.createFieldNode(srcFdTyp, dstFdTyp, &node); err != nil {
-					return err
+				// is recursive struct type, node is leaf node and copied as a whole
+				if !slices.Contains(visiting, srcFdTyp) {
+					// is struct type
+					if err := rc.createFieldNode(srcFdTyp, dstFdTyp, &node, append(visiting, srcFdTyp)); err != nil {
+						return err
+					}
 				}
 			} else {
 				// is not builtin type, not struct type, not atomic type
@@ -108,6 +116,7 @@ func (rc *RefCopier[S, D]) CopyTo(src *S, dst *D, opts ...option.Opt[copyConf])
 
 func (rc *RefCopier[S, D]) defaultCopyConf() copyConf {
 	cc := newCopyConf()
+	cc.deep = rc.defaultConf.deep
 
 	if rc.defaultConf.ignoreFds != nil {
 		ignoreFds := xset.NewMapSet[string](rc.defaultConf.ignoreFds.Size())
@@ -135,10 +144,15 @@ func (rc *RefCopier[S, D]) copyTree(src *S, dst *D, cc copyConf) error {
 	dstTyp := reflect.TypeOf(dst)
 	dstVal := reflect.ValueOf(dst)
 
-	return rc.copyNode(srcTyp, srcVal, dstTyp, dstVal, &rc.root, cc)
+	var dc *deepCopier
+	if cc.deep {
+		dc = newDeepCopier(&rc.structFields, rc.atomicTypes, cc)
+	}
+	return rc.copyNode(srcTyp, srcVal, dstTyp, dstVal, &rc.root, cc, dc)
 }
 
-func (rc *RefCopier[S, D]) copyNode(srcTyp reflect.Type, srcVal reflect.Value, dstTyp reflect.Type, dstVal reflect.Value, root *fieldNode, cc copyConf) error {
+// copyNode copies srcVal to dstVal by the field tree root, dc is nil unless in deep-copy mode.
+func (rc *RefCopier[S, D]) copyNode(srcTyp reflect.Type, srcVal reflect.Value, dstTyp reflect.Type, dstVal reflect.Value, root *fieldNode, cc copyConf, dc *deepCopier) error {
 	oriSrcVal := srcVal
 	oriDstVal := dstVal
 
@@ -151,10 +165,22 @@ func (rc *RefCopier[S, D]) copyNode(srcTyp reflect.Type, srcVal reflect.Value, d
 	}
 
 	if dstVal.Kind() == reflect.Pointer {
+		if dc != nil && oriSrcVal.Kind() == reflect.Pointer {
+			if copied, ok := dc.seen(oriSrcVal, dstVal); ok {
+				// is cycle, points to the copied value
+				dstVal.Set(copied)
+				return nil
+			}
+		}
+
 		if dstVal.IsNil() {
 			dstVal.Set(reflect.New(dstTyp.Elem()))
 		}
 
+		if dc != nil && oriSrcVal.Kind() == reflect.Pointer {
+			dc.visit(oriSrcVal, dstVal, dstVal)
+		}
+
 		dstVal = dstVal.Elem()
 		dstTyp = dstTyp.Elem()
 	}
@@ -167,6 +193,11 @@ func (rc *RefCopier[S, D]) copyNode(srcTyp reflect.Type, srcVal reflect.Value, d
 
 		convertFunc, ok := cc.covertFds[fdName]
 		if !ok {
+			if dc != nil {
+				// copy recursively, the element types may differ
+				return dc.copy(fdName, srcVal, dstVal)
+			}
+
 			if srcTyp != dstTyp {
 				return errFieldTypeMismatch(fdName, srcTyp, dstTyp)
 			}
@@ -211,7 +242,7 @@ func (rc *RefCopier[S, D]) copyNode(srcTyp reflect.Type, srcVal reflect.Value, d
 		dstFdTyp := dstTyp.Field(field.dIndex)
 		dstFdVal := dstVal.Field(field.dIndex)
 
-		if err := rc.copyNode(srcFdTyp.Type, srcFdVal, dstFdTyp.Type, dstFdVal, &field, cc); err != nil {
+		if err := rc.copyNode(srcFdTyp.Type, srcFdVal, dstFdTyp.Type, dstFdVal, &field, cc, dc); err != nil {
 			return err
 		}
 	}
@@ -242,7 +273,7 @@ func NewRefCopier[S any, D any](opts ...option.Opt[copyConf]) (*RefCopier[S, D],
 		},
 	}
 
-	if err := copier.createFieldNode(srcTyp, dstTyp, &root); err != nil {
+	if err := copier.createFieldNode(srcTyp, dstTyp, &root, []reflect.Type{srcTyp}); err != nil {
 		return nil, err
 	}
 
@@ -1,9 +1,11 @@
 package copier
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/JrMarcco/jit"
+	"github.com/JrMarcco/jit/bean/copy/converter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -150,6 +152,102 @@ func TestRefCopier_Copy(t *testing.T) {
 	}
 }
 
+func TestRefCopier_DeepCopy(t *testing.T) {
+	t.Parallel()
+
+	src := &deepSrc{
+		Ints:  []int{1, 2, 3},
+		Items: []itemSrc{{Name: "a", Tags: []string{"x"}}, {Name: "b"}},
+		ByKey: map[string]itemSrc{"a": {Name: "a", Tags: []string{"y"}}},
+		Arr:   [2]itemSrc{{Name: "c"}, {Name: "d", Tags: []string{"z"}}},
+		Ptrs:  map[string]*itemSrc{"e": {Name: "e"}},
+		Anys:  []any{[]int{4, 5}},
+	}
+
+	copier, err := NewRefCopier[deepSrc, deepDst](DeepCopy())
+	require.NoError(t, err)
+
+	dst, err := copier.Copy(src)
+	require.NoError(t, err)
+	assert.Equal(t, &deepDst{
+		Ints:  []int{1, 2, 3},
+		Items: []itemDst{{Name: "a", Tags: []string{"x"}}, {Name: "b"}},
+		ByKey: map[string]itemDst{"a": {Name: "a", Tags: []string{"y"}}},
+		Arr:   [2]itemDst{{Name: "c"}, {Name: "d", Tags: []string{"z"}}},
+		Ptrs:  map[string]*itemDst{"e": {Name: "e"}},
+		Anys:  []any{[]int{4, 5}},
+	}, dst)
+
+	// nothing is shared with src
+	src.Ints[0] = 10
+	src.Items[0].Tags[0] = "changed"
+	src.ByKey["a"].Tags[0] = "changed"
+	src.Arr[1].Tags[0] = "changed"
+	src.Ptrs["e"].Name = "changed"
+	src.Anys[0].([]int)[0] = 10
+	src.ByKey["b"] = itemSrc{Name: "b"}
+
+	assert.Equal(t, 1, dst.Ints[0])
+	assert.Equal(t, "x", dst.Items[0].Tags[0])
+	assert.Equal(t, "y", dst.ByKey["a"].Tags[0])
+	assert.Equal(t, "z", dst.Arr[1].Tags[0])
+	assert.Equal(t, "e", dst.Ptrs["e"].Name)
+	assert.Equal(t, 4, dst.Anys[0].([]int)[0])
+	assert.Len(t, dst.ByKey, 1)
+
+	// ignore and convert fields of the nested structs by name
+	dst, err = copier.Copy(src, IgnoreFds("Tags"), ConvertFd("Name", converter.ConvertFunc[string, string](
+		func(src string) (string, error) {
+			return src + "!", nil
+		},
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, []itemDst{{Name: "a!"}, {Name: "b!"}}, dst.Items)
+
+	// shares the slices and maps with src without DeepCopy
+	shallow, err := NewRefCopier[sliceSrc, sliceDst]()
+	require.NoError(t, err)
+
+	sSrc := &sliceSrc{Ints: []int{1, 2, 3}}
+	sDst, err := shallow.Copy(sSrc)
+	require.NoError(t, err)
+	sSrc.Ints[0] = 10
+	assert.Equal(t, 10, sDst.Ints[0])
+
+	sDst, err = shallow.Copy(sSrc, DeepCopy())
+	require.NoError(t, err)
+	sSrc.Ints[0] = 1
+	assert.Equal(t, 10, sDst.Ints[0])
+
+	// element types mismatch
+	mismatch, err := NewRefCopier[deepSrc, mismatchDst](DeepCopy())
+	require.NoError(t, err)
+	_, err = mismatch.Copy(src)
+	assert.Equal(t, errFieldTypeMismatch("Ints", reflect.TypeOf(0), reflect.TypeOf("")), err)
+}
+
+func TestRefCopier_DeepCopyCycle(t *testing.T) {
+	t.Parallel()
+
+	copier, err := NewRefCopier[nodeSrc, nodeDst](DeepCopy())
+	require.NoError(t, err)
+
+	// a -> b -> a
+	a := &nodeSrc{Val: 1}
+	b := &nodeSrc{Val: 2, Next: a}
+	a.Next = b
+	a.Nodes = map[int]*nodeSrc{1: a, 2: b}
+
+	dst, err := copier.Copy(a)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, dst.Val)
+	assert.Equal(t, 2, dst.Next.Val)
+	assert.Same(t, dst, dst.Next.Next)
+	assert.Same(t, dst, dst.Nodes[1])
+	assert.Same(t, dst.Next, dst.Nodes[2])
+}
+
 type param struct {
 	Val string
 }
@@ -218,6 +316,50 @@ type complexDst struct {
 	Embed embedDst
 }
 
+type itemSrc struct {
+	Name string
+	Tags []string
+}
+
+type itemDst struct {
+	Name string
+	Tags []string
+}
+
+type deepSrc struct {
+	Ints  []int
+	Items []itemSrc
+	ByKey map[string]itemSrc
+	Arr   [2]itemSrc
+	Ptrs  map[string]*itemSrc
+	Anys  []any
+}
+
+type deepDst struct {
+	Ints  []int
+	Items []itemDst
+	ByKey map[string]itemDst
+	Arr   [2]itemDst
+	Ptrs  map[string]*itemDst
+	Anys  []any
+}
+
+type mismatchDst struct {
+	Ints []string
+}
+
+type nodeSrc struct {
+	Val   int
+	Next  *nodeSrc
+	Nodes map[int]*nodeSrc
+}
+
+type nodeDst struct {
+	Val   int
+	Next  *nodeDst
+	Nodes map[int]*nodeDst
+}
+
 func BenchmarkRefCopier_Copy(b *testing.B) {
 	b.Run("reuse", func(b *testing.B) {
 		copier, err := NewRefCopier[complexSrc, complexDst]()
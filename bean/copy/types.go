@@ -17,6 +17,7 @@ type convertFunc func(src any) (any, error)
 type copyConf struct {
 	ignoreFds *xset.MapSet[string]
 	covertFds map[string]convertFunc
+	deep      bool
 }
 
 func newCopyConf() copyConf {
@@ -46,6 +47,17 @@ func IgnoreFds(fds ...string) option.Opt[copyConf] {
 	}
 }
 
+// DeepCopy enables the deep-copy mode.
+// Slices, arrays, maps and the values pointed by pointers are copied recursively instead of sharing with the source,
+// elements of different struct types such as []SrcStruct to []DstStruct are copied field by field,
+// and cycles through pointers, slices and maps are copied into the same cycles.
+// Channels and functions are still copied by reference.
+func DeepCopy() option.Opt[copyConf] {
+	return func(cc *copyConf) {
+		cc.deep = true
+	}
+}
+
 func ConvertFd[S any, D any](fd string, converter converter.Converter[S, D]) option.Opt[copyConf] {
 	return func(cc *copyConf) {
 		if fd == "" || converter == nil {
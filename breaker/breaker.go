@@ -0,0 +1,372 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var (
+	errInvalidParam = errors.New("[jit] invalid param")
+
+	// ErrOpenState is returned when the circuit breaker is open.
+	ErrOpenState = errors.New("[jit] circuit breaker is open")
+	// ErrTooManyRequests is returned when the circuit breaker is half-open and all the probes are in use.
+	ErrTooManyRequests = errors.New("[jit] too many requests in half-open state")
+)
+
+// State is the state of a CircuitBreaker.
+type State int8
+
+const (
+	// StateClosed lets all calls through and records their results.
+	StateClosed State = iota
+	// StateOpen rejects all calls until the open timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a limited number of probes through to decide whether the callee has recovered.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calling a failing callee for a while so that it has a chance to recover.
+//
+// In the closed state the results of calls are recorded in a sliding window,
+// the breaker trips to open once the window holds at least minRequests calls
+// and the failures reach the failure threshold or the failure ratio.
+// After the open timeout the breaker turns half-open and lets at most halfOpenMaxRequests probes through:
+// the breaker closes once all of them succeed and opens again on any failure.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	clock  clock.Clock
+	window window
+
+	minRequests         int
+	failureThreshold    int     // trips when failures reach it, 0 means disabled
+	failureRatio        float64 // trips when failures / total reach it, 0 means disabled
+	openTimeout         time.Duration
+	halfOpenMaxRequests int
+
+	isFailure     func(err error) bool
+	onStateChange func(from, to State)
+
+	state      State
+	generation uint64    // increased on every state change, results of calls from older generations are dropped
+	openedAt   time.Time // when the breaker turned open
+
+	probes         int // probes let through in half-open state
+	probeSuccesses int // succeeded probes in half-open state
+}
+
+// State returns the current state of the breaker.
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	state, _, changes := cb.currentState(cb.clock.Now())
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+	return state
+}
+
+// Counts returns the count of calls and failures in the sliding window.
+func (cb *CircuitBreaker) Counts() (total int, failures int) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.window.counts(cb.clock.Now())
+}
+
+// Allow checks whether a call is allowed, the caller must report the result of the call by done.
+// It returns ErrOpenState or ErrTooManyRequests when the call is rejected.
+func (cb *CircuitBreaker) Allow() (done func(err error), err error) {
+	cb.mu.Lock()
+	state, generation, changes := cb.currentState(cb.clock.Now())
+
+	switch state {
+	case StateOpen:
+		err = ErrOpenState
+	case StateHalfOpen:
+		if cb.probes >= cb.halfOpenMaxRequests {
+			err = ErrTooManyRequests
+		} else {
+			cb.probes++
+		}
+	default:
+	}
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			cb.record(generation, cb.isFailure(err))
+		})
+	}, nil
+}
+
+// Do calls fn if the breaker allows and records its result.
+// It returns ErrOpenState or ErrTooManyRequests without calling fn when the call is rejected.
+func (cb *CircuitBreaker) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// a panic is recorded as a failure
+		if r := recover(); r != nil {
+			done(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+		done(err)
+	}()
+	return fn(ctx)
+}
+
+// Reset closes the breaker and clears the sliding window.
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	changes := cb.setState(StateClosed, cb.clock.Now())
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+}
+
+// record records the result of a call allowed in generation.
+func (cb *CircuitBreaker) record(generation uint64, failure bool) {
+	cb.mu.Lock()
+	now := cb.clock.Now()
+	state, current, changes := cb.currentState(now)
+	if generation == current {
+		changes = append(changes, cb.recordLocked(state, now, failure)...)
+	}
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+}
+
+// report records the result of a call made without Allow in the current generation,
+// it takes no probe in half-open state and the result is dropped in open state.
+func (cb *CircuitBreaker) report(failure bool) {
+	cb.mu.Lock()
+	now := cb.clock.Now()
+	state, _, changes := cb.currentState(now)
+	changes = append(changes, cb.recordLocked(state, now, failure)...)
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+}
+
+func (cb *CircuitBreaker) recordLocked(state State, now time.Time, failure bool) []stateChange {
+	switch state {
+	case StateClosed:
+		cb.window.record(now, failure)
+		if cb.shouldTrip(now) {
+			return cb.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failure {
+			return cb.setState(StateOpen, now)
+		}
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.halfOpenMaxRequests {
+			return cb.setState(StateClosed, now)
+		}
+	default:
+	}
+	return nil
+}
+
+func (cb *CircuitBreaker) shouldTrip(now time.Time) bool {
+	total, failures := cb.window.counts(now)
+	if total < cb.minRequests || failures == 0 {
+		return false
+	}
+	if cb.failureThreshold > 0 && failures >= cb.failureThreshold {
+		return true
+	}
+	return cb.failureRatio > 0 && float64(failures)/float64(total) >= cb.failureRatio
+}
+
+// currentState returns the state at now, the open breaker turns half-open once the open timeout elapses.
+func (cb *CircuitBreaker) currentState(now time.Time) (State, uint64, []stateChange) {
+	var changes []stateChange
+	if cb.state == StateOpen && !now.Before(cb.openedAt.Add(cb.openTimeout)) {
+		changes = cb.setState(StateHalfOpen, now)
+	}
+	return cb.state, cb.generation, changes
+}
+
+type stateChange struct {
+	from, to State
+}
+
+// setState moves the breaker to state and starts a new generation,
+// returns the state change to notify after unlocking.
+func (cb *CircuitBreaker) setState(state State, now time.Time) []stateChange {
+	from := cb.state
+
+	cb.generation++
+	cb.state = state
+	cb.probes, cb.probeSuccesses = 0, 0
+	cb.window.reset()
+	if state == StateOpen {
+		cb.openedAt = now
+	}
+
+	if from == state {
+		return nil
+	}
+	return []stateChange{{from: from, to: state}}
+}
+
+// notify calls the state change callback outside the lock, so that the callback is free to use the breaker.
+func (cb *CircuitBreaker) notify(changes []stateChange) {
+	if cb.onStateChange == nil {
+		return
+	}
+	for _, c := range changes {
+		cb.onStateChange(c.from, c.to)
+	}
+}
+
+// WithCountWindow sets the sliding window to the latest size calls, it is the default with size 100.
+func WithCountWindow(size int) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		if size > 0 {
+			cb.window = newCountWindow(size)
+		} else {
+			cb.window = nil
+		}
+	}
+}
+
+// WithTimeWindow sets the sliding window to the calls in the latest size duration,
+// the duration is split into buckets which expire as a whole.
+func WithTimeWindow(size time.Duration, buckets int) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		if size > 0 && buckets > 0 && size >= time.Duration(buckets) {
+			cb.window = newTimeWindow(size, buckets)
+		} else {
+			cb.window = nil
+		}
+	}
+}
+
+// WithMinRequests sets the minimum calls in the sliding window before the breaker can trip, 10 by default.
+func WithMinRequests(n int) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.minRequests = n
+	}
+}
+
+// WithFailureThreshold trips the breaker when the failures in the sliding window reach n, disabled by default.
+func WithFailureThreshold(n int) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.failureThreshold = n
+	}
+}
+
+// WithFailureRatio trips the breaker when the failure ratio in the sliding window reaches ratio,
+// 0.5 by default and 0 disables it.
+func WithFailureRatio(ratio float64) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.failureRatio = ratio
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before turning half-open, 1 minute by default.
+func WithOpenTimeout(d time.Duration) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.openTimeout = d
+	}
+}
+
+// WithHalfOpenMaxRequests sets the number of probes let through in half-open state,
+// the breaker closes once all of them succeed. 1 by default.
+func WithHalfOpenMaxRequests(n int) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.halfOpenMaxRequests = n
+	}
+}
+
+// WithIsFailure sets the predicate deciding whether the result of a call is a failure,
+// every non-nil error is a failure by default.
+func WithIsFailure(isFailure func(err error) bool) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.isFailure = isFailure
+	}
+}
+
+// WithOnStateChange sets the callback invoked on every state change.
+// It is called synchronously by the goroutine causing the change, but never with the internal lock held.
+func WithOnStateChange(onStateChange func(from, to State)) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.onStateChange = onStateChange
+	}
+}
+
+// WithClock sets the clock of the breaker, clock.Real() by default.
+func WithClock(c clock.Clock) option.Opt[CircuitBreaker] {
+	return func(cb *CircuitBreaker) {
+		cb.clock = c
+	}
+}
+
+func NewCircuitBreaker(opts ...option.Opt[CircuitBreaker]) (*CircuitBreaker, error) {
+	cb := &CircuitBreaker{
+		clock:               clock.Real(),
+		window:              newCountWindow(100),
+		minRequests:         10,
+		failureRatio:        0.5,
+		openTimeout:         time.Minute,
+		halfOpenMaxRequests: 1,
+		isFailure: func(err error) bool {
+			return err != nil
+		},
+	}
+	option.Apply(cb, opts...)
+
+	switch {
+	case cb.window == nil:
+		return nil, fmt.Errorf("%w: invalid sliding window", errInvalidParam)
+	case cb.clock == nil:
+		return nil, fmt.Errorf("%w: clock should not be nil", errInvalidParam)
+	case cb.isFailure == nil:
+		return nil, fmt.Errorf("%w: isFailure should not be nil", errInvalidParam)
+	case cb.minRequests < 1:
+		return nil, fmt.Errorf("%w: min requests should be greater than 0", errInvalidParam)
+	case cb.failureThreshold < 0:
+		return nil, fmt.Errorf("%w: failure threshold should not be negative", errInvalidParam)
+	case cb.failureRatio < 0 || cb.failureRatio > 1:
+		return nil, fmt.Errorf("%w: failure ratio should be in [0, 1]", errInvalidParam)
+	case cb.failureThreshold == 0 && cb.failureRatio == 0:
+		return nil, fmt.Errorf("%w: either failure threshold or failure ratio should be set", errInvalidParam)
+	case cb.openTimeout <= 0:
+		return nil, fmt.Errorf("%w: open timeout should be greater than 0", errInvalidParam)
+	case cb.halfOpenMaxRequests < 1:
+		return nil, fmt.Errorf("%w: half-open max requests should be greater than 0", errInvalidParam)
+	default:
+		return cb, nil
+	}
+}
@@ -0,0 +1,307 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	epoch  = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	errBiz = errors.New("biz error")
+)
+
+type transition struct {
+	from, to State
+}
+
+// newTestBreaker creates a breaker driven by a manual clock and records its state changes.
+func newTestBreaker(t *testing.T, opts ...option.Opt[CircuitBreaker]) (*CircuitBreaker, *clock.Manual, func() []transition) {
+	clk := clock.NewManual(epoch)
+
+	var mu sync.Mutex
+	var transitions []transition
+	opts = append([]option.Opt[CircuitBreaker]{
+		WithClock(clk),
+		WithOnStateChange(func(from, to State) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, transition{from: from, to: to})
+		}),
+	}, opts...)
+
+	cb, err := NewCircuitBreaker(opts...)
+	require.NoError(t, err)
+	return cb, clk, func() []transition {
+		mu.Lock()
+		defer mu.Unlock()
+		return transitions
+	}
+}
+
+func call(cb *CircuitBreaker, err error) error {
+	return cb.Do(context.Background(), func(ctx context.Context) error {
+		return err
+	})
+}
+
+func TestNewCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		opts    []option.Opt[CircuitBreaker]
+		wantErr error
+	}{
+		{name: "default"},
+		{name: "time window", opts: []option.Opt[CircuitBreaker]{WithTimeWindow(time.Minute, 6)}},
+		{name: "invalid count window", opts: []option.Opt[CircuitBreaker]{WithCountWindow(0)}, wantErr: errInvalidParam},
+		{name: "invalid time window", opts: []option.Opt[CircuitBreaker]{WithTimeWindow(time.Minute, 0)}, wantErr: errInvalidParam},
+		{name: "nil clock", opts: []option.Opt[CircuitBreaker]{WithClock(nil)}, wantErr: errInvalidParam},
+		{name: "nil isFailure", opts: []option.Opt[CircuitBreaker]{WithIsFailure(nil)}, wantErr: errInvalidParam},
+		{name: "invalid min requests", opts: []option.Opt[CircuitBreaker]{WithMinRequests(0)}, wantErr: errInvalidParam},
+		{name: "negative threshold", opts: []option.Opt[CircuitBreaker]{WithFailureThreshold(-1)}, wantErr: errInvalidParam},
+		{name: "invalid ratio", opts: []option.Opt[CircuitBreaker]{WithFailureRatio(1.5)}, wantErr: errInvalidParam},
+		{
+			name:    "no trip condition",
+			opts:    []option.Opt[CircuitBreaker]{WithFailureRatio(0)},
+			wantErr: errInvalidParam,
+		},
+		{name: "invalid open timeout", opts: []option.Opt[CircuitBreaker]{WithOpenTimeout(0)}, wantErr: errInvalidParam},
+		{
+			name:    "invalid half-open max requests",
+			opts:    []option.Opt[CircuitBreaker]{WithHalfOpenMaxRequests(0)},
+			wantErr: errInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cb, err := NewCircuitBreaker(tc.opts...)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.Equal(t, StateClosed, cb.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	t.Parallel()
+
+	cb, clk, transitions := newTestBreaker(t,
+		WithCountWindow(10), WithMinRequests(4), WithFailureRatio(0.5), WithOpenTimeout(time.Minute),
+	)
+
+	// not enough calls to trip
+	for range 3 {
+		assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	}
+	assert.Equal(t, StateClosed, cb.State())
+	total, failures := cb.Counts()
+	assert.Equal(t, 3, total)
+	assert.Equal(t, 3, failures)
+
+	// 3 / 4 reaches the ratio
+	assert.NoError(t, call(cb, nil))
+	assert.Equal(t, StateOpen, cb.State())
+
+	// rejected without calling
+	called := false
+	err := cb.Do(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrOpenState)
+	assert.False(t, called)
+
+	clk.Advance(time.Minute - time.Nanosecond)
+	assert.Equal(t, StateOpen, cb.State())
+	clk.Advance(time.Nanosecond)
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	assert.Equal(t, []transition{
+		{from: StateClosed, to: StateOpen},
+		{from: StateOpen, to: StateHalfOpen},
+	}, transitions())
+}
+
+func TestCircuitBreaker_FailureThreshold(t *testing.T) {
+	t.Parallel()
+
+	cb, _, _ := newTestBreaker(t,
+		WithCountWindow(5), WithMinRequests(1), WithFailureThreshold(2), WithFailureRatio(0),
+	)
+
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	// failures rotated out of the count window do not count
+	for range 5 {
+		assert.NoError(t, call(cb, nil))
+	}
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	assert.Equal(t, StateClosed, cb.State())
+
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_TimeWindow(t *testing.T) {
+	t.Parallel()
+
+	cb, clk, _ := newTestBreaker(t,
+		WithTimeWindow(10*time.Second, 10), WithMinRequests(1), WithFailureThreshold(3), WithFailureRatio(0),
+	)
+
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	clk.Advance(5 * time.Second)
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	total, failures := cb.Counts()
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, failures)
+
+	// the first failure expires from the window
+	clk.Advance(5 * time.Second)
+	total, _ = cb.Counts()
+	assert.Equal(t, 1, total)
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	assert.Equal(t, StateClosed, cb.State())
+
+	clk.Advance(time.Second)
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	assert.Equal(t, StateOpen, cb.State())
+
+	// the window is cleared on state changes
+	total, _ = cb.Counts()
+	assert.Zero(t, total)
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		results []error
+		want    State
+	}{
+		{name: "all probes succeed", results: []error{nil, nil}, want: StateClosed},
+		{name: "probe fails", results: []error{nil, errBiz}, want: StateOpen},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cb, clk, transitions := newTestBreaker(t,
+				WithCountWindow(10), WithMinRequests(1), WithFailureRatio(1),
+				WithOpenTimeout(time.Second), WithHalfOpenMaxRequests(2),
+			)
+
+			assert.ErrorIs(t, call(cb, errBiz), errBiz)
+			clk.Advance(time.Second)
+
+			var dones []func(error)
+			for range 2 {
+				done, err := cb.Allow()
+				require.NoError(t, err)
+				dones = append(dones, done)
+			}
+			// probes are limited
+			_, err := cb.Allow()
+			assert.ErrorIs(t, err, ErrTooManyRequests)
+
+			for i, res := range tc.results {
+				dones[i](res)
+				// done reports only once
+				dones[i](errBiz)
+			}
+			assert.Equal(t, tc.want, cb.State())
+			assert.Equal(t, []transition{
+				{from: StateClosed, to: StateOpen},
+				{from: StateOpen, to: StateHalfOpen},
+				{from: StateHalfOpen, to: tc.want},
+			}, transitions())
+		})
+	}
+}
+
+func TestCircuitBreaker_StaleResult(t *testing.T) {
+	t.Parallel()
+
+	cb, _, _ := newTestBreaker(t, WithCountWindow(10), WithMinRequests(1), WithFailureRatio(1))
+
+	done, err := cb.Allow()
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	cb.Reset()
+	assert.Equal(t, StateClosed, cb.State())
+
+	// the result of a call allowed before reset is dropped
+	done(errBiz)
+	assert.Equal(t, StateClosed, cb.State())
+	total, _ := cb.Counts()
+	assert.Zero(t, total)
+}
+
+func TestCircuitBreaker_Do(t *testing.T) {
+	t.Parallel()
+
+	cb, _, _ := newTestBreaker(t,
+		WithCountWindow(10), WithMinRequests(1), WithFailureRatio(1),
+		WithIsFailure(func(err error) bool {
+			return err != nil && !errors.Is(err, context.Canceled)
+		}),
+	)
+
+	// ignored errors are recorded as successes
+	assert.ErrorIs(t, call(cb, context.Canceled), context.Canceled)
+	assert.Equal(t, StateClosed, cb.State())
+
+	assert.Panics(t, func() {
+		_ = cb.Do(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	total, failures := cb.Counts()
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 1, failures)
+}
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch)
+
+	var cb *CircuitBreaker
+	var states []State
+	cb, err := NewCircuitBreaker(
+		WithClock(clk), WithMinRequests(1), WithFailureRatio(1),
+		WithOnStateChange(func(from, to State) {
+			// the callback is free to use the breaker
+			states = append(states, cb.State())
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, call(cb, errBiz), errBiz)
+	cb.Reset()
+	assert.Equal(t, []State{StateOpen, StateClosed}, states)
+}
+
+func TestState_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "unknown", State(-1).String())
+}
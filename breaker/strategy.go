@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"time"
+
+	"github.com/JrMarcco/jit/retry"
+)
+
+var _ retry.Strategy = (*retryStrategy)(nil)
+
+// retryStrategy stops retrying while the breaker rejects calls.
+type retryStrategy struct {
+	cb       *CircuitBreaker
+	strategy retry.Strategy
+}
+
+func (s *retryStrategy) Next() (time.Duration, bool) {
+	if !s.cb.ready() {
+		return 0, false
+	}
+	return s.strategy.Next()
+}
+
+func (s *retryStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	if !s.cb.ready() {
+		return 0, false
+	}
+	return s.strategy.NextWithRetried(retriedTimes)
+}
+
+func (s *retryStrategy) Report(err error) retry.Strategy {
+	s.cb.report(s.cb.isFailure(err))
+	s.strategy.Report(err)
+	return s
+}
+
+func (s *retryStrategy) Reset() {
+	s.strategy.Reset()
+}
+
+// Strategy wraps strategy as a retry.Strategy gated by the breaker.
+// Every result reported to it is recorded by the breaker as a call without taking a half-open probe,
+// and it stops retrying while the breaker is open or has no half-open probe left.
+func (cb *CircuitBreaker) Strategy(strategy retry.Strategy) retry.Strategy {
+	return &retryStrategy{cb: cb, strategy: strategy}
+}
+
+// ready reports whether a call would be allowed, without taking a half-open probe.
+func (cb *CircuitBreaker) ready() bool {
+	cb.mu.Lock()
+	state, _, changes := cb.currentState(cb.clock.Now())
+	ready := state == StateClosed || (state == StateHalfOpen && cb.probes < cb.halfOpenMaxRequests)
+	cb.mu.Unlock()
+
+	cb.notify(changes)
+	return ready
+}
@@ -0,0 +1,87 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/internal/errs"
+	"github.com/JrMarcco/jit/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_Strategy(t *testing.T) {
+	t.Parallel()
+
+	cb, clk, _ := newTestBreaker(t,
+		WithCountWindow(10), WithMinRequests(3), WithFailureRatio(1), WithOpenTimeout(time.Minute),
+	)
+
+	fis, err := retry.NewFixedIntervalStrategy(time.Millisecond, 10)
+	require.NoError(t, err)
+	s := cb.Strategy(fis)
+
+	// the breaker trips after 3 failures and stops retrying
+	calls := 0
+	err = retry.Retry(context.Background(), s, func() error {
+		calls++
+		return errBiz
+	})
+	assert.Equal(t, errs.ErrRetryTimeExhausted(errBiz), err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, StateOpen, cb.State())
+
+	_, ok := s.NextWithRetried(1)
+	assert.False(t, ok)
+
+	// a half-open probe is allowed after the open timeout
+	clk.Advance(time.Minute)
+	next, ok := s.NextWithRetried(1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Millisecond, next)
+
+	err = retry.Retry(context.Background(), s, func() error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_StrategyReportHalfOpen(t *testing.T) {
+	t.Parallel()
+
+	cb, clk, _ := newTestBreaker(t,
+		WithCountWindow(10), WithMinRequests(1), WithFailureThreshold(1),
+		WithOpenTimeout(time.Minute), WithHalfOpenMaxRequests(2),
+	)
+
+	fis, err := retry.NewFixedIntervalStrategy(time.Millisecond, 10)
+	require.NoError(t, err)
+	s := cb.Strategy(fis)
+
+	s.Report(errBiz)
+	assert.Equal(t, StateOpen, cb.State())
+	clk.Advance(time.Minute)
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	// reports take no probe
+	s.Report(nil)
+	done, err := cb.Allow()
+	require.NoError(t, err)
+	_, err = cb.Allow()
+	require.NoError(t, err)
+	_, err = cb.Allow()
+	assert.ErrorIs(t, err, ErrTooManyRequests)
+
+	// the reported success counts towards closing the breaker
+	done(nil)
+	assert.Equal(t, StateClosed, cb.State())
+
+	// a reported failure opens the half-open breaker
+	s.Report(errBiz)
+	clk.Advance(time.Minute)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	s.Report(errBiz)
+	assert.Equal(t, StateOpen, cb.State())
+}
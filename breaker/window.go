@@ -0,0 +1,111 @@
+package breaker
+
+import "time"
+
+// window records the results of recent calls.
+type window interface {
+	// record adds the result of a call finished at now.
+	record(now time.Time, failure bool)
+	// counts returns the count of calls and failures in the window at now.
+	counts(now time.Time) (total int, failures int)
+	// reset clears all the results.
+	reset()
+}
+
+var (
+	_ window = (*countWindow)(nil)
+	_ window = (*timeWindow)(nil)
+)
+
+// countWindow keeps the results of the latest size calls.
+type countWindow struct {
+	results  []bool // ring buffer, true means a failure
+	pos      int    // position of the next result
+	total    int
+	failures int
+}
+
+func (w *countWindow) record(_ time.Time, failure bool) {
+	if w.total == len(w.results) {
+		// overwrite the oldest result
+		if w.results[w.pos] {
+			w.failures--
+		}
+	} else {
+		w.total++
+	}
+
+	w.results[w.pos] = failure
+	if failure {
+		w.failures++
+	}
+	w.pos = (w.pos + 1) % len(w.results)
+}
+
+func (w *countWindow) counts(_ time.Time) (int, int) {
+	return w.total, w.failures
+}
+
+func (w *countWindow) reset() {
+	clear(w.results)
+	w.pos, w.total, w.failures = 0, 0, 0
+}
+
+func newCountWindow(size int) *countWindow {
+	return &countWindow{results: make([]bool, size)}
+}
+
+// timeWindow keeps the results of the calls in the latest size duration,
+// the duration is split into buckets which expire as a whole.
+type timeWindow struct {
+	width   time.Duration // duration of a bucket
+	buckets []bucket
+}
+
+type bucket struct {
+	epoch    int64 // index of the bucket since the zero time, identifies whether the bucket expired
+	total    int
+	failures int
+}
+
+func (w *timeWindow) record(now time.Time, failure bool) {
+	epoch := w.epoch(now)
+	b := &w.buckets[epoch%int64(len(w.buckets))]
+	if b.epoch != epoch {
+		*b = bucket{epoch: epoch}
+	}
+
+	b.total++
+	if failure {
+		b.failures++
+	}
+}
+
+func (w *timeWindow) counts(now time.Time) (int, int) {
+	oldest := w.epoch(now) - int64(len(w.buckets))
+
+	var total, failures int
+	for _, b := range w.buckets {
+		if b.epoch > oldest {
+			total += b.total
+			failures += b.failures
+		}
+	}
+	return total, failures
+}
+
+func (w *timeWindow) reset() {
+	clear(w.buckets)
+}
+
+func (w *timeWindow) epoch(now time.Time) int64 {
+	// offset by 1 so that the zero bucket never matches a valid epoch
+	return now.UnixNano()/int64(w.width) + 1
+}
+
+func newTimeWindow(size time.Duration, buckets int) *timeWindow {
+	return &timeWindow{
+		width:   size / time.Duration(buckets),
+		buckets: make([]bucket, buckets),
+	}
+}
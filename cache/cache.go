@@ -0,0 +1,358 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/xsync"
+)
+
+// Builder builds a Cache.
+type Builder[K comparable, V any] struct {
+	capacity int
+	policy   Policy
+
+	ttl             time.Duration
+	cleanupInterval time.Duration
+
+	onEvict func(key K, val V, reason EvictReason)
+	loader  func(ctx context.Context, key K) (V, error)
+}
+
+// Policy sets the eviction policy, default is LRU.
+func (b *Builder[K, V]) Policy(policy Policy) *Builder[K, V] {
+	b.policy = policy
+	return b
+}
+
+// TTL sets the default time-to-live of entries, ttl <= 0 means never expire ( default ).
+func (b *Builder[K, V]) TTL(ttl time.Duration) *Builder[K, V] {
+	b.ttl = ttl
+	return b
+}
+
+// CleanupInterval enables background expiry, expired entries are deleted every interval.
+// Without background expiry, expired entries are only deleted lazily when they are accessed or evicted.
+func (b *Builder[K, V]) CleanupInterval(interval time.Duration) *Builder[K, V] {
+	b.cleanupInterval = interval
+	return b
+}
+
+// OnEvict sets the callback invoked after an entry is removed from the cache.
+// The callback is invoked outside the lock of the cache, so it is free to access the cache.
+func (b *Builder[K, V]) OnEvict(onEvict func(key K, val V, reason EvictReason)) *Builder[K, V] {
+	b.onEvict = onEvict
+	return b
+}
+
+// Loader sets the function used by GetOrLoad to load the missing value.
+func (b *Builder[K, V]) Loader(loader func(ctx context.Context, key K) (V, error)) *Builder[K, V] {
+	b.loader = loader
+	return b
+}
+
+func (b *Builder[K, V]) Build() (*Cache[K, V], error) {
+	if b.capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	c := &Cache[K, V]{
+		capacity: b.capacity,
+		entries:  make(map[K]*entry[K, V], b.capacity),
+		evictor:  newEvictor[K, V](b.policy),
+		pool:     xsync.NewPool[*entry[K, V]](func() *entry[K, V] { return &entry[K, V]{} }),
+		ttl:      b.ttl,
+		onEvict:  b.onEvict,
+		loader:   b.loader,
+		group:    newFlightGroup[K, V](),
+		now:      time.Now,
+		closeCh:  make(chan struct{}),
+	}
+
+	if b.cleanupInterval > 0 {
+		go c.cleanup(b.cleanupInterval)
+	}
+	return c, nil
+}
+
+// NewBuilder creates a cache builder, capacity is the max number of entries.
+func NewBuilder[K comparable, V any](capacity int) *Builder[K, V] {
+	return &Builder[K, V]{
+		capacity: capacity,
+		policy:   LRU,
+	}
+}
+
+// Cache is a thread-safe bounded cache.
+// When the cache is full, an entry is evicted according to the eviction policy before adding a new one.
+type Cache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	capacity int
+	entries  map[K]*entry[K, V]
+	evictor  evictor[K, V]
+	pool     *xsync.Pool[*entry[K, V]]
+
+	ttl     time.Duration
+	onEvict func(key K, val V, reason EvictReason)
+
+	loader func(ctx context.Context, key K) (V, error)
+	group  *flightGroup[K, V]
+
+	hits        atomic.Int64
+	misses      atomic.Int64
+	evictions   atomic.Int64
+	expirations atomic.Int64
+	loads       atomic.Int64
+	loadErrors  atomic.Int64
+
+	now func() time.Time
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// evicted is a removed entry waiting for the eviction callback.
+type evicted[K comparable, V any] struct {
+	key    K
+	val    V
+	reason EvictReason
+}
+
+// Get returns the value of the key, expired entry is deleted and treated as missing.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+
+	if e.expired(c.now().UnixNano()) {
+		removed := c.remove(e, EvictReasonExpired, nil)
+		c.mu.Unlock()
+
+		c.misses.Add(1)
+		c.notify(removed)
+		var zero V
+		return zero, false
+	}
+
+	c.evictor.access(e)
+	val := e.val
+	c.mu.Unlock()
+
+	c.hits.Add(1)
+	return val, true
+}
+
+// Put stores the key-value pair with the default ttl.
+func (c *Cache[K, V]) Put(key K, val V) {
+	c.PutWithTTL(key, val, c.ttl)
+}
+
+// PutWithTTL stores the key-value pair with the given ttl, ttl <= 0 means never expire.
+func (c *Cache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = c.now().Add(ttl).UnixNano()
+	}
+
+	c.mu.Lock()
+
+	if e, ok := c.entries[key]; ok {
+		e.val = val
+		e.expireAt = expireAt
+		c.evictor.access(e)
+		c.mu.Unlock()
+		return
+	}
+
+	var removed []evicted[K, V]
+	for len(c.entries) >= c.capacity {
+		victim := c.evictor.victim()
+
+		reason := EvictReasonCapacity
+		if victim.expired(c.now().UnixNano()) {
+			reason = EvictReasonExpired
+		}
+		removed = c.remove(victim, reason, removed)
+	}
+
+	e := c.pool.Get()
+	e.key = key
+	e.val = val
+	e.expireAt = expireAt
+
+	c.entries[key] = e
+	c.evictor.add(e)
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// Del deletes the key and returns its value, expired entry is deleted and treated as missing.
+func (c *Cache[K, V]) Del(key K) (V, bool) {
+	c.mu.Lock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+
+		var zero V
+		return zero, false
+	}
+
+	val := e.val
+	expired := e.expired(c.now().UnixNano())
+
+	reason := EvictReasonDeleted
+	if expired {
+		reason = EvictReasonExpired
+	}
+	removed := c.remove(e, reason, nil)
+	c.mu.Unlock()
+
+	c.notify(removed)
+	if expired {
+		var zero V
+		return zero, false
+	}
+	return val, true
+}
+
+// GetOrLoad returns the value of the key, loads and stores it with the loader if missing.
+// Concurrent loads of the same key are deduplicated, only one loader call is in-flight for a key at a time.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K) (V, error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+
+	if c.loader == nil {
+		var zero V
+		return zero, ErrNilLoader
+	}
+
+	val, _, err := c.group.do(ctx, key, func() (V, error) {
+		val, err := c.loader(ctx, key)
+		if err != nil {
+			c.loadErrors.Add(1)
+			return val, err
+		}
+
+		c.loads.Add(1)
+		c.Put(key, val)
+		return val, nil
+	})
+	return val, err
+}
+
+// Len returns the number of entries, including the expired entries not deleted yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Clear deletes all the entries.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+
+	var removed []evicted[K, V]
+	for _, e := range c.entries {
+		removed = c.remove(e, EvictReasonDeleted, removed)
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// DeleteExpired deletes all the expired entries and returns the number of deleted entries.
+func (c *Cache[K, V]) DeleteExpired() int {
+	now := c.now().UnixNano()
+
+	c.mu.Lock()
+
+	var removed []evicted[K, V]
+	cnt := 0
+	for _, e := range c.entries {
+		if e.expired(now) {
+			removed = c.remove(e, EvictReasonExpired, removed)
+			cnt++
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+	return cnt
+}
+
+// Stats returns a snapshot of the statistics.
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Loads:       c.loads.Load(),
+		LoadErrors:  c.loadErrors.Load(),
+	}
+}
+
+// Close stops the background expiry, it is safe to call Close multiple times.
+// The cache is still usable after closed.
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+}
+
+func (c *Cache[K, V]) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// remove removes the entry and puts it back to the pool, the caller must hold the lock.
+// The removed key-value pair is appended to removed if the eviction callback is set.
+func (c *Cache[K, V]) remove(e *entry[K, V], reason EvictReason, removed []evicted[K, V]) []evicted[K, V] {
+	delete(c.entries, e.key)
+	c.evictor.remove(e)
+
+	switch reason {
+	case EvictReasonCapacity:
+		c.evictions.Add(1)
+	case EvictReasonExpired:
+		c.expirations.Add(1)
+	default:
+	}
+
+	if c.onEvict != nil {
+		removed = append(removed, evicted[K, V]{key: e.key, val: e.val, reason: reason})
+	}
+
+	e.reset()
+	c.pool.Put(e)
+	return removed
+}
+
+// notify invokes the eviction callback, the caller must not hold the lock.
+func (c *Cache[K, V]) notify(removed []evicted[K, V]) {
+	for _, r := range removed {
+		c.onEvict(r.key, r.val, r.reason)
+	}
+}
@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type evictRecord struct {
+	key    string
+	val    int
+	reason EvictReason
+}
+
+// fakeNow is a manually advanced clock for ttl tests.
+type fakeNow struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeNow) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeNow) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func newTestCache(t *testing.T, b *Builder[string, int]) (*Cache[string, int], *fakeNow, *[]evictRecord) {
+	records := make([]evictRecord, 0)
+
+	var mu sync.Mutex
+	c, err := b.OnEvict(func(key string, val int, reason EvictReason) {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, evictRecord{key: key, val: val, reason: reason})
+	}).Build()
+	require.NoError(t, err)
+
+	clock := &fakeNow{now: time.Unix(0, 0)}
+	c.now = clock.Now
+	return c, clock, &records
+}
+
+func TestNewBuilder(t *testing.T) {
+	tcs := []struct {
+		name     string
+		capacity int
+		wantErr  error
+	}{
+		{name: "basic", capacity: 16},
+		{name: "zero capacity", capacity: 0, wantErr: ErrInvalidCapacity},
+		{name: "negative capacity", capacity: -1, wantErr: ErrInvalidCapacity},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewBuilder[string, int](tc.capacity).Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, 0, c.Len())
+		})
+	}
+}
+
+func TestCache_Basic(t *testing.T) {
+	c, _, records := newTestCache(t, NewBuilder[string, int](4))
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("a", 10)
+	assert.Equal(t, 2, c.Len())
+
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+
+	val, ok = c.Del("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+	_, ok = c.Del("b")
+	assert.False(t, ok)
+
+	c.Put("c", 3)
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+
+	assert.ElementsMatch(t, []evictRecord{
+		{key: "b", val: 2, reason: EvictReasonDeleted},
+		{key: "a", val: 10, reason: EvictReasonDeleted},
+		{key: "c", val: 3, reason: EvictReasonDeleted},
+	}, *records)
+	assert.Equal(t, Stats{Hits: 1, Misses: 1}, c.Stats())
+}
+
+func TestCache_Evict(t *testing.T) {
+	tcs := []struct {
+		name        string
+		policy      Policy
+		wantEvicted []string
+	}{
+		{name: "lru", policy: LRU, wantEvicted: []string{"c", "b"}},
+		{name: "lfu", policy: LFU, wantEvicted: []string{"c", "d"}},
+		{name: "fifo", policy: FIFO, wantEvicted: []string{"a", "b"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _, records := newTestCache(t, NewBuilder[string, int](3).Policy(tc.policy))
+
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+
+			_, _ = c.Get("a")
+			_, _ = c.Get("b")
+			_, _ = c.Get("a")
+
+			c.Put("d", 4)
+			c.Put("e", 5)
+			assert.Equal(t, 3, c.Len())
+
+			evictedKeys := make([]string, 0, len(*records))
+			for _, r := range *records {
+				assert.Equal(t, EvictReasonCapacity, r.reason)
+				evictedKeys = append(evictedKeys, r.key)
+			}
+			assert.Equal(t, tc.wantEvicted, evictedKeys)
+			assert.Equal(t, int64(2), c.Stats().Evictions)
+		})
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, clock, records := newTestCache(t, NewBuilder[string, int](3).TTL(time.Minute))
+
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Hour)
+	c.PutWithTTL("c", 3, 0)
+
+	clock.Advance(time.Minute)
+
+	// lazy expiry
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	val, ok := c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	// update refreshes ttl
+	c.PutWithTTL("b", 20, time.Minute)
+	clock.Advance(time.Hour)
+
+	_, ok = c.Del("b")
+	assert.False(t, ok)
+
+	val, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, val)
+
+	assert.Equal(t, []evictRecord{
+		{key: "a", val: 1, reason: EvictReasonExpired},
+		{key: "b", val: 20, reason: EvictReasonExpired},
+	}, *records)
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, Expirations: 2}, c.Stats())
+}
+
+func TestCache_DeleteExpired(t *testing.T) {
+	c, clock, records := newTestCache(t, NewBuilder[string, int](4).TTL(time.Minute))
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.PutWithTTL("c", 3, time.Hour)
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 2, c.DeleteExpired())
+	assert.Equal(t, 1, c.Len())
+
+	assert.ElementsMatch(t, []evictRecord{
+		{key: "a", val: 1, reason: EvictReasonExpired},
+		{key: "b", val: 2, reason: EvictReasonExpired},
+	}, *records)
+
+	// expired victim is reported as expired
+	c.Put("d", 4)
+	c.Put("e", 5)
+	c.Put("f", 6)
+	clock.Advance(time.Hour)
+	c.Put("g", 7)
+	assert.Equal(t, evictRecord{key: "c", val: 3, reason: EvictReasonExpired}, (*records)[2])
+}
+
+func TestCache_BackgroundExpiry(t *testing.T) {
+	var expiredCnt atomic.Int32
+	c, err := NewBuilder[string, int](4).
+		TTL(time.Millisecond).
+		CleanupInterval(5 * time.Millisecond).
+		OnEvict(func(string, int, EvictReason) { expiredCnt.Add(1) }).
+		Build()
+	require.NoError(t, err)
+	defer c.Close()
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	assert.Eventually(t, func() bool {
+		return expiredCnt.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 0, c.Len())
+
+	// close twice
+	c.Close()
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	errLoad := errors.New("load error")
+
+	c, err := NewBuilder[string, int](4).
+		Loader(func(ctx context.Context, key string) (int, error) {
+			if key == "err" {
+				return 0, errLoad
+			}
+			return len(key), nil
+		}).
+		Build()
+	require.NoError(t, err)
+
+	val, err := c.GetOrLoad(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	// loaded value is cached
+	val, err = c.GetOrLoad(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, 3, val)
+
+	_, err = c.GetOrLoad(context.Background(), "err")
+	assert.Equal(t, errLoad, err)
+	_, ok := c.Get("err")
+	assert.False(t, ok)
+
+	assert.Equal(t, Stats{Hits: 1, Misses: 3, Loads: 1, LoadErrors: 1}, c.Stats())
+
+	// without loader
+	c, err = NewBuilder[string, int](4).Build()
+	require.NoError(t, err)
+	_, err = c.GetOrLoad(context.Background(), "abc")
+	assert.Equal(t, ErrNilLoader, err)
+}
+
+func TestCache_GetOrLoad_SingleFlight(t *testing.T) {
+	const goroutineCnt = 32
+
+	var loadCnt atomic.Int32
+	release := make(chan struct{})
+
+	c, err := NewBuilder[string, int](4).
+		Loader(func(ctx context.Context, key string) (int, error) {
+			loadCnt.Add(1)
+			<-release
+			return 1, nil
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var started sync.WaitGroup
+	for range goroutineCnt {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+
+			val, err := c.GetOrLoad(context.Background(), "key")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, val)
+		}()
+	}
+	started.Wait()
+
+	// waiting caller gives up when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetOrLoad(ctx, "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+
+	// duplicate loads may only happen if a goroutine arrives after the in-flight load finished,
+	// by then the value is cached, so the loader is called exactly once.
+	assert.Equal(t, int32(1), loadCnt.Load())
+}
+
+func TestCache_Parallel(t *testing.T) {
+	const (
+		goroutineCnt = 16
+		loopCnt      = 2000
+		capacity     = 64
+	)
+
+	for _, policy := range []Policy{LRU, LFU, FIFO} {
+		t.Run(policy.String(), func(t *testing.T) {
+			var evictedCnt atomic.Int64
+			c, err := NewBuilder[int, int](capacity).
+				Policy(policy).
+				OnEvict(func(int, int, EvictReason) { evictedCnt.Add(1) }).
+				Build()
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for g := range goroutineCnt {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := range loopCnt {
+						key := (g*loopCnt + i) % (4 * capacity)
+						switch i % 4 {
+						case 0:
+							c.Put(key, i)
+						case 1:
+							_, _ = c.Del(key)
+						default:
+							_, _ = c.Get(key)
+						}
+						assert.LessOrEqual(t, c.Len(), capacity)
+					}
+				}()
+			}
+			wg.Wait()
+
+			stats := c.Stats()
+			assert.Equal(t, int64(goroutineCnt*loopCnt/2), stats.Hits+stats.Misses)
+			assert.GreaterOrEqual(t, evictedCnt.Load(), stats.Evictions)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+// entry is the node of the cache.
+// It is linked to a doubly linked list with sentinel nodes, the same design as xlist.LinkedList.
+type entry[K comparable, V any] struct {
+	key K
+	val V
+
+	expireAt int64 // unix nano, 0 means never expire
+
+	bucket *freqBucket[K, V] // the frequency bucket which the entry belongs to, LFU only
+
+	prev *entry[K, V]
+	next *entry[K, V]
+}
+
+func (e *entry[K, V]) expired(now int64) bool {
+	return e.expireAt > 0 && e.expireAt <= now
+}
+
+func (e *entry[K, V]) reset() {
+	var key K
+	var val V
+
+	e.key = key
+	e.val = val
+	e.expireAt = 0
+	e.bucket = nil
+	e.prev, e.next = nil, nil
+}
+
+// entryList is a doubly linked list of entries, front is the oldest.
+type entryList[K comparable, V any] struct {
+	head *entry[K, V]
+	tail *entry[K, V]
+	size int
+}
+
+func (l *entryList[K, V]) pushBack(e *entry[K, V]) {
+	e.prev, e.next = l.tail.prev, l.tail
+	e.prev.next, e.next.prev = e, e
+	l.size++
+}
+
+func (l *entryList[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+	l.size--
+}
+
+func (l *entryList[K, V]) moveToBack(e *entry[K, V]) {
+	l.remove(e)
+	l.pushBack(e)
+}
+
+// front returns the oldest entry, nil if the list is empty.
+func (l *entryList[K, V]) front() *entry[K, V] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.head.next
+}
+
+func newEntryList[K comparable, V any]() *entryList[K, V] {
+	head := &entry[K, V]{}
+	tail := &entry[K, V]{prev: head}
+	head.next = tail
+
+	return &entryList[K, V]{head: head, tail: tail}
+}
@@ -0,0 +1,155 @@
+package cache
+
+// evictor keeps the eviction order of entries.
+// evictor is not thread-safe, it is guarded by the lock of the cache.
+type evictor[K comparable, V any] interface {
+	// add adds a new entry.
+	add(e *entry[K, V])
+	// access records an access (hit or update) of the entry.
+	access(e *entry[K, V])
+	// remove removes the entry.
+	remove(e *entry[K, V])
+	// victim returns the next entry to evict without removing it, nil if there is no entry.
+	victim() *entry[K, V]
+}
+
+func newEvictor[K comparable, V any](policy Policy) evictor[K, V] {
+	switch policy {
+	case LFU:
+		return newLfuEvictor[K, V]()
+	case FIFO:
+		return &fifoEvictor[K, V]{l: newEntryList[K, V]()}
+	default:
+		return &lruEvictor[K, V]{l: newEntryList[K, V]()}
+	}
+}
+
+var _ evictor[int, any] = (*lruEvictor[int, any])(nil)
+
+type lruEvictor[K comparable, V any] struct {
+	l *entryList[K, V]
+}
+
+func (e *lruEvictor[K, V]) add(en *entry[K, V]) {
+	e.l.pushBack(en)
+}
+
+func (e *lruEvictor[K, V]) access(en *entry[K, V]) {
+	e.l.moveToBack(en)
+}
+
+func (e *lruEvictor[K, V]) remove(en *entry[K, V]) {
+	e.l.remove(en)
+}
+
+func (e *lruEvictor[K, V]) victim() *entry[K, V] {
+	return e.l.front()
+}
+
+var _ evictor[int, any] = (*fifoEvictor[int, any])(nil)
+
+type fifoEvictor[K comparable, V any] struct {
+	l *entryList[K, V]
+}
+
+func (e *fifoEvictor[K, V]) add(en *entry[K, V]) {
+	e.l.pushBack(en)
+}
+
+// access does not change the order, entries are always evicted in insertion order.
+func (e *fifoEvictor[K, V]) access(*entry[K, V]) {}
+
+func (e *fifoEvictor[K, V]) remove(en *entry[K, V]) {
+	e.l.remove(en)
+}
+
+func (e *fifoEvictor[K, V]) victim() *entry[K, V] {
+	return e.l.front()
+}
+
+// freqBucket holds the entries with the same access frequency.
+// Buckets are linked in ascending frequency order, so that all operations of lfuEvictor are O(1).
+type freqBucket[K comparable, V any] struct {
+	freq    int64
+	entries *entryList[K, V]
+
+	prev *freqBucket[K, V]
+	next *freqBucket[K, V]
+}
+
+var _ evictor[int, any] = (*lfuEvictor[int, any])(nil)
+
+type lfuEvictor[K comparable, V any] struct {
+	// sentinel nodes of the bucket list
+	head *freqBucket[K, V]
+	tail *freqBucket[K, V]
+}
+
+func (e *lfuEvictor[K, V]) add(en *entry[K, V]) {
+	b := e.head.next
+	if b == e.tail || b.freq != 1 {
+		b = e.insertBucketAfter(e.head, 1)
+	}
+
+	en.bucket = b
+	b.entries.pushBack(en)
+}
+
+func (e *lfuEvictor[K, V]) access(en *entry[K, V]) {
+	curr := en.bucket
+
+	next := curr.next
+	if next == e.tail || next.freq != curr.freq+1 {
+		next = e.insertBucketAfter(curr, curr.freq+1)
+	}
+
+	curr.entries.remove(en)
+	en.bucket = next
+	next.entries.pushBack(en)
+
+	e.removeIfEmpty(curr)
+}
+
+func (e *lfuEvictor[K, V]) remove(en *entry[K, V]) {
+	b := en.bucket
+	b.entries.remove(en)
+	en.bucket = nil
+
+	e.removeIfEmpty(b)
+}
+
+func (e *lfuEvictor[K, V]) victim() *entry[K, V] {
+	if e.head.next == e.tail {
+		return nil
+	}
+	return e.head.next.entries.front()
+}
+
+func (e *lfuEvictor[K, V]) insertBucketAfter(b *freqBucket[K, V], freq int64) *freqBucket[K, V] {
+	newB := &freqBucket[K, V]{
+		freq:    freq,
+		entries: newEntryList[K, V](),
+		prev:    b,
+		next:    b.next,
+	}
+	newB.prev.next, newB.next.prev = newB, newB
+	return newB
+}
+
+func (e *lfuEvictor[K, V]) removeIfEmpty(b *freqBucket[K, V]) {
+	if b.entries.size != 0 {
+		return
+	}
+
+	b.prev.next = b.next
+	b.next.prev = b.prev
+	b.prev, b.next = nil, nil
+}
+
+func newLfuEvictor[K comparable, V any]() *lfuEvictor[K, V] {
+	head := &freqBucket[K, V]{}
+	tail := &freqBucket[K, V]{prev: head}
+	head.next = tail
+
+	return &lfuEvictor[K, V]{head: head, tail: tail}
+}
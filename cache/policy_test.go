@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// victims pops all the entries in eviction order.
+func victims(ev evictor[int, int]) []int {
+	res := make([]int, 0)
+	for e := ev.victim(); e != nil; e = ev.victim() {
+		res = append(res, e.key)
+		ev.remove(e)
+	}
+	return res
+}
+
+func TestEvictor(t *testing.T) {
+	tcs := []struct {
+		name   string
+		policy Policy
+		// access the entries in order after adding 0, 1, 2, 3
+		accessKeys []int
+		// remove the entries after accessing
+		removeKeys  []int
+		wantVictims []int
+	}{
+		{
+			name:        "lru without access",
+			policy:      LRU,
+			wantVictims: []int{0, 1, 2, 3},
+		}, {
+			name:        "lru",
+			policy:      LRU,
+			accessKeys:  []int{0, 2, 1},
+			wantVictims: []int{3, 0, 2, 1},
+		}, {
+			name:        "lru with remove",
+			policy:      LRU,
+			accessKeys:  []int{0},
+			removeKeys:  []int{2},
+			wantVictims: []int{1, 3, 0},
+		}, {
+			name:        "fifo",
+			policy:      FIFO,
+			accessKeys:  []int{0, 2, 1},
+			wantVictims: []int{0, 1, 2, 3},
+		}, {
+			name:        "lfu",
+			policy:      LFU,
+			accessKeys:  []int{0, 0, 2, 3, 3, 3},
+			wantVictims: []int{1, 2, 0, 3},
+		}, {
+			name:   "lfu same frequency in lru order",
+			policy: LFU,
+			// frequencies: 0 -> 2, 1 -> 2, 2 -> 2, 3 -> 1
+			accessKeys:  []int{2, 1, 0},
+			wantVictims: []int{3, 2, 1, 0},
+		}, {
+			name:        "lfu with remove",
+			policy:      LFU,
+			accessKeys:  []int{1, 1, 2},
+			removeKeys:  []int{0, 2},
+			wantVictims: []int{3, 1},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ev := newEvictor[int, int](tc.policy)
+
+			entries := make(map[int]*entry[int, int])
+			for i := range 4 {
+				e := &entry[int, int]{key: i}
+				entries[i] = e
+				ev.add(e)
+			}
+
+			for _, key := range tc.accessKeys {
+				ev.access(entries[key])
+			}
+			for _, key := range tc.removeKeys {
+				ev.remove(entries[key])
+			}
+
+			assert.Equal(t, tc.wantVictims, victims(ev))
+			assert.Nil(t, ev.victim())
+		})
+	}
+}
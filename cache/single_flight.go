@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errLoaderPanic = errors.New("[jit] cache loader panicked")
+
+// call is an in-flight or completed load.
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// flightGroup deduplicates the concurrent loads of the same key.
+type flightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// do executes loadFunc for the key, only one execution is in-flight for a key at a time.
+// Duplicate callers wait for the result of the in-flight execution until ctx is done.
+// shared reports whether the result is shared with other callers.
+func (g *flightGroup[K, V]) do(ctx context.Context, key K, loadFunc func() (V, error)) (val V, shared bool, err error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.val, true, c.err
+		case <-ctx.Done():
+			var zero V
+			return zero, true, ctx.Err()
+		}
+	}
+
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	returned := false
+	defer func() {
+		if !returned {
+			// loadFunc panicked, the panic goes on in the current goroutine,
+			// and the waiting callers get an error instead of a zero value.
+			c.err = errLoaderPanic
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.val, c.err = loadFunc()
+	returned = true
+	return c.val, false, c.err
+}
+
+func newFlightGroup[K comparable, V any]() *flightGroup[K, V] {
+	return &flightGroup[K, V]{calls: make(map[K]*call[V])}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCapacity = errors.New("[jit] cache capacity should be greater than 0")
+	ErrNilLoader       = errors.New("[jit] cache loader is nil")
+)
+
+// Policy is the eviction policy used when the cache is full.
+type Policy uint8
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry,
+	// entries with the same frequency are evicted in least recently used order.
+	LFU
+	// FIFO evicts the earliest inserted entry.
+	FIFO
+)
+
+func (p Policy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case FIFO:
+		return "FIFO"
+	default:
+		return fmt.Sprintf("Policy(%d)", p)
+	}
+}
+
+// EvictReason tells why an entry is removed from the cache.
+type EvictReason uint8
+
+const (
+	// EvictReasonCapacity means the entry is evicted by the policy to make room for a new entry.
+	EvictReasonCapacity EvictReason = iota
+	// EvictReasonExpired means the entry is expired.
+	EvictReasonExpired
+	// EvictReasonDeleted means the entry is deleted by Del or Clear.
+	EvictReasonDeleted
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictReasonCapacity:
+		return "capacity"
+	case EvictReasonExpired:
+		return "expired"
+	case EvictReasonDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("EvictReason(%d)", r)
+	}
+}
+
+// Stats is a snapshot of the cache statistics.
+type Stats struct {
+	Hits   int64
+	Misses int64
+
+	Evictions   int64 // entries evicted by the policy
+	Expirations int64 // entries removed due to expiration
+
+	Loads      int64 // successful loader calls
+	LoadErrors int64 // failed loader calls
+}
+
+// HitRate returns hits / (hits + misses), 0 if there is no request.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
@@ -0,0 +1,59 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout is like context.WithTimeout but the timeout is measured by c.
+func WithTimeout(parent context.Context, c Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, c, c.Now().Add(timeout))
+}
+
+// WithDeadline is like context.WithDeadline but the deadline is measured by c.
+//
+// For the real clock it simply calls context.WithDeadline.
+// For other clocks the returned context is canceled by a Timer of c,
+// its Err returns context.DeadlineExceeded once the deadline is reached.
+func WithDeadline(parent context.Context, c Clock, deadline time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := c.(realClock); ok {
+		return context.WithDeadline(parent, deadline)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	dc := &deadlineCtx{Context: ctx, deadline: deadline}
+
+	timer := c.NewTimer(deadline.Sub(c.Now()))
+	go func() {
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+
+	// stop the timer synchronously so that the waiter is released once cancel returns
+	return dc, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// deadlineCtx reports the deadline of WithDeadline and translates the cancel cause into Err.
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *deadlineCtx) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
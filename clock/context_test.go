@@ -0,0 +1,68 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+	ctx, cancel := WithTimeout(context.Background(), c, time.Second)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, epoch.Add(time.Second), deadline)
+
+	require.NoError(t, c.BlockUntil(context.Background(), 1))
+	c.Advance(999 * time.Millisecond)
+	assert.NoError(t, ctx.Err())
+
+	c.Advance(time.Millisecond)
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+}
+
+func TestWithDeadline_Cancel(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+
+	ctx, cancel := WithDeadline(context.Background(), c, epoch.Add(time.Second))
+	cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	// the timer is stopped once cancel returns
+	assert.Equal(t, 0, c.Waiters())
+
+	// canceled by the parent
+	parent, parentCancel := context.WithCancel(context.Background())
+	ctx, cancel = WithDeadline(parent, c, epoch.Add(time.Second))
+	defer cancel()
+	parentCancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	// a deadline in the past expires immediately
+	ctx, cancel = WithDeadline(context.Background(), c, epoch.Add(-time.Second))
+	defer cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+func TestWithTimeout_Real(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := WithTimeout(context.Background(), Real(), time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
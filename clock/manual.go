@@ -0,0 +1,210 @@
+package clock
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ Clock = (*Manual)(nil)
+
+// Manual is a Clock that only moves when Advance or Set is called.
+// Timers and tickers created by Manual fire synchronously inside Advance and Set,
+// in the order of their fire time, so tests do not depend on real sleeps.
+type Manual struct {
+	mu sync.Mutex
+
+	now     time.Time
+	waiters []*manualWaiter // active timers and tickers
+
+	changed chan struct{} // closed and replaced whenever waiters changes
+}
+
+// Now returns the current time of the clock.
+func (c *Manual) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed since t according to the clock.
+func (c *Manual) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer creates a Timer that fires once the clock has been advanced by d.
+// A Timer with d <= 0 fires immediately.
+func (c *Manual) NewTimer(d time.Duration) Timer {
+	w := &manualWaiter{c: c, ch: make(chan time.Time, 1)}
+	w.Reset(d)
+	return w
+}
+
+// NewTicker creates a Ticker that fires every time the clock has been advanced by d.
+// Like time.Ticker, ticks are dropped when the receiver falls behind.
+func (c *Manual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	w := &manualTicker{manualWaiter{c: c, ch: make(chan time.Time, 1), period: d}}
+	w.Reset(d)
+	return w
+}
+
+// Advance moves the clock forward by d and fires every timer and ticker that is due.
+func (c *Manual) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set moves the clock to t and fires every timer and ticker that is due.
+func (c *Manual) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(t)
+}
+
+func (c *Manual) setLocked(t time.Time) {
+	for {
+		var next *manualWaiter
+		for _, w := range c.waiters {
+			if !w.at.After(t) && (next == nil || w.at.Before(next.at)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		if next.at.After(c.now) {
+			c.now = next.at
+		}
+		next.fire()
+
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+			continue
+		}
+		c.removeLocked(next)
+	}
+	c.now = t
+}
+
+// Waiters returns the number of active timers and tickers.
+func (c *Manual) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until there are at least n active timers and tickers or ctx is done.
+// It is typically used to wait for the code under test to start waiting before advancing the clock.
+func (c *Manual) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			c.mu.Unlock()
+			return nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Manual) addLocked(w *manualWaiter) {
+	c.waiters = append(c.waiters, w)
+	c.notifyLocked()
+}
+
+func (c *Manual) removeLocked(w *manualWaiter) bool {
+	idx := slices.Index(c.waiters, w)
+	if idx < 0 {
+		return false
+	}
+	c.waiters = slices.Delete(c.waiters, idx, idx+1)
+	c.notifyLocked()
+	return true
+}
+
+func (c *Manual) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// NewManual creates a Manual clock starting at now.
+func NewManual(now time.Time) *Manual {
+	return &Manual{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+// manualWaiter is a timer or ticker of Manual.
+type manualWaiter struct {
+	c *Manual
+
+	ch     chan time.Time
+	at     time.Time     // next fire time
+	period time.Duration // 0 for timers
+}
+
+func (w *manualWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+// fire sends the fire time without blocking, the caller must hold the clock lock.
+func (w *manualWaiter) fire() {
+	select {
+	case w.ch <- w.at:
+	default:
+	}
+}
+
+func (w *manualWaiter) Stop() bool {
+	w.c.mu.Lock()
+	defer w.c.mu.Unlock()
+	return w.c.removeLocked(w)
+}
+
+func (w *manualWaiter) Reset(d time.Duration) bool {
+	w.c.mu.Lock()
+	defer w.c.mu.Unlock()
+
+	active := w.c.removeLocked(w)
+	w.at = w.c.now.Add(d)
+	if d <= 0 && w.period == 0 {
+		w.fire()
+		return active
+	}
+	w.c.addLocked(w)
+	return active
+}
+
+// manualTicker adapts manualWaiter to the Ticker interface.
+type manualTicker struct {
+	manualWaiter
+}
+
+func (t *manualTicker) Stop() {
+	t.manualWaiter.Stop()
+}
+
+func (t *manualTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+
+	t.c.mu.Lock()
+	t.period = d
+	t.c.mu.Unlock()
+	t.manualWaiter.Reset(d)
+}
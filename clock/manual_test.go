@@ -0,0 +1,142 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestManual_Timer(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+	timer := c.NewTimer(time.Second)
+	assert.Equal(t, 1, c.Waiters())
+
+	c.Advance(999 * time.Millisecond)
+	_, ok := fired(timer.C())
+	assert.False(t, ok)
+
+	c.Advance(time.Millisecond)
+	at, ok := fired(timer.C())
+	require.True(t, ok)
+	assert.Equal(t, epoch.Add(time.Second), at)
+	assert.Equal(t, 0, c.Waiters())
+	assert.False(t, timer.Stop())
+
+	// reset a fired timer
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Reset(2*time.Second))
+	c.Advance(time.Second)
+	_, ok = fired(timer.C())
+	assert.False(t, ok)
+	assert.True(t, timer.Stop())
+	c.Advance(time.Hour)
+	_, ok = fired(timer.C())
+	assert.False(t, ok)
+
+	// non-positive duration fires immediately
+	timer = c.NewTimer(0)
+	at, ok = fired(timer.C())
+	require.True(t, ok)
+	assert.Equal(t, c.Now(), at)
+	assert.Equal(t, time.Hour+2*time.Second, c.Since(epoch))
+}
+
+func TestManual_Ticker(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+	ticker := c.NewTicker(time.Second)
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Second)
+		at, ok := fired(ticker.C())
+		require.True(t, ok)
+		assert.Equal(t, epoch.Add(time.Duration(i)*time.Second), at)
+	}
+
+	// ticks are dropped when the receiver falls behind
+	c.Advance(3 * time.Second)
+	at, ok := fired(ticker.C())
+	require.True(t, ok)
+	assert.Equal(t, epoch.Add(4*time.Second), at)
+	_, ok = fired(ticker.C())
+	assert.False(t, ok)
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Second)
+	_, ok = fired(ticker.C())
+	assert.False(t, ok)
+	c.Advance(time.Minute)
+	_, ok = fired(ticker.C())
+	assert.True(t, ok)
+
+	ticker.Stop()
+	assert.Equal(t, 0, c.Waiters())
+
+	assert.Panics(t, func() { c.NewTicker(0) })
+}
+
+func TestManual_FireOrder(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+	t2 := c.NewTimer(2 * time.Second)
+	t1 := c.NewTimer(time.Second)
+
+	c.Set(epoch.Add(time.Minute))
+	at1, ok := fired(t1.C())
+	require.True(t, ok)
+	at2, ok := fired(t2.C())
+	require.True(t, ok)
+	assert.True(t, at1.Before(at2))
+	assert.Equal(t, epoch.Add(time.Minute), c.Now())
+}
+
+func TestManual_BlockUntil(t *testing.T) {
+	t.Parallel()
+
+	c := NewManual(epoch)
+	go func() {
+		timer := c.NewTimer(time.Second)
+		<-timer.C()
+	}()
+
+	require.NoError(t, c.BlockUntil(context.Background(), 1))
+	c.Advance(time.Second)
+	require.NoError(t, c.BlockUntil(context.Background(), 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, c.BlockUntil(ctx, 1), context.DeadlineExceeded)
+}
+
+func TestReal(t *testing.T) {
+	t.Parallel()
+
+	c := Real()
+	start := c.Now()
+
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	assert.GreaterOrEqual(t, c.Since(start), 2*time.Millisecond)
+}
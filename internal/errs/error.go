@@ -41,3 +41,14 @@ func ErrInvalidMaxInterval(maxInterval time.Duration) error {
 func ErrRetryTimeExhausted(latestErr error) error {
 	return fmt.Errorf("[jit] retry time exhausted, the latest error: %w", latestErr)
 }
+
+func ErrInvalidJitterMode(mode int8) error {
+	return fmt.Errorf("[jit] invalid jitter mode: %d", mode)
+}
+
+func ErrInvalidRetryBudget(ratio float64, minRetries int) error {
+	return fmt.Errorf(
+		"[jit] invalid retry budget: ratio %v, min retries %d, expected both values should not be negative",
+		ratio, minRetries,
+	)
+}
@@ -1,6 +1,7 @@
 package list
 
 import (
+	"iter"
 	"math/rand/v2"
 
 	"github.com/JrMarcco/jit"
@@ -183,6 +184,17 @@ func (sl *SkipList[T]) ToSlice() []T {
 	return res
 }
 
+// All returns an iterator over index-value pairs in ascending order.
+func (sl *SkipList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for currN, index := sl.head.next[0], 0; currN != nil; currN, index = currN.next[0], index+1 {
+			if !yield(index, currN.val) {
+				return
+			}
+		}
+	}
+}
+
 func (sl *SkipList[T]) Len() int {
 	return sl.size
 }
@@ -151,6 +151,155 @@ func (rbt *RBTree[K, V]) inOrderTraversal(visitFunc func(node *rbNode[K, V]) boo
 	}
 }
 
+// IterDesc traversal all the tree node in descending order.
+//
+// If fn returns false, terminate traversal.
+func (rbt *RBTree[K, V]) IterDesc(visitFunc func(key K, val V) bool) {
+	for node := rbt.maxNode(); node != nil; node = rbt.findPredecessor(node) {
+		if !visitFunc(node.key, node.val) {
+			return
+		}
+	}
+}
+
+// IterRange traversal the tree node whose key is in [from, to) in ascending order.
+//
+// If fn returns false, terminate traversal.
+func (rbt *RBTree[K, V]) IterRange(from K, to K, visitFunc func(key K, val V) bool) {
+	for node := rbt.ceilingNode(from); node != nil && rbt.cmp(node.key, to) < 0; node = rbt.findSuccessor(node) {
+		if !visitFunc(node.key, node.val) {
+			return
+		}
+	}
+}
+
+// IterRangeDesc traversal the tree node whose key is in [from, to) in descending order.
+//
+// If fn returns false, terminate traversal.
+func (rbt *RBTree[K, V]) IterRangeDesc(from K, to K, visitFunc func(key K, val V) bool) {
+	for node := rbt.lowerNode(to); node != nil && rbt.cmp(node.key, from) >= 0; node = rbt.findPredecessor(node) {
+		if !visitFunc(node.key, node.val) {
+			return
+		}
+	}
+}
+
+// Floor returns the greatest key less than or equal to the given key.
+func (rbt *RBTree[K, V]) Floor(key K) (K, V, error) {
+	return rbt.nodeKv(rbt.floorNode(key))
+}
+
+// Ceiling returns the least key greater than or equal to the given key.
+func (rbt *RBTree[K, V]) Ceiling(key K) (K, V, error) {
+	return rbt.nodeKv(rbt.ceilingNode(key))
+}
+
+// Lower returns the greatest key strictly less than the given key.
+func (rbt *RBTree[K, V]) Lower(key K) (K, V, error) {
+	return rbt.nodeKv(rbt.lowerNode(key))
+}
+
+// Higher returns the least key strictly greater than the given key.
+func (rbt *RBTree[K, V]) Higher(key K) (K, V, error) {
+	return rbt.nodeKv(rbt.higherNode(key))
+}
+
+// First returns the least key of the tree.
+func (rbt *RBTree[K, V]) First() (K, V, error) {
+	return rbt.nodeKv(rbt.minNode())
+}
+
+// Last returns the greatest key of the tree.
+func (rbt *RBTree[K, V]) Last() (K, V, error) {
+	return rbt.nodeKv(rbt.maxNode())
+}
+
+// PollFirst returns and deletes the least key of the tree.
+func (rbt *RBTree[K, V]) PollFirst() (K, V, error) {
+	return rbt.pollNode(rbt.minNode())
+}
+
+// PollLast returns and deletes the greatest key of the tree.
+func (rbt *RBTree[K, V]) PollLast() (K, V, error) {
+	return rbt.pollNode(rbt.maxNode())
+}
+
+// Rank returns the number of keys strictly less than the given key.
+// If the key exists, the rank is its 0-based index in ascending order.
+func (rbt *RBTree[K, V]) Rank(key K) int64 {
+	var rank int64
+
+	node := rbt.root
+	for node != nil {
+		cmp := rbt.cmp(key, node.key)
+		if cmp == 0 {
+			return rank + node.left.getSize()
+		}
+
+		if cmp < 0 {
+			node = node.left
+		} else {
+			// the left subtree and the node itself are less than the key
+			rank += node.left.getSize() + 1
+			node = node.right
+		}
+	}
+
+	return rank
+}
+
+// Select returns the key-value pair at the given 0-based index in ascending order.
+func (rbt *RBTree[K, V]) Select(index int64) (K, V, error) {
+	if index < 0 || index >= rbt.size {
+		return rbt.nodeKv(nil)
+	}
+
+	node := rbt.root
+	for node != nil {
+		leftSize := node.left.getSize()
+		if index == leftSize {
+			break
+		}
+
+		if index < leftSize {
+			node = node.left
+		} else {
+			index -= leftSize + 1
+			node = node.right
+		}
+	}
+
+	return rbt.nodeKv(node)
+}
+
+// CountRange returns the number of keys in [lo, hi).
+func (rbt *RBTree[K, V]) CountRange(lo K, hi K) int64 {
+	if rbt.cmp(lo, hi) >= 0 {
+		return 0
+	}
+	return rbt.Rank(hi) - rbt.Rank(lo)
+}
+
+func (rbt *RBTree[K, V]) nodeKv(node *rbNode[K, V]) (K, V, error) {
+	if node == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, errs.ErrNodeNotFound
+	}
+	return node.key, node.val, nil
+}
+
+func (rbt *RBTree[K, V]) pollNode(node *rbNode[K, V]) (K, V, error) {
+	key, val, err := rbt.nodeKv(node)
+	if err != nil {
+		return key, val, err
+	}
+
+	rbt.deleteNode(node)
+	rbt.size--
+	return key, val, nil
+}
+
 // leftRotate left rotate around the node
 //
 //	     left rotate around the node x
@@ -195,6 +344,11 @@ func (rbt *RBTree[K, V]) leftRotate(x *rbNode[K, V]) {
 	y.left = x
 	// node x's parent = node y
 	x.parent = y
+
+	// node y takes over the whole subtree of node x,
+	// node x's subtree size should be recalculated.
+	y.size = x.size
+	x.updateSize()
 }
 
 // rightRotate right rotate around the node
@@ -241,6 +395,11 @@ func (rbt *RBTree[K, V]) rightRotate(x *rbNode[K, V]) {
 	y.right = x
 	// node x's parent = node y
 	x.parent = y
+
+	// node y takes over the whole subtree of node x,
+	// node x's subtree size should be recalculated.
+	y.size = x.size
+	x.updateSize()
 }
 
 // insertNode insert a new node into the tree
@@ -282,6 +441,11 @@ func (rbt *RBTree[K, V]) insertNode(node *rbNode[K, V]) error {
 		parent.right = insertedNode
 	}
 
+	// increase the subtree size of all the ancestors of the inserted node
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.parent {
+		ancestor.size++
+	}
+
 	rbt.fixupInsertion(insertedNode)
 	return nil
 }
@@ -371,7 +535,7 @@ func (rbt *RBTree[K, V]) fixupBlackUncleLeftChild(node *rbNode[K, V]) *rbNode[K,
 	node.getGrandparent().setColor(red)
 	rbt.rightRotate(node.getGrandparent())
 
-	return node.parent
+	return node
 }
 
 // fixupBlackUncleRightChild handles the case where the node's uncle is black,
@@ -463,6 +627,41 @@ func (rbt *RBTree[K, V]) findSuccessor(node *rbNode[K, V]) *rbNode[K, V] {
 	return parent
 }
 
+// findPredecessor find the predecessor of the given node.
+// It is the mirror of findSuccessor:
+//
+//  1. If the node has a left child,
+//     the predecessor is the rightmost node of the left subtree.
+//  2. If the node has no left child,
+//     backtrack up the parent node until find the first ancestor node that is the right child of its parent.
+//     The ancestor node's parent is the predecessor.
+func (rbt *RBTree[K, V]) findPredecessor(node *rbNode[K, V]) *rbNode[K, V] {
+	if node == nil {
+		return nil
+	}
+
+	if node.left != nil {
+		// if the node has a left child, the predecessor is the rightmost node of the left subtree
+		curr := node.left
+		for curr.right != nil {
+			curr = curr.right
+		}
+		return curr
+	}
+
+	// the node has no left child
+	parent := node.parent
+	curr := node
+
+	for parent != nil && curr == parent.left {
+		// keep moving up until the node is the right child of its parent
+		curr = parent
+		parent = parent.parent
+	}
+
+	return parent
+}
+
 // deleteNode delete the given node from the tree.
 func (rbt *RBTree[K, V]) deleteNode(node *rbNode[K, V]) {
 	deletedNode := node
@@ -481,6 +680,15 @@ func (rbt *RBTree[K, V]) deleteNode(node *rbNode[K, V]) {
 		deletedNode = successor
 	}
 
+	// now deletedNode will be removed from the tree,
+	// decrease the subtree size of all the ancestors of it.
+	// deletedNode's own size is set to 0 so that the rotations during deletion fixup
+	// do not count it while it is still linked to its parent.
+	for ancestor := deletedNode.parent; ancestor != nil; ancestor = ancestor.parent {
+		ancestor.size--
+	}
+	deletedNode.size = 0
+
 	var replacement *rbNode[K, V]
 
 	// now deletedNode is the successor of the original deleted node
@@ -632,6 +840,104 @@ func (rbt *RBTree[K, V]) findNode(key K) *rbNode[K, V] {
 	return nil
 }
 
+// minNode returns the leftmost node of the tree.
+func (rbt *RBTree[K, V]) minNode() *rbNode[K, V] {
+	node := rbt.root
+	for node != nil && node.left != nil {
+		node = node.left
+	}
+	return node
+}
+
+// maxNode returns the rightmost node of the tree.
+func (rbt *RBTree[K, V]) maxNode() *rbNode[K, V] {
+	node := rbt.root
+	for node != nil && node.right != nil {
+		node = node.right
+	}
+	return node
+}
+
+// floorNode returns the node with the greatest key less than or equal to the given key.
+func (rbt *RBTree[K, V]) floorNode(key K) *rbNode[K, V] {
+	var res *rbNode[K, V]
+
+	node := rbt.root
+	for node != nil {
+		cmp := rbt.cmp(key, node.key)
+		if cmp == 0 {
+			return node
+		}
+
+		if cmp < 0 {
+			node = node.left
+		} else {
+			// node is a candidate, try to find a greater one in the right subtree
+			res = node
+			node = node.right
+		}
+	}
+
+	return res
+}
+
+// ceilingNode returns the node with the least key greater than or equal to the given key.
+func (rbt *RBTree[K, V]) ceilingNode(key K) *rbNode[K, V] {
+	var res *rbNode[K, V]
+
+	node := rbt.root
+	for node != nil {
+		cmp := rbt.cmp(key, node.key)
+		if cmp == 0 {
+			return node
+		}
+
+		if cmp < 0 {
+			// node is a candidate, try to find a less one in the left subtree
+			res = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return res
+}
+
+// lowerNode returns the node with the greatest key strictly less than the given key.
+func (rbt *RBTree[K, V]) lowerNode(key K) *rbNode[K, V] {
+	var res *rbNode[K, V]
+
+	node := rbt.root
+	for node != nil {
+		if rbt.cmp(key, node.key) <= 0 {
+			node = node.left
+		} else {
+			res = node
+			node = node.right
+		}
+	}
+
+	return res
+}
+
+// higherNode returns the node with the least key strictly greater than the given key.
+func (rbt *RBTree[K, V]) higherNode(key K) *rbNode[K, V] {
+	var res *rbNode[K, V]
+
+	node := rbt.root
+	for node != nil {
+		if rbt.cmp(key, node.key) < 0 {
+			res = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return res
+}
+
 func (rbt *RBTree[K, V]) midOrderTraversal(visitFn func(node *rbNode[K, V])) {
 	stack := make([]*rbNode[K, V], 0, rbt.size)
 
@@ -667,9 +973,23 @@ type rbNode[K any, V any] struct {
 	right  *rbNode[K, V]
 	key    K
 	val    V
+	size   int64 // size of the subtree rooted at this node
 	color  color
 }
 
+// getSize returns the subtree size of the node, nil node's size is 0.
+func (rbn *rbNode[K, V]) getSize() int64 {
+	if rbn == nil {
+		return 0
+	}
+	return rbn.size
+}
+
+// updateSize recalculates the subtree size of the node from its children.
+func (rbn *rbNode[K, V]) updateSize() {
+	rbn.size = rbn.left.getSize() + rbn.right.getSize() + 1
+}
+
 func (rbn *rbNode[K, V]) getColor() color {
 	if rbn == nil {
 		return black
@@ -718,6 +1038,7 @@ func newRbNode[K any, V any](key K, val V) *rbNode[K, V] {
 	return &rbNode[K, V]{
 		key:    key,
 		val:    val,
+		size:   1,
 		color:  red,
 		parent: nil,
 		left:   nil,
@@ -1,6 +1,8 @@
 package tree
 
 import (
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/JrMarcco/jit"
@@ -57,6 +59,19 @@ func validRBNode[K any, V any](node *rbNode[K, V], cnt int, num int) bool {
 	return validRBNode(node.left, cnt, num) && validRBNode(node.right, cnt, num)
 }
 
+// validSubtreeSize checks the subtree size of every node equals the sum of its children's plus one.
+func validSubtreeSize[K any, V any](node *rbNode[K, V]) bool {
+	if node == nil {
+		return true
+	}
+
+	if node.size != node.left.getSize()+node.right.getSize()+1 {
+		return false
+	}
+
+	return validSubtreeSize(node.left) && validSubtreeSize(node.right)
+}
+
 func TestNewRBTree(t *testing.T) {
 	tcs := []struct {
 		name    string
@@ -271,6 +286,7 @@ func TestRBTree_Insert(t *testing.T) {
 
 			assert.Equal(t, tc.wantRes, validRBTree(rbt.root))
 			assert.Equal(t, tc.wantSize, rbt.Size())
+			assert.True(t, validSubtreeSize(rbt.root))
 
 			keys, vals := rbt.Kvs()
 			assert.Equal(t, tc.wantKeys, keys)
@@ -352,6 +368,7 @@ func TestRBTree_Del(t *testing.T) {
 				}
 
 				assert.Equal(t, tc.wantRes, validRBTree(rbt.root))
+				assert.True(t, validSubtreeSize(rbt.root))
 			}
 
 			vals := rbt.Vals()
@@ -451,3 +468,313 @@ func TestRBTree_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestRBTree_Navigation(t *testing.T) {
+	tcs := []struct {
+		name    string
+		keys    []int
+		navFunc func(rbt *RBTree[int, int]) (int, int, error)
+		wantKey int
+		wantVal int
+		wantErr error
+	}{
+		{
+			name:    "floor equal",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Floor(3) },
+			wantKey: 3,
+			wantVal: 3,
+		}, {
+			name:    "floor between",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Floor(4) },
+			wantKey: 3,
+			wantVal: 3,
+		}, {
+			name:    "floor not found",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Floor(0) },
+			wantErr: errs.ErrNodeNotFound,
+		}, {
+			name:    "ceiling equal",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Ceiling(5) },
+			wantKey: 5,
+			wantVal: 5,
+		}, {
+			name:    "ceiling between",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Ceiling(4) },
+			wantKey: 5,
+			wantVal: 5,
+		}, {
+			name:    "ceiling not found",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Ceiling(8) },
+			wantErr: errs.ErrNodeNotFound,
+		}, {
+			name:    "lower",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Lower(5) },
+			wantKey: 3,
+			wantVal: 3,
+		}, {
+			name:    "lower not found",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Lower(1) },
+			wantErr: errs.ErrNodeNotFound,
+		}, {
+			name:    "higher",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Higher(5) },
+			wantKey: 7,
+			wantVal: 7,
+		}, {
+			name:    "higher not found",
+			keys:    []int{1, 3, 5, 7},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Higher(7) },
+			wantErr: errs.ErrNodeNotFound,
+		}, {
+			name:    "first",
+			keys:    []int{5, 3, 7, 1},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.First() },
+			wantKey: 1,
+			wantVal: 1,
+		}, {
+			name:    "first of empty tree",
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.First() },
+			wantErr: errs.ErrNodeNotFound,
+		}, {
+			name:    "last",
+			keys:    []int{5, 3, 7, 1},
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Last() },
+			wantKey: 7,
+			wantVal: 7,
+		}, {
+			name:    "last of empty tree",
+			navFunc: func(rbt *RBTree[int, int]) (int, int, error) { return rbt.Last() },
+			wantErr: errs.ErrNodeNotFound,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rbt := NewRBTree[int, int](testCmp)
+			for _, key := range tc.keys {
+				_ = rbt.Put(key, key)
+			}
+
+			key, val, err := tc.navFunc(rbt)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantKey, key)
+			assert.Equal(t, tc.wantVal, val)
+		})
+	}
+}
+
+func TestRBTree_Poll(t *testing.T) {
+	rbt := NewRBTree[int, int](testCmp)
+	for _, key := range []int{4, 2, 6, 1, 3, 5, 7} {
+		_ = rbt.Put(key, key)
+	}
+
+	key, _, err := rbt.PollFirst()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, key)
+
+	key, _, err = rbt.PollLast()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, key)
+
+	assert.True(t, validRBTree(rbt.root))
+	assert.Equal(t, int64(5), rbt.Size())
+	assert.Equal(t, []int{2, 3, 4, 5, 6}, rbt.Keys())
+
+	for rbt.Size() > 0 {
+		_, _, err = rbt.PollFirst()
+		assert.NoError(t, err)
+	}
+
+	_, _, err = rbt.PollFirst()
+	assert.Equal(t, errs.ErrNodeNotFound, err)
+	_, _, err = rbt.PollLast()
+	assert.Equal(t, errs.ErrNodeNotFound, err)
+}
+
+func TestRBTree_IterRange(t *testing.T) {
+	tcs := []struct {
+		name     string
+		keys     []int
+		from     int
+		to       int
+		limit    int
+		wantAsc  []int
+		wantDesc []int
+	}{
+		{
+			name:     "full range",
+			keys:     []int{5, 1, 3, 7, 9},
+			from:     0,
+			to:       10,
+			wantAsc:  []int{1, 3, 5, 7, 9},
+			wantDesc: []int{9, 7, 5, 3, 1},
+		}, {
+			name:     "from inclusive and to exclusive",
+			keys:     []int{5, 1, 3, 7, 9},
+			from:     3,
+			to:       9,
+			wantAsc:  []int{3, 5, 7},
+			wantDesc: []int{7, 5, 3},
+		}, {
+			name:     "bounds between keys",
+			keys:     []int{5, 1, 3, 7, 9},
+			from:     2,
+			to:       8,
+			wantAsc:  []int{3, 5, 7},
+			wantDesc: []int{7, 5, 3},
+		}, {
+			name:     "empty range",
+			keys:     []int{5, 1, 3, 7, 9},
+			from:     5,
+			to:       5,
+			wantAsc:  []int{},
+			wantDesc: []int{},
+		}, {
+			name:     "terminate traversal",
+			keys:     []int{5, 1, 3, 7, 9},
+			from:     0,
+			to:       10,
+			limit:    2,
+			wantAsc:  []int{1, 3},
+			wantDesc: []int{9, 7},
+		}, {
+			name:     "empty tree",
+			from:     0,
+			to:       10,
+			wantAsc:  []int{},
+			wantDesc: []int{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			rbt := NewRBTree[int, int](testCmp)
+			for _, key := range tc.keys {
+				_ = rbt.Put(key, key)
+			}
+
+			collect := func(iterFunc func(from, to int, visitFunc func(key int, val int) bool)) []int {
+				res := make([]int, 0)
+				iterFunc(tc.from, tc.to, func(key int, _ int) bool {
+					res = append(res, key)
+					return tc.limit == 0 || len(res) < tc.limit
+				})
+				return res
+			}
+
+			assert.Equal(t, tc.wantAsc, collect(rbt.IterRange))
+			assert.Equal(t, tc.wantDesc, collect(rbt.IterRangeDesc))
+		})
+	}
+}
+
+func TestRBTree_IterDesc(t *testing.T) {
+	rbt := NewRBTree[int, int](testCmp)
+	for _, key := range []int{3, 1, 4, 5, 2} {
+		_ = rbt.Put(key, key)
+	}
+
+	keys := make([]int, 0)
+	rbt.IterDesc(func(key int, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, keys)
+}
+
+func TestRBTree_OrderStatistic(t *testing.T) {
+	rbt := NewRBTree[int, int](testCmp)
+	for _, key := range []int{50, 20, 80, 10, 30, 70, 90, 60} {
+		_ = rbt.Put(key, key)
+	}
+
+	tcs := []struct {
+		name     string
+		key      int
+		wantRank int64
+	}{
+		{name: "least key", key: 10, wantRank: 0},
+		{name: "greatest key", key: 90, wantRank: 7},
+		{name: "middle key", key: 60, wantRank: 4},
+		{name: "non-existent key less than all", key: 5, wantRank: 0},
+		{name: "non-existent key between", key: 55, wantRank: 4},
+		{name: "non-existent key greater than all", key: 95, wantRank: 8},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantRank, rbt.Rank(tc.key))
+		})
+	}
+
+	keys := rbt.Keys()
+	for i, key := range keys {
+		k, v, err := rbt.Select(int64(i))
+		assert.NoError(t, err)
+		assert.Equal(t, key, k)
+		assert.Equal(t, key, v)
+	}
+
+	_, _, err := rbt.Select(-1)
+	assert.Equal(t, errs.ErrNodeNotFound, err)
+	_, _, err = rbt.Select(rbt.Size())
+	assert.Equal(t, errs.ErrNodeNotFound, err)
+
+	assert.Equal(t, int64(3), rbt.CountRange(20, 60))
+	assert.Equal(t, int64(4), rbt.CountRange(15, 65))
+	assert.Equal(t, int64(8), rbt.CountRange(0, 100))
+	assert.Equal(t, int64(0), rbt.CountRange(60, 20))
+	assert.Equal(t, int64(0), rbt.CountRange(60, 60))
+}
+
+func TestRBTree_OrderStatisticRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	rbt := NewRBTree[int, int](testCmp)
+	want := make([]int, 0)
+
+	for range 2000 {
+		key := r.IntN(500)
+		idx, found := slices.BinarySearch(want, key)
+
+		if r.IntN(3) == 0 {
+			_, err := rbt.Del(key)
+			assert.Equal(t, found, err == nil)
+			if found {
+				want = slices.Delete(want, idx, idx+1)
+			}
+		} else {
+			err := rbt.Put(key, key)
+			assert.Equal(t, !found, err == nil)
+			if !found {
+				want = slices.Insert(want, idx, key)
+			}
+		}
+
+		rank := rbt.Rank(key)
+		wantRank, _ := slices.BinarySearch(want, key)
+		assert.Equal(t, int64(wantRank), rank)
+	}
+
+	assert.True(t, validRBTree(rbt.root))
+	assert.True(t, validSubtreeSize(rbt.root))
+	assert.Equal(t, int64(len(want)), rbt.Size())
+
+	for i, key := range want {
+		k, _, err := rbt.Select(int64(i))
+		assert.NoError(t, err)
+		assert.Equal(t, key, k)
+	}
+}
@@ -2,14 +2,16 @@ package pool
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/ratelimit"
 )
 
 const (
@@ -33,13 +35,55 @@ var (
 	errInvalidParam = fmt.Errorf("[jit] invalid param")
 	errInvalidTask  = fmt.Errorf("[jit] invalid task")
 
-	errPoolIsNotRunning = fmt.Errorf("[jit] task pool is not running")
-	errPoolIsRunning    = fmt.Errorf("[jit] task pool is running")
-	errPoolIsClosing    = fmt.Errorf("[jit] task pool is closing")
-	errPoolIsClosed     = fmt.Errorf("[jit] task pool is closed")
-	errPoolIsLocked     = fmt.Errorf("[jit] task pool is locked")
+	ErrPoolIsNotRunning = errors.New("[jit] task pool is not running")
+	ErrPoolIsRunning    = errors.New("[jit] task pool is running")
+	ErrPoolIsClosing    = errors.New("[jit] task pool is closing")
+	ErrPoolIsClosed     = errors.New("[jit] task pool is closed")
+	ErrPoolIsLocked     = errors.New("[jit] task pool is locked")
+
+	// ErrTaskRejected 任务被拒绝提交。
+	ErrTaskRejected = errors.New("[jit] task is rejected")
+	// ErrTaskDiscarded 任务被拒绝策略丢弃，只会通过 Observer.TaskRejected 通知。
+	ErrTaskDiscarded = errors.New("[jit] task is discarded")
+
+	errQueueIsFull   = fmt.Errorf("%w: task queue is full", ErrTaskRejected)
+	errSubmitLimited = fmt.Errorf("%w: submit is rate limited", ErrTaskRejected)
+)
+
+// RejectPolicy 任务队列已满时的拒绝策略。
+type RejectPolicy int8
+
+const (
+	// RejectPolicyBlock 阻塞等待队列有空闲位置，直到 ctx 结束或达到 submitTimeout，默认策略。
+	RejectPolicyBlock RejectPolicy = iota
+	// RejectPolicyAbort 立即返回 ErrTaskRejected。
+	RejectPolicyAbort
+	// RejectPolicyCallerRuns 在调用者的 goroutine 中直接执行任务。
+	RejectPolicyCallerRuns
+	// RejectPolicyDiscard 直接丢弃任务，Submit 返回 nil。
+	RejectPolicyDiscard
+	// RejectPolicyDiscardOldest 丢弃队首的任务（即下一个将被执行的任务），然后重新提交当前任务。
+	// 队列中没有可丢弃的任务时（例如队列容量为 0）返回 ErrTaskRejected。
+	RejectPolicyDiscardOldest
 )
 
+func (rp RejectPolicy) String() string {
+	switch rp {
+	case RejectPolicyBlock:
+		return "block"
+	case RejectPolicyAbort:
+		return "abort"
+	case RejectPolicyCallerRuns:
+		return "caller-runs"
+	case RejectPolicyDiscard:
+		return "discard"
+	case RejectPolicyDiscardOldest:
+		return "discard-oldest"
+	default:
+		return "unknown"
+	}
+}
+
 var _ Task = (*TaskFunc)(nil)
 
 type TaskFunc func(ctx context.Context) error
@@ -49,20 +93,30 @@ func (t TaskFunc) Run(ctx context.Context) error {
 }
 
 type taskWrapper struct {
-	task Task
+	task     Task
+	observer Observer
+
+	values  context.Context // 提交任务的 ctx，任务执行时继承其中的值
+	timeout time.Duration   // 任务的执行超时时间，0 表示不超时
+	clock   clock.Clock     // 计算执行超时时间的时钟，timeout 大于 0 时不为 nil
 }
 
 func (t *taskWrapper) Run(ctx context.Context) (err error) {
+	if t.values != nil {
+		ctx = &inheritCtx{Context: ctx, values: t.values}
+	}
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, t.clock, t.timeout)
+		defer cancel()
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			buf := make([]byte, panicBuffLen)
 			buf = buf[:runtime.Stack(buf, false)]
 
-			slog.Error(
-				"[jit] panic when running task",
-				"panic", r,
-				"stack", string(buf),
-			)
+			t.observer.TaskPanicked(r, buf)
 
 			err = fmt.Errorf("%w: %+v", errTaskRunningPanic, r)
 		}
@@ -121,6 +175,7 @@ type BlockTaskPool struct {
 
 	maxIdleTime   time.Duration
 	submitTimeout time.Duration
+	taskTimeout   time.Duration // 任务默认的执行超时时间，0 表示不超时
 
 	state         int32 // 内部状态
 	totalG        int32 // goroutine 总数
@@ -139,8 +194,8 @@ type BlockTaskPool struct {
 	coreG int32 // 核心 goroutine 数量
 	maxG  int32 // 最大 goroutine 数量
 
-	queue            chan Task // 任务队列
-	queueBacklogRate float64   // 任务队列积压率
+	queue            *taskQueue // 任务队列，支持优先级和延时任务
+	queueBacklogRate float64    // 任务队列积压率
 
 	timeoutG *timeoutGoroutine // 超时的 goroutine
 
@@ -149,71 +204,277 @@ type BlockTaskPool struct {
 
 	errHandler       func(ctx context.Context, err error) // 错误处理器
 	errHandleTimeout time.Duration
+
+	rejectPolicy  RejectPolicy                               // 任务队列已满时的拒绝策略
+	rejectHandler func(ctx context.Context, task Task) error // 自定义拒绝处理器，优先于 rejectPolicy
+
+	limiter ratelimit.Limiter // 提交任务的限流器
+
+	observer Observer    // 任务池事件观察者
+	clock    clock.Clock // 任务池使用的时钟
 }
 
 // Submit 提交一个任务。
-// 在队列已满的情况下，调用者会被阻塞。
+// 在队列已满的情况下，按拒绝策略处理，默认策略下调用者会被阻塞。
 // 在 Start 方法被调用后仍然可以调用 Submit 方法。
+//
+// 任务执行时的 ctx 继承 ctx 中的值，但不受 ctx 的取消和超时影响（ctx 的超时只用于控制提交），
+// 任务池被 ShutdownNow 中断或任务执行超时时通知任务。
 func (p *BlockTaskPool) Submit(ctx context.Context, task Task) error {
+	return p.submit(ctx, task, TaskOptions{})
+}
+
+// TrySubmit 非阻塞地提交一个任务，队列已满或者被限流时不使用拒绝策略，直接返回 ErrTaskRejected。
+func (p *BlockTaskPool) TrySubmit(ctx context.Context, task Task) error {
 	if task == nil {
+		p.observer.TaskRejected(errInvalidTask)
 		return errInvalidTask
 	}
 
+	var r *ratelimit.Reservation
+	if p.limiter != nil {
+		if r = p.limiter.Reserve(); !r.OK() || r.Delay() > 0 {
+			r.Cancel()
+			p.observer.TaskRejected(errSubmitLimited)
+			return errSubmitLimited
+		}
+	}
+
+	if err := p.tryEnqueue(ctx, p.newQueuedTask(ctx, task, TaskOptions{})); err != nil {
+		if r != nil {
+			// 提交失败时归还限流器的许可
+			r.Cancel()
+		}
+		p.observer.TaskRejected(err)
+		return err
+	}
+	return nil
+}
+
+// SubmitWithPriority 提交一个带优先级的任务，priority 越大越先执行，同优先级的任务先进先出。
+// Submit 提交的任务优先级为 0。
+func (p *BlockTaskPool) SubmitWithPriority(ctx context.Context, task Task, priority int) error {
+	return p.submit(ctx, task, TaskOptions{priority: priority})
+}
+
+// SubmitAfter 提交一个延时任务，任务在 delay 之后才可以被执行。
+func (p *BlockTaskPool) SubmitAfter(ctx context.Context, task Task, delay time.Duration) error {
+	return p.submit(ctx, task, TaskOptions{runAt: p.clock.Now().Add(delay)})
+}
+
+// SubmitAt 提交一个延时任务，任务在 runAt 之后才可以被执行。
+//
+// 延时任务在到达执行时间前不占用任务队列的容量，所以提交延时任务不会因为队列已满而阻塞。
+// 到达执行时间后延时任务进入任务队列，与其他任务一起按优先级调度。
+// 调用 Shutdown 后，剩余的延时任务仍然会在到达执行时间后执行。
+func (p *BlockTaskPool) SubmitAt(ctx context.Context, task Task, runAt time.Time) error {
+	return p.submit(ctx, task, TaskOptions{runAt: runAt})
+}
+
+// SubmitTask 提交一个任务并返回任务的句柄，通过 opts 设置任务的优先级、执行时间和执行超时时间。
+// 句柄可以查询任务处于排队、执行中还是已结束，并且可以取消还未开始执行的任务。
+//
+// 任务没有进入队列时句柄的状态为 TaskStateCanceled，Err 返回原因：
+//
+//	1、被 RejectPolicyDiscard 或 RejectPolicyDiscardOldest 丢弃时为 ErrTaskDiscarded；
+//	2、交给自定义拒绝处理器处理时为 ErrTaskRejected，句柄不再跟踪任务的执行；
+//	3、ShutdownNow 返回的任务为 ErrPoolIsClosed，调用者自行执行时句柄同样不再跟踪。
+func (p *BlockTaskPool) SubmitTask(ctx context.Context, task Task, opts ...option.Opt[TaskOptions]) (*TaskHandle, error) {
+	if task == nil {
+		p.observer.TaskRejected(errInvalidTask)
+		return nil, errInvalidTask
+	}
+
+	var to TaskOptions
+	option.Apply(&to, opts...)
+
+	qt := p.newQueuedTask(ctx, task, to)
+	qt.handle = newTaskHandle()
+	if err := p.submitQueued(ctx, qt); err != nil {
+		return nil, err
+	}
+	return qt.handle, nil
+}
+
+func (p *BlockTaskPool) submit(ctx context.Context, task Task, to TaskOptions) error {
+	if task == nil {
+		p.observer.TaskRejected(errInvalidTask)
+		return errInvalidTask
+	}
+	return p.submitQueued(ctx, p.newQueuedTask(ctx, task, to))
+}
+
+func (p *BlockTaskPool) submitQueued(ctx context.Context, qt *queuedTask) error {
+	if err := p.enqueue(ctx, qt); err != nil {
+		p.taskObserver(qt).TaskRejected(err)
+		return err
+	}
+	return nil
+}
+
+// taskObserver 返回通知 qt 的任务事件的 Observer，任务池内部的任务不计入任务事件。
+func (p *BlockTaskPool) taskObserver(qt *queuedTask) Observer {
+	if qt.internal {
+		return NopObserver{}
+	}
+	return p.observer
+}
+
+// newQueuedTask 创建队列中的任务，任务执行时继承 ctx 中的值。
+// ctx 为 nil 表示任务池内部的任务（例如 keyedRunner），不继承值也不使用默认的执行超时时间。
+func (p *BlockTaskPool) newQueuedTask(ctx context.Context, task Task, to TaskOptions) *queuedTask {
+	tw := &taskWrapper{
+		task:     task,
+		observer: p.observer,
+		clock:    p.clock,
+	}
+	if ctx != nil {
+		tw.values = context.WithoutCancel(ctx)
+		tw.timeout = p.taskTimeout
+	}
+	if to.timeout > 0 {
+		tw.timeout = to.timeout
+	}
+
+	return &queuedTask{
+		task:     tw,
+		priority: to.priority,
+		runAt:    to.runAt,
+		internal: ctx == nil,
+	}
+}
+
+// enqueue 等待限流器放行后提交任务，提交失败时归还限流器的许可。
+func (p *BlockTaskPool) enqueue(ctx context.Context, qt *queuedTask) error {
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, p.submitTimeout)
+		ctx, cancel = clock.WithTimeout(ctx, p.clock, p.submitTimeout)
 		defer cancel()
 	}
 
+	if p.limiter == nil || qt.internal {
+		return p.enqueueWithPolicy(ctx, qt)
+	}
+
+	// 限流等待同样受提交超时控制
+	r := p.limiter.Reserve()
+	if err := r.Wait(ctx); err != nil {
+		return err
+	}
+	err := p.enqueueWithPolicy(ctx, qt)
+	if err != nil {
+		// 提交失败时归还限流器的许可，避免影响之后的提交者
+		r.Cancel()
+	}
+	return err
+}
+
+// enqueueWithPolicy 提交任务，队列已满时按拒绝策略处理。
+func (p *BlockTaskPool) enqueueWithPolicy(ctx context.Context, qt *queuedTask) error {
 	for {
-		if atomic.LoadInt32(&p.state) == stateClosing {
-			return errPoolIsClosing
+		err := p.tryEnqueue(ctx, qt)
+		if !errors.Is(err, errQueueIsFull) {
+			return err
 		}
-		if atomic.LoadInt32(&p.state) == stateClosed {
-			return errPoolIsClosed
+
+		if p.rejectHandler != nil {
+			if err = p.rejectHandler(ctx, qt.task.(*taskWrapper).task); err == nil {
+				qt.cancel(ErrTaskRejected)
+			}
+			return err
+		}
+
+		switch p.rejectPolicy {
+		case RejectPolicyAbort:
+			return err
+		case RejectPolicyCallerRuns:
+			p.taskObserver(qt).TaskSubmitted()
+			qt.readyAt = p.clock.Now()
+			p.runTask(qt)
+			return nil
+		case RejectPolicyDiscard:
+			qt.cancel(ErrTaskDiscarded)
+			p.observer.TaskRejected(ErrTaskDiscarded)
+			return nil
+		case RejectPolicyDiscardOldest:
+			oldest := p.queue.poll()
+			if oldest == nil {
+				// 没有可丢弃的任务
+				return err
+			}
+			oldest.cancel(ErrTaskDiscarded)
+			p.observer.TaskRejected(ErrTaskDiscarded)
+		default:
+			// 队列已满，等待队列有空闲位置后重试。
+			if err = p.queue.waitNotFull(ctx); err != nil {
+				return err
+			}
 		}
+	}
+}
+
+// checkSubmit 检查任务池是否允许提交任务。
+func (p *BlockTaskPool) checkSubmit() error {
+	switch atomic.LoadInt32(&p.state) {
+	case stateClosing:
+		return ErrPoolIsClosing
+	case stateClosed:
+		return ErrPoolIsClosed
+	default:
+		return nil
+	}
+}
 
-		tw := &taskWrapper{
-			task: task,
+// tryEnqueue 非阻塞提交任务，队列已满时返回 errQueueIsFull。
+func (p *BlockTaskPool) tryEnqueue(ctx context.Context, qt *queuedTask) error {
+	for {
+		if err := p.checkSubmit(); err != nil {
+			return err
 		}
 
-		ok, err := p.trySubmit(ctx, tw, stateCreated)
+		ok, err := p.trySubmit(ctx, qt, stateCreated)
 		if ok || err != nil {
 			return err
 		}
 
-		ok, err = p.trySubmit(ctx, tw, stateRunning)
+		ok, err = p.trySubmit(ctx, qt, stateRunning)
 		if ok || err != nil {
 			return err
 		}
+
+		// trySubmit 失败可能是因为任务池被其他提交者锁定，队列未满时重试。
+		if p.queue.full() {
+			return errQueueIsFull
+		}
 	}
 }
 
-// trySubmit 尝试提交一个任务。
-func (p *BlockTaskPool) trySubmit(ctx context.Context, task Task, state int32) (bool, error) {
+// trySubmit 尝试提交一个任务，队列已满时直接返回。
+func (p *BlockTaskPool) trySubmit(ctx context.Context, qt *queuedTask, state int32) (bool, error) {
 	// 锁定 task pool。
 	if atomic.CompareAndSwapInt32(&p.state, state, stateLocked) {
 		// 当 trySubmit 成功返回时解除锁定 task pool。
 		defer atomic.CompareAndSwapInt32(&p.state, stateLocked, state)
 
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			return false, ctx.Err()
-		case p.queue <- task:
-			if state == stateRunning && p.allowToCreateG() {
-				// 任务池处于运行状态且允许创建新 goroutine 执行任务。
-				p.increaseG(1)
-				id := atomic.AddInt32(&p.id, 1)
-				go p.newG(id)
-
-				slog.Info("[jit] create new goroutine", "id", id)
-			}
+		}
 
-			// 任务池还未运行 或 当前不允许创建 goroutine，直接成功提交。
-			return true, nil
-		default:
+		if !p.queue.offer(qt) {
 			return false, nil
 		}
+		p.taskObserver(qt).TaskSubmitted()
+
+		if state == stateRunning && p.allowToCreateG() {
+			// 任务池处于运行状态且允许创建新 goroutine 执行任务。
+			p.increaseG(1)
+			id := atomic.AddInt32(&p.id, 1)
+			p.observer.GoroutineCreated(id)
+			go p.newG(id)
+		}
+
+		// 任务池还未运行 或 当前不允许创建 goroutine，直接成功提交。
+		return true, nil
 	}
 	return false, nil
 }
@@ -234,7 +495,7 @@ func (p *BlockTaskPool) allowToCreateG() bool {
 	}
 
 	// 计算队列占用率
-	rate := float64(len(p.queue)) / float64(cap(p.queue))
+	rate := float64(p.queue.len()) / float64(p.queue.cap())
 
 	// 队列存在待运行的 task 且队列积压率达到阈值
 	return rate != 0 && rate >= p.queueBacklogRate
@@ -242,27 +503,45 @@ func (p *BlockTaskPool) allowToCreateG() bool {
 
 // newG 创建新的 goroutine， 参数 id 用来表示新创建的 goroutine。
 func (p *BlockTaskPool) newG(id int32) {
-	// 创建一个持续时间为 0 的 Timer（假超时），该 Timer 会立即过期并向其 channel 发送信号。
-	//
-	// 注意：
-	// 	timer 只保证在等待 x 时间后才发送信号，而不是在 x 时间内发送信号。
-	//
-	// 这里假超时的目的是保证除任务池退出的情况外， goroutine 至少执行一个任务。
-	// 同时这里不能使用 nil timer，会导致 for 循环内的 case <-idleTImer.C 发生 panic。
-	idleTimer := time.NewTimer(0)
-	if !idleTimer.Stop() {
-		// 从 channel 中读取并丢弃该信号，避免假超时导致 goroutine 退出
-		<-idleTimer.C
-	}
-
 	for {
-		select {
-		case <-p.interruptCtx.Done():
+		// 处于超时组的 goroutine 最多等待 maxIdleTime 获取任务，其他 goroutine 一直等待直到任务池中断。
+		ctx, cancel := p.interruptCtx, context.CancelFunc(func() {})
+		if p.joinTimeoutG(id) {
+			ctx, cancel = clock.WithTimeout(p.interruptCtx, p.clock, p.getMaxIdleTime())
+		}
+
+		qt, err := p.queue.take(ctx)
+		cancel()
+
+		switch {
+		case errors.Is(err, errQueueIsClosed):
+			// 任务队列被关闭且没有剩余任务
+			// ShutdownNow 先迁移到 closed 状态再关闭队列，Shutdown 则在最后一个 goroutine 退出时才迁移到 closed 状态。
+			reason := GoroutineExitShutdown
+			if atomic.LoadInt32(&p.state) == stateClosed {
+				reason = GoroutineExitInterrupt
+			}
+
+			p.decreaseG(1)
+			// 任务池中没有 goroutine
+			if p.countG() == 0 {
+				// 因 shutdown 导致的 goroutine 退出，
+				// 最后一个退出的 goroutine 需要负责状态迁移，并通知外部调用者。
+				if atomic.CompareAndSwapInt32(&p.state, stateClosing, stateClosed) {
+					// 调用 context.CancelFunc 通知外部调用者
+					p.interruptCancelFunc()
+				}
+			}
+			p.observer.GoroutineExited(id, reason)
+			return
+
+		case err != nil && p.interruptCtx.Err() != nil:
 			// 收到整个 task pool 的中断信号
 			p.decreaseG(1)
+			p.observer.GoroutineExited(id, GoroutineExitInterrupt)
 			return
 
-		case <-idleTimer.C:
+		case err != nil:
 			// 空闲时收到超时信号，即 goroutine 在 maxIdleTime 时间内没获取到可执行任务
 			p.mu.Lock()
 			// 任务池 goroutine 总数 -1
@@ -270,79 +549,116 @@ func (p *BlockTaskPool) newG(id int32) {
 			// 从超时组移除当前 goroutine id
 			p.timeoutG.del(id)
 			p.mu.Unlock()
+			p.observer.GoroutineExited(id, GoroutineExitIdle)
 			return
+		}
 
-		case task, ok := <-p.queue:
-			// 当 p.queue 被 close，这里会收到零值 task 和 false
-			if p.timeoutG.in(id) {
-				// 当前 goroutine 在超时组中，且在超时前成功拿到任务执行
-				p.timeoutG.del(id)
-				if !idleTimer.Stop() {
-					<-idleTimer.C
-				}
-			}
+		if p.timeoutG.in(id) {
+			// 当前 goroutine 在超时组中，且在超时前成功拿到任务执行
+			p.timeoutG.del(id)
+		}
 
-			if !ok {
-				// !ok 意味着任务队列被关闭
-				p.decreaseG(1)
-				// 任务池中没有 goroutine
-				if p.countG() == 0 {
-					// 因 shutdown 导致的 goroutine 退出，
-					// 最后一个退出的 goroutine 需要负责状态迁移，并通知外部调用者。
-					if atomic.CompareAndSwapInt32(&p.state, stateClosing, stateClosed) {
-						// 调用 context.CancelFunc 通知外部调用者
-						p.interruptCancelFunc()
-					}
-				}
-				return
-			}
+		// 成功获取可执行任务
+		p.runTask(qt)
 
-			// 成功获取可执行任务
-			atomic.AddInt32(&p.totalRunningG, 1)
-			err := task.Run(p.interruptCtx)
-			atomic.AddInt32(&p.totalRunningG, -1)
-
-			// 处理任务执行错误
-			if err != nil && p.errHandler != nil {
-				// 在独立的 goroutine 中调用错误 errHandler，
-				// 避免 errHandler 发生 panic 影响任务池的运行。
-				go func(err error) {
-					defer func() {
-						if r := recover(); r != nil {
-						}
-					}()
-
-					// 超时控制，避免 goroutine 泄露
-					ctx, cancel := context.WithTimeout(p.interruptCtx, p.errHandleTimeout)
-					p.errHandler(ctx, err)
-					cancel()
-				}(err)
-			}
+		// 任务执行完成后的判断。
+		p.mu.Lock()
 
-			// 任务执行完成后的判断。
-			p.mu.Lock()
+		// 检查队列中是否还有任务需要执行。
+		queueLen := int32(p.queue.len())
+		noTaskToExec := queueLen == 0 || queueLen < p.totalG
+		// 临时 goroutine 的快速退出策略：
+		//
+		//	1、当前 goroutine 处于 (coreG, maxG] 区间（即临时 goroutine）且没有积压任务，直接退出 goroutine；
+		//	2、SetMaxG 调小 maxG 后 goroutine 总数超过 maxG，超出的 goroutine 执行完任务后直接退出。
+		if (noTaskToExec && p.coreG < p.totalG) || p.totalG > p.maxG {
+			p.totalG--
+			p.mu.Unlock()
+			p.observer.GoroutineExited(id, GoroutineExitTemporary)
+			return
+		}
 
-			// 检查队列中是否还有任务需要执行。
-			noTaskToExec := len(p.queue) == 0 || int32(len(p.queue)) < p.totalG
-			// 临时 goroutine 的快速退出策略
-			if noTaskToExec && p.coreG < p.totalG && p.totalG <= p.maxG {
-				// 当前 goroutine 处于 (coreG, maxG] 区间（即临时 goroutine），直接退出 goroutine。
-				p.totalG--
-				p.mu.Unlock()
-				return
-			}
+		p.mu.Unlock()
+	}
+}
+
+// runTask 执行任务，任务执行错误交给 errHandler 处理。
+// 任务在开始执行前已经通过句柄取消时直接丢弃。
+func (p *BlockTaskPool) runTask(qt *queuedTask) {
+	if qt.handle != nil && !qt.handle.start() {
+		return
+	}
+
+	observer := p.taskObserver(qt)
+	observer.TaskStarted(p.clock.Since(qt.readyAt))
+	atomic.AddInt32(&p.totalRunningG, 1)
+	startAt := p.clock.Now()
+	err := qt.task.Run(p.interruptCtx)
+	runTime := p.clock.Since(startAt)
+	atomic.AddInt32(&p.totalRunningG, -1)
+	observer.TaskFinished(runTime, err)
+
+	if qt.handle != nil {
+		qt.handle.finish(err)
+	}
+
+	p.handleErr(err)
+}
 
-			// p.totalG-p.timeoutG.size() -> 当前活跃的 goroutine 数
-			// 核心 goroutine 的超时管理，为属于 (initG, coreG] 区间的 goroutine 设置超时器。
-			if p.initG < p.totalG-p.timeoutG.size() {
-				// 核心 goroutine 不立即退出能保证在一定时间（maxIdleTime）由任务提交带来的扩容，保持核心处理能力。
-				idleTimer = time.NewTimer(p.maxIdleTime)
-				p.timeoutG.add(id)
+// handleErr 处理任务执行错误。
+func (p *BlockTaskPool) handleErr(err error) {
+	goHandleErr(p.interruptCtx, p.errHandler, p.errHandleTimeout, err)
+}
+
+// goHandleErr 在独立的 goroutine 中调用错误 errHandler，
+// 避免 errHandler 发生 panic 影响任务池的运行。
+func goHandleErr(ctx context.Context, errHandler func(ctx context.Context, err error), timeout time.Duration, err error) {
+	if err == nil || errHandler == nil {
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
 			}
+		}()
 
-			p.mu.Unlock()
-		}
+		// 超时控制，避免 goroutine 泄露
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		errHandler(ctx, err)
+		cancel()
+	}()
+}
+
+// joinTimeoutG 在 goroutine 等待任务前判断是否需要加入超时组，返回 goroutine 是否在超时组中。
+//
+// p.totalG-p.timeoutG.size() -> 当前活跃的 goroutine 数
+// 核心 goroutine 的超时管理，为属于 (initG, coreG] 区间的 goroutine 设置超时。
+// 核心 goroutine 不立即退出能保证在一定时间（maxIdleTime）由任务提交带来的扩容，保持核心处理能力。
+//
+// 注意：
+//
+//	在等待任务前而不是执行任务后判断，
+//	保证扩容创建但没有拿到任务的 goroutine 也能超时退出。
+func (p *BlockTaskPool) joinTimeoutG(id int32) bool {
+	if p.timeoutG.in(id) {
+		return true
 	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.initG < p.totalG-p.timeoutG.size() {
+		p.timeoutG.add(id)
+		return true
+	}
+	return false
+}
+
+func (p *BlockTaskPool) getMaxIdleTime() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.maxIdleTime
 }
 
 func (p *BlockTaskPool) increaseG(delta int32) {
@@ -370,16 +686,16 @@ func (p *BlockTaskPool) countG() int32 {
 func (p *BlockTaskPool) Start() error {
 	for {
 		if atomic.LoadInt32(&p.state) == stateClosing {
-			return errPoolIsClosing
+			return ErrPoolIsClosing
 		}
 		if atomic.LoadInt32(&p.state) == stateClosed {
-			return errPoolIsClosed
+			return ErrPoolIsClosed
 		}
 		if atomic.LoadInt32(&p.state) == stateRunning {
-			return errPoolIsRunning
+			return ErrPoolIsRunning
 		}
 		if atomic.LoadInt32(&p.state) == stateLocked {
-			return errPoolIsLocked
+			return ErrPoolIsLocked
 		}
 
 		if atomic.CompareAndSwapInt32(&p.state, stateCreated, stateLocked) {
@@ -387,7 +703,7 @@ func (p *BlockTaskPool) Start() error {
 			cntG := p.initG
 
 			// 需求的 goroutine 数 = 队列中任务数 - 初始 goroutine 数。
-			needG := int32(len(p.queue)) - p.initG
+			needG := int32(p.queue.len()) - p.initG
 			if needG > 0 {
 				// 允许创建的最大 goroutine 数
 				allowMaxG := p.maxG - p.initG
@@ -400,7 +716,9 @@ func (p *BlockTaskPool) Start() error {
 
 			p.increaseG(cntG)
 			for i := int32(0); i < cntG; i++ {
-				go p.newG(atomic.AddInt32(&p.id, 1))
+				id := atomic.AddInt32(&p.id, 1)
+				p.observer.GoroutineCreated(id)
+				go p.newG(id)
 			}
 			atomic.CompareAndSwapInt32(&p.state, stateLocked, stateRunning)
 			return nil
@@ -414,76 +732,185 @@ func (p *BlockTaskPool) Start() error {
 func (p *BlockTaskPool) Shutdown() (<-chan struct{}, error) {
 	for {
 		if atomic.LoadInt32(&p.state) == stateCreated {
-			return nil, errPoolIsNotRunning
+			return nil, ErrPoolIsNotRunning
 		}
 		if atomic.LoadInt32(&p.state) == stateClosed {
-			return nil, errPoolIsClosed
+			return nil, ErrPoolIsClosed
 		}
 		if atomic.LoadInt32(&p.state) == stateClosing {
-			return nil, errPoolIsClosing
+			return nil, ErrPoolIsClosing
 		}
 
 		if atomic.CompareAndSwapInt32(&p.state, stateRunning, stateClosing) {
 			// 关闭任务队列，拒绝新任务提交。
 			// 注意：
-			//  close(p.queue) 只是把 chan 标记为“关闭”状态，
-			//	此时工作 goroutine 还可以读取 chan 的剩余数据，知道 chan 的数据全被取走。
-			close(p.queue)
+			//  close 只是把队列标记为“关闭”状态，
+			//	此时工作 goroutine 还可以获取队列的剩余任务（包括延时任务），直到任务全被取走。
+			p.queue.close()
 			return p.interruptCtx.Done(), nil
 		}
 	}
 }
 
-// ShutdownNow 立即关闭任务池，并返回剩余的任务（不包含执行中的任务也）。
+// ShutdownNow 立即关闭任务池，并返回剩余的任务（包括未到执行时间的延时任务，不包含执行中的任务）。
+// 通过句柄取消的任务不会被返回。
+// 返回的任务视为被丢弃，通过 SubmitFunc 提交的任务的 Future 以 ErrPoolIsClosed 完成。
 func (p *BlockTaskPool) ShutdownNow() ([]Task, error) {
 	for {
 		if atomic.LoadInt32(&p.state) == stateCreated {
-			return nil, errPoolIsNotRunning
+			return nil, ErrPoolIsNotRunning
 		}
 		if atomic.LoadInt32(&p.state) == stateClosed {
-			return nil, errPoolIsClosed
+			return nil, ErrPoolIsClosed
 		}
 		if atomic.LoadInt32(&p.state) == stateClosing {
-			return nil, errPoolIsClosing
+			return nil, ErrPoolIsClosing
 		}
 
 		if atomic.CompareAndSwapInt32(&p.state, stateRunning, stateClosed) {
-			close(p.queue)
+			p.queue.close()
 			p.interruptCancelFunc()
 
-			tasks := make([]Task, 0, len(p.queue))
-			for task := range p.queue {
-				tasks = append(tasks, task)
-			}
-			return tasks, nil
+			return p.queue.drain(), nil
 		}
 	}
 }
 
+// SetCoreG 运行时调整核心 goroutine 数量，需要满足 initG <= coreG <= maxG。
+// 调小 coreG 后，超出的 goroutine 在空闲时退出，不会影响执行中和队列中的任务。
+func (p *BlockTaskPool) SetCoreG(coreG int32) error {
+	p.mu.Lock()
+	if !(p.initG <= coreG && coreG <= p.maxG) {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: goroutine required to satisfy [ init <= core <= max ]", errInvalidParam)
+	}
+	p.coreG = coreG
+	p.mu.Unlock()
+	return nil
+}
+
+// SetMaxG 运行时调整最大 goroutine 数量，需要满足 coreG <= maxG。
+// 同时调整 coreG 和 maxG 时，扩容需要先调用 SetMaxG，缩容需要先调用 SetCoreG。
+//
+// 调大 maxG 后，按队列中积压的任务数立即创建 goroutine。
+// 调小 maxG 后，超出的 goroutine 执行完当前任务后退出，不会影响执行中和队列中的任务。
+func (p *BlockTaskPool) SetMaxG(maxG int32) error {
+	p.mu.Lock()
+	if p.coreG > maxG {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: goroutine required to satisfy [ init <= core <= max ]", errInvalidParam)
+	}
+	p.maxG = maxG
+	p.mu.Unlock()
+
+	p.growG()
+	return nil
+}
+
+// SetMaxIdleTime 运行时调整 goroutine 的最大空闲时间。
+// 新的空闲时间在 goroutine 下一次等待任务时生效。
+func (p *BlockTaskPool) SetMaxIdleTime(maxIdleTime time.Duration) error {
+	if maxIdleTime <= 0 {
+		return fmt.Errorf("%w: max idle time should be greater than 0", errInvalidParam)
+	}
+
+	p.mu.Lock()
+	p.maxIdleTime = maxIdleTime
+	p.mu.Unlock()
+	return nil
+}
+
+// SetQueueBacklogRate 运行时调整触发创建 goroutine 的队列积压率。
+func (p *BlockTaskPool) SetQueueBacklogRate(queueBacklogRate float64) error {
+	if queueBacklogRate < float64(0) || queueBacklogRate > float64(1) {
+		return fmt.Errorf("%w: queue backlog rate should be in [0, 1]", errInvalidParam)
+	}
+
+	p.mu.Lock()
+	p.queueBacklogRate = queueBacklogRate
+	p.mu.Unlock()
+
+	p.growG()
+	return nil
+}
+
+// SetQueueSize 运行时调整任务队列的容量。
+// 调大容量后唤醒阻塞在 Submit 的调用者；
+// 调小容量时队列中超出容量的任务不会被丢弃，在队列积压降到容量以下之前新的提交会按拒绝策略处理。
+func (p *BlockTaskPool) SetQueueSize(queueSize int32) error {
+	if queueSize < 0 {
+		return fmt.Errorf("%w: queue size should be greater or equal to 0", errInvalidParam)
+	}
+
+	p.queue.setCap(int(queueSize))
+	p.growG()
+	return nil
+}
+
+// growG 参数调整后，按队列中积压的任务数创建 goroutine，goroutine 总数不超过 maxG。
+// 只在任务池运行时生效，任务池未运行时由 Start 负责创建 goroutine。
+func (p *BlockTaskPool) growG() {
+	for {
+		state := atomic.LoadInt32(&p.state)
+		if state != stateRunning && state != stateLocked {
+			return
+		}
+		// 与 trySubmit 一样锁定 task pool，避免并发创建 goroutine 超过 maxG。
+		if atomic.CompareAndSwapInt32(&p.state, stateRunning, stateLocked) {
+			break
+		}
+		runtime.Gosched()
+	}
+	defer atomic.CompareAndSwapInt32(&p.state, stateLocked, stateRunning)
+
+	if !p.allowToCreateG() {
+		return
+	}
+
+	p.mu.Lock()
+	cntG := min(int32(p.queue.len()), p.maxG-p.totalG)
+	p.totalG += cntG
+	p.mu.Unlock()
+
+	for i := int32(0); i < cntG; i++ {
+		id := atomic.AddInt32(&p.id, 1)
+		p.observer.GoroutineCreated(id)
+		go p.newG(id)
+	}
+}
+
 // State 查询任务池内部状态。
 func (p *BlockTaskPool) State(ctx context.Context, interval time.Duration) (<-chan State, error) {
+	return watchState(ctx, p.interruptCtx, p.clock, interval, p.getState)
+}
+
+// watchState 按 interval 定时发送任务池状态，ctx 结束或任务池中断时发送最后一次状态并关闭 chan。
+func watchState(
+	ctx context.Context, interruptCtx context.Context,
+	clk clock.Clock, interval time.Duration, getState func(timestamp int64) State,
+) (<-chan State, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
-	if p.interruptCtx.Err() != nil {
-		return nil, p.interruptCtx.Err()
+	if interruptCtx.Err() != nil {
+		return nil, interruptCtx.Err()
 	}
 
 	stateChan := make(chan State)
 	go func() {
-		ticker := time.NewTicker(interval)
+		ticker := clk.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
 			select {
-			case timestamp := <-ticker.C:
-				p.sendState(stateChan, timestamp.UnixMilli())
+			case timestamp := <-ticker.C():
+				sendState(stateChan, getState(timestamp.UnixMilli()))
 			case <-ctx.Done():
-				p.sendState(stateChan, time.Now().UnixMilli())
+				sendState(stateChan, getState(clk.Now().UnixMilli()))
 				close(stateChan)
 				return
-			case <-p.interruptCtx.Done():
-				p.sendState(stateChan, time.Now().UnixMilli())
+			case <-interruptCtx.Done():
+				sendState(stateChan, getState(clk.Now().UnixMilli()))
 				close(stateChan)
 				return
 			}
@@ -492,9 +919,9 @@ func (p *BlockTaskPool) State(ctx context.Context, interval time.Duration) (<-ch
 	return stateChan, nil
 }
 
-func (p *BlockTaskPool) sendState(ch chan<- State, timestamp int64) {
+func sendState(ch chan<- State, state State) {
 	select {
-	case ch <- p.getState(timestamp):
+	case ch <- state:
 	default:
 		// 发送失败直接丢弃。
 	}
@@ -502,9 +929,10 @@ func (p *BlockTaskPool) sendState(ch chan<- State, timestamp int64) {
 
 func (p *BlockTaskPool) getState(timestamp int64) State {
 	return State{
-		QueueSize:    int32(cap(p.queue)),
+		QueueSize:    int32(p.queue.cap()),
 		GoroutineCnt: p.countG(),
-		WaitingCnt:   int32(len(p.queue)),
+		WaitingCnt:   int32(p.queue.len()),
+		DelayedCnt:   int32(p.queue.delayedLen()),
 		RunningCnt:   atomic.LoadInt32(&p.totalRunningG),
 		PoolState:    atomic.LoadInt32(&p.state),
 		Timestamp:    timestamp,
@@ -523,6 +951,14 @@ func WithSubmitTimeout(submitTimeout time.Duration) option.Opt[BlockTaskPool] {
 	}
 }
 
+// WithDefaultTaskTimeout 设置任务默认的执行超时时间，从任务开始执行时计时，超时后通过 ctx 通知任务。
+// 默认为 0，即任务不超时。
+func WithDefaultTaskTimeout(taskTimeout time.Duration) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		p.taskTimeout = taskTimeout
+	}
+}
+
 func WithCoreG(coreG int32) option.Opt[BlockTaskPool] {
 	return func(p *BlockTaskPool) {
 		p.coreG = coreG
@@ -553,6 +989,51 @@ func WithErrHandleTimeout(errHandleTimeout time.Duration) option.Opt[BlockTaskPo
 	}
 }
 
+// WithRejectPolicy 设置任务队列已满时的拒绝策略，默认为 RejectPolicyBlock。
+// 延时任务在到达执行时间前不占用队列容量，提交延时任务不会触发拒绝策略。
+func WithRejectPolicy(policy RejectPolicy) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		p.rejectPolicy = policy
+	}
+}
+
+// WithRejectHandler 设置自定义的拒绝处理器，任务队列已满时调用，优先于 WithRejectPolicy 设置的拒绝策略。
+// handler 在调用者的 goroutine 中执行，返回值作为 Submit 的返回值。
+func WithRejectHandler(handler func(ctx context.Context, task Task) error) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		p.rejectHandler = handler
+	}
+}
+
+// WithSubmitLimiter 设置提交任务的限流器，提交任务前等待限流器放行，等待时间计入提交超时时间。
+// TrySubmit 在限流器不能立即放行时返回 ErrTaskRejected。
+func WithSubmitLimiter(limiter ratelimit.Limiter) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		p.limiter = limiter
+	}
+}
+
+// WithObserver 设置任务池的事件观察者，多个 Observer 会按顺序收到事件。
+// 默认的 Observer 为 LogObserver，设置后会替换默认的 Observer，
+// 需要保留日志时可以同时传入 NewLogObserver(nil)。
+func WithObserver(observers ...Observer) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		if len(observers) == 1 && observers[0] != nil {
+			p.observer = observers[0]
+			return
+		}
+		p.observer = MultiObserver(observers...)
+	}
+}
+
+// WithClock 设置任务池使用的时钟，默认为 clock.Real()。
+// 延时任务、goroutine 的空闲超时、提交超时和任务的执行超时都按该时钟计算，测试时可以使用 clock.NewManual。
+func WithClock(c clock.Clock) option.Opt[BlockTaskPool] {
+	return func(p *BlockTaskPool) {
+		p.clock = c
+	}
+}
+
 // NewBlockTaskPool 创建任务池。
 func NewBlockTaskPool(initG int32, queueSize int32, opts ...option.Opt[BlockTaskPool]) (*BlockTaskPool, error) {
 	if initG <= 0 {
@@ -563,13 +1044,14 @@ func NewBlockTaskPool(initG int32, queueSize int32, opts ...option.Opt[BlockTask
 	}
 
 	p := &BlockTaskPool{
-		queue:            make(chan Task, queueSize),
 		initG:            initG,
 		coreG:            initG,
 		maxG:             initG,
 		maxIdleTime:      defaultMaxIdleTime,
 		submitTimeout:    defaultSubmitTimeout,
 		errHandleTimeout: defaultErrHandleTimeout,
+		observer:         NewLogObserver(nil),
+		clock:            clock.Real(),
 	}
 
 	ctx := context.Background()
@@ -578,6 +1060,11 @@ func NewBlockTaskPool(initG int32, queueSize int32, opts ...option.Opt[BlockTask
 
 	option.Apply(p, opts...)
 
+	if p.clock == nil {
+		return nil, fmt.Errorf("%w: clock should not be nil", errInvalidParam)
+	}
+	p.queue = newTaskQueue(int(queueSize), p.clock)
+
 	// 默认情况 coreG == maxG。
 	// 当 coreG == maxG 时，goroutine 的分层会简化为两层:
 	//
@@ -604,5 +1091,11 @@ func NewBlockTaskPool(initG int32, queueSize int32, opts ...option.Opt[BlockTask
 	if p.queueBacklogRate < float64(0) || p.queueBacklogRate > float64(1) {
 		return nil, fmt.Errorf("%w: queue backlog rate should be in [0, 1]", errInvalidParam)
 	}
+	if p.taskTimeout < 0 {
+		return nil, fmt.Errorf("%w: task timeout should be greater or equal to 0", errInvalidParam)
+	}
+	if p.rejectPolicy < RejectPolicyBlock || p.rejectPolicy > RejectPolicyDiscardOldest {
+		return nil, fmt.Errorf("%w: unknown reject policy %d", errInvalidParam, p.rejectPolicy)
+	}
 	return p, nil
 }
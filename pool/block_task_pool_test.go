@@ -2,12 +2,16 @@ package pool
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/ratelimit"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func poolInternalState(p *BlockTaskPool) int32 {
@@ -29,6 +33,15 @@ func runningPool(t *testing.T, initG, queueSize int32, opts ...option.Opt[BlockT
 	return p
 }
 
+// waitForIdleG 等待任务池中有 n 个 goroutine 处于等待任务的状态。
+func waitForIdleG(t *testing.T, p *BlockTaskPool, n int) {
+	assert.Eventually(t, func() bool {
+		p.queue.mu.Lock()
+		defer p.queue.mu.Unlock()
+		return p.queue.waiters == n
+	}, time.Second, time.Millisecond)
+}
+
 func runningPoolWithFilledQueue(t *testing.T, initG, queueSize int32) (*BlockTaskPool, chan struct{}) {
 	p := runningPool(t, initG, queueSize)
 	wait := make(chan struct{})
@@ -101,7 +114,7 @@ func TestNewBlockTaskPool(t *testing.T) {
 				assert.Equal(t, stateCreated, poolInternalState(p))
 
 				assert.Equal(t, tc.initG, p.initG)
-				assert.Equal(t, int(tc.queueSize), cap(p.queue))
+				assert.Equal(t, int(tc.queueSize), p.queue.cap())
 			}
 		})
 	}
@@ -118,7 +131,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(1), p.coreG)
 		assert.Equal(t, int32(1), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 		assert.Equal(t, time.Second, p.maxIdleTime)
 	})
 
@@ -132,7 +145,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(1), p.coreG)
 		assert.Equal(t, int32(1), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 		assert.Equal(t, time.Second, p.submitTimeout)
 	})
 
@@ -146,7 +159,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(2), p.coreG)
 		assert.Equal(t, int32(2), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 	})
 
 	t.Run("with max goroutine", func(t *testing.T) {
@@ -159,7 +172,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(2), p.coreG)
 		assert.Equal(t, int32(2), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 	})
 
 	t.Run("with core and max goroutine", func(t *testing.T) {
@@ -172,7 +185,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(2), p.coreG)
 		assert.Equal(t, int32(4), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 		assert.Equal(t, int32(4), p.maxG)
 	})
 
@@ -186,7 +199,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(2), p.coreG)
 		assert.Equal(t, int32(2), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 	})
 
 	t.Run("with core == init and max != init", func(t *testing.T) {
@@ -199,7 +212,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.Equal(t, int32(1), p.initG)
 		assert.Equal(t, int32(4), p.coreG)
 		assert.Equal(t, int32(4), p.maxG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 	})
 
 	t.Run("with queue backlog rate", func(t *testing.T) {
@@ -210,7 +223,7 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.NotNil(t, p)
 		assert.Equal(t, stateCreated, poolInternalState(p))
 		assert.Equal(t, int32(1), p.initG)
-		assert.Equal(t, 3, cap(p.queue))
+		assert.Equal(t, 3, p.queue.cap())
 		assert.Equal(t, 0.5, p.queueBacklogRate)
 	})
 
@@ -229,6 +242,22 @@ func TestNewBlockTaskPoolWithOption(t *testing.T) {
 		assert.ErrorIs(t, err, errInvalidParam)
 		assert.Nil(t, p)
 	})
+
+	t.Run("with reject policy", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewBlockTaskPool(1, 3, WithRejectPolicy(RejectPolicyCallerRuns))
+		assert.NoError(t, err)
+		assert.Equal(t, RejectPolicyCallerRuns, p.rejectPolicy)
+	})
+
+	t.Run("unknown reject policy", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewBlockTaskPool(1, 3, WithRejectPolicy(RejectPolicyDiscardOldest+1))
+		assert.ErrorIs(t, err, errInvalidParam)
+		assert.Nil(t, p)
+	})
 }
 
 func TestBlockTaskPool_State(t *testing.T) {
@@ -491,7 +520,7 @@ func TestBlockTaskPool_Shutdown(t *testing.T) {
 
 	done, err := p1.Shutdown()
 	assert.Nil(t, done)
-	assert.ErrorIs(t, err, errPoolIsNotRunning)
+	assert.ErrorIs(t, err, ErrPoolIsNotRunning)
 	assert.Equal(t, poolInternalState(p1), stateCreated)
 
 	err = p1.Start()
@@ -507,7 +536,7 @@ func TestBlockTaskPool_Shutdown(t *testing.T) {
 
 	done, err = p1.Shutdown()
 	assert.Nil(t, done)
-	assert.ErrorIs(t, err, errPoolIsClosed)
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
 
 	p2, err := NewBlockTaskPool(1, 3)
 	assert.NoError(t, err)
@@ -516,7 +545,7 @@ func TestBlockTaskPool_Shutdown(t *testing.T) {
 
 	tasks, err := p2.ShutdownNow()
 	assert.Equal(t, 0, len(tasks))
-	assert.ErrorIs(t, err, errPoolIsNotRunning)
+	assert.ErrorIs(t, err, ErrPoolIsNotRunning)
 	assert.Equal(t, poolInternalState(p2), stateCreated)
 
 	err = p2.Start()
@@ -529,7 +558,405 @@ func TestBlockTaskPool_Shutdown(t *testing.T) {
 	assert.Equal(t, poolInternalState(p2), stateClosed)
 
 	_, err = p2.ShutdownNow()
-	assert.ErrorIs(t, err, errPoolIsClosed)
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+}
+
+func TestBlockTaskPool_SubmitWithPriority(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewBlockTaskPool(1, 8)
+	assert.NoError(t, err)
+
+	var mu sync.Mutex
+	ids := make([]int, 0, 6)
+	taskWithId := func(id int) Task {
+		return TaskFunc(func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ids = append(ids, id)
+			return nil
+		})
+	}
+
+	// 任务池启动前提交的任务按优先级执行，同优先级的任务先进先出
+	assert.NoError(t, p.Submit(context.Background(), taskWithId(1)))
+	assert.NoError(t, p.SubmitWithPriority(context.Background(), taskWithId(2), 10))
+	assert.NoError(t, p.SubmitWithPriority(context.Background(), taskWithId(3), -1))
+	assert.NoError(t, p.SubmitWithPriority(context.Background(), taskWithId(4), 10))
+	assert.NoError(t, p.SubmitWithPriority(context.Background(), taskWithId(5), 5))
+	assert.NoError(t, p.Submit(context.Background(), taskWithId(6)))
+
+	assert.NoError(t, p.Start())
+	done, err := p.Shutdown()
+	assert.NoError(t, err)
+	<-done
+
+	assert.Equal(t, []int{2, 4, 5, 1, 6, 3}, ids)
+}
+
+func TestBlockTaskPool_SubmitAfter(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 4)
+
+	type record struct {
+		id    int
+		runAt time.Time
+	}
+
+	records := make(chan record, 3)
+	taskWithId := func(id int) Task {
+		return TaskFunc(func(ctx context.Context) error {
+			records <- record{id: id, runAt: time.Now()}
+			return nil
+		})
+	}
+
+	start := time.Now()
+	assert.NoError(t, p.SubmitAfter(context.Background(), taskWithId(1), 30*time.Millisecond))
+	assert.NoError(t, p.SubmitAt(context.Background(), taskWithId(2), start.Add(10*time.Millisecond)))
+	assert.NoError(t, p.Submit(context.Background(), taskWithId(3)))
+
+	r := <-records
+	assert.Equal(t, 3, r.id)
+
+	r = <-records
+	assert.Equal(t, 2, r.id)
+	assert.GreaterOrEqual(t, r.runAt.Sub(start), 10*time.Millisecond)
+
+	r = <-records
+	assert.Equal(t, 1, r.id)
+	assert.GreaterOrEqual(t, r.runAt.Sub(start), 30*time.Millisecond)
+
+	// Shutdown 后剩余的延时任务仍然会执行
+	assert.NoError(t, p.SubmitAfter(context.Background(), taskWithId(4), 10*time.Millisecond))
+	done, err := p.Shutdown()
+	assert.NoError(t, err)
+	<-done
+
+	r = <-records
+	assert.Equal(t, 4, r.id)
+
+	assert.ErrorIs(t, p.SubmitAfter(context.Background(), taskWithId(5), time.Millisecond), ErrPoolIsClosed)
+}
+
+func TestBlockTaskPool_ShutdownNowWithDelayedTask(t *testing.T) {
+	t.Parallel()
+
+	p, wait := runningPoolWithFilledQueue(t, 1, 2)
+	defer close(wait)
+
+	for range 3 {
+		assert.NoError(t, p.SubmitAfter(context.Background(), TaskFunc(func(ctx context.Context) error {
+			return nil
+		}), time.Hour))
+	}
+
+	state := p.getState(time.Now().UnixMilli())
+	assert.Equal(t, int32(3), state.DelayedCnt)
+
+	tasks, err := p.ShutdownNow()
+	assert.NoError(t, err)
+	// 队列中的 2 个任务 + 3 个延时任务
+	assert.Equal(t, 5, len(tasks))
+}
+
+// fullPool 创建一个运行中且队列已满的任务池，唯一的 goroutine 被阻塞直到 wait 被关闭。
+// 队列中的任务执行时会把 id 写入 ids。
+func fullPool(t *testing.T, opts ...option.Opt[BlockTaskPool]) (p *BlockTaskPool, wait chan struct{}, ids chan int) {
+	p = runningPool(t, 1, 1, opts...)
+	wait = make(chan struct{})
+	ids = make(chan int, 4)
+
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})))
+	<-started
+
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		ids <- 1
+		return nil
+	})))
+	return p, wait, ids
+}
+
+func TestBlockTaskPool_RejectPolicy(t *testing.T) {
+	t.Parallel()
+
+	var handled Task
+
+	tcs := []struct {
+		name    string
+		opts    []option.Opt[BlockTaskPool]
+		wantErr error
+		// 调用者的 goroutine 中执行的任务
+		wantCallerIds []int
+		// 任务池中执行的任务
+		wantPoolIds  []int
+		wantRejected []error
+	}{
+		{
+			name:         "block",
+			wantErr:      context.DeadlineExceeded,
+			wantPoolIds:  []int{1},
+			wantRejected: []error{context.DeadlineExceeded},
+		}, {
+			name:         "abort",
+			opts:         []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyAbort)},
+			wantErr:      ErrTaskRejected,
+			wantPoolIds:  []int{1},
+			wantRejected: []error{ErrTaskRejected},
+		}, {
+			name:          "caller runs",
+			opts:          []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyCallerRuns)},
+			wantCallerIds: []int{2},
+			wantPoolIds:   []int{1},
+		}, {
+			name:         "discard",
+			opts:         []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyDiscard)},
+			wantPoolIds:  []int{1},
+			wantRejected: []error{ErrTaskDiscarded},
+		}, {
+			name:         "discard oldest",
+			opts:         []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyDiscardOldest)},
+			wantPoolIds:  []int{2},
+			wantRejected: []error{ErrTaskDiscarded},
+		}, {
+			name: "custom handler",
+			opts: []option.Opt[BlockTaskPool]{
+				WithRejectPolicy(RejectPolicyDiscard),
+				WithRejectHandler(func(ctx context.Context, task Task) error {
+					handled = task
+					return ErrTaskRejected
+				}),
+			},
+			wantErr:      ErrTaskRejected,
+			wantPoolIds:  []int{1},
+			wantRejected: []error{ErrTaskRejected},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			obs := newRecordObserver()
+			p, wait, ids := fullPool(t, append(tc.opts, WithObserver(obs))...)
+
+			var callerIds []int
+			task := TaskFunc(func(ctx context.Context) error {
+				select {
+				case <-wait:
+					ids <- 2
+				default:
+					// 队列已满时在调用者的 goroutine 中执行
+					callerIds = append(callerIds, 2)
+				}
+				return nil
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			err := p.Submit(ctx, task)
+			cancel()
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCallerIds, callerIds)
+
+			close(wait)
+			done, err := p.Shutdown()
+			require.NoError(t, err)
+			<-done
+			close(ids)
+
+			poolIds := make([]int, 0, 2)
+			for id := range ids {
+				poolIds = append(poolIds, id)
+			}
+			assert.Equal(t, tc.wantPoolIds, poolIds)
+
+			obs.mu.Lock()
+			defer obs.mu.Unlock()
+			assert.Len(t, obs.rejected, len(tc.wantRejected))
+			for i, err := range tc.wantRejected {
+				assert.ErrorIs(t, obs.rejected[i], err)
+			}
+		})
+	}
+
+	// 自定义拒绝处理器收到的是原始任务
+	_, ok := handled.(TaskFunc)
+	assert.True(t, ok)
+}
+
+func TestBlockTaskPool_TrySubmit(t *testing.T) {
+	t.Parallel()
+
+	p, wait, ids := fullPool(t, WithRejectPolicy(RejectPolicyCallerRuns))
+
+	// 队列已满时直接返回，不使用拒绝策略
+	err := p.TrySubmit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		ids <- 2
+		return nil
+	}))
+	assert.ErrorIs(t, err, ErrTaskRejected)
+	assert.ErrorIs(t, p.TrySubmit(context.Background(), nil), errInvalidTask)
+
+	close(wait)
+	assert.Equal(t, 1, <-ids)
+
+	// 队列有空闲位置时提交成功
+	assert.Eventually(t, func() bool {
+		return p.TrySubmit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			ids <- 3
+			return nil
+		})) == nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 3, <-ids)
+
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.TrySubmit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})), ErrPoolIsClosed)
+}
+
+func TestBlockTaskPool_SetParam(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewBlockTaskPool(2, 4, WithMaxG(4))
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name    string
+		setFunc func() error
+		wantErr error
+	}{
+		{name: "core less than init", setFunc: func() error { return p.SetCoreG(1) }, wantErr: errInvalidParam},
+		{name: "core greater than max", setFunc: func() error { return p.SetCoreG(5) }, wantErr: errInvalidParam},
+		{name: "core", setFunc: func() error { return p.SetCoreG(3) }},
+		{name: "max less than core", setFunc: func() error { return p.SetMaxG(2) }, wantErr: errInvalidParam},
+		{name: "max", setFunc: func() error { return p.SetMaxG(8) }},
+		{name: "max idle time is 0", setFunc: func() error { return p.SetMaxIdleTime(0) }, wantErr: errInvalidParam},
+		{name: "max idle time", setFunc: func() error { return p.SetMaxIdleTime(time.Second) }},
+		{name: "backlog rate is negative", setFunc: func() error { return p.SetQueueBacklogRate(-0.1) }, wantErr: errInvalidParam},
+		{name: "backlog rate greater than 1", setFunc: func() error { return p.SetQueueBacklogRate(1.1) }, wantErr: errInvalidParam},
+		{name: "backlog rate", setFunc: func() error { return p.SetQueueBacklogRate(0.5) }},
+		{name: "queue size is negative", setFunc: func() error { return p.SetQueueSize(-1) }, wantErr: errInvalidParam},
+		{name: "queue size", setFunc: func() error { return p.SetQueueSize(8) }},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.setFunc(), tc.wantErr)
+		})
+	}
+
+	assert.Equal(t, int32(3), p.coreG)
+	assert.Equal(t, int32(8), p.maxG)
+	assert.Equal(t, time.Second, p.maxIdleTime)
+	assert.Equal(t, 0.5, p.queueBacklogRate)
+	assert.Equal(t, 8, p.queue.cap())
+	// 任务池未运行时不创建 goroutine
+	assert.Equal(t, int32(0), p.countG())
+}
+
+func TestBlockTaskPool_ResizeUnderLoad(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 16, WithMaxIdleTime(10*time.Millisecond))
+
+	var executed, running, maxRunning atomic.Int32
+	wait := make(chan struct{})
+	newTask := func(wait <-chan struct{}) Task {
+		return TaskFunc(func(ctx context.Context) error {
+			cur := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := maxRunning.Load()
+				if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+
+			<-wait
+			time.Sleep(time.Millisecond)
+			executed.Add(1)
+			return nil
+		})
+	}
+
+	for range 8 {
+		require.NoError(t, p.Submit(context.Background(), newTask(wait)))
+	}
+	// 只有一个 goroutine，其他任务积压在队列中
+	assert.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+
+	// 扩容：立即为积压的任务创建 goroutine
+	require.NoError(t, p.SetMaxG(4))
+	assert.Eventually(t, func() bool { return running.Load() == 4 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(4), p.countG())
+
+	// 缩容：超出的 goroutine 执行完当前任务后退出
+	require.NoError(t, p.SetCoreG(2))
+	require.NoError(t, p.SetMaxG(2))
+	close(wait)
+
+	assert.Eventually(t, func() bool { return executed.Load() == 8 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool { return p.countG() <= 2 }, time.Second, time.Millisecond)
+	maxRunning.Store(0)
+
+	done := make(chan struct{})
+	close(done)
+	for range 8 {
+		require.NoError(t, p.Submit(context.Background(), newTask(done)))
+	}
+	assert.Eventually(t, func() bool { return executed.Load() == 16 }, time.Second, time.Millisecond)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	assert.LessOrEqual(t, p.countG(), int32(2))
+
+	// 空闲的核心 goroutine 超时退出
+	assert.Eventually(t, func() bool { return p.countG() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestBlockTaskPool_SetQueueSize(t *testing.T) {
+	t.Parallel()
+
+	p, wait, ids := fullPool(t)
+
+	// 队列已满，提交被阻塞
+	submitted := make(chan error)
+	go func() {
+		submitted <- p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			ids <- 2
+			return nil
+		}))
+	}()
+
+	select {
+	case err := <-submitted:
+		t.Fatalf("submit should be blocked, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// 扩容后被阻塞的提交成功
+	require.NoError(t, p.SetQueueSize(2))
+	require.NoError(t, <-submitted)
+	assert.Equal(t, 2, p.queue.len())
+
+	// 缩容不会丢弃队列中的任务
+	require.NoError(t, p.SetQueueSize(0))
+	assert.Equal(t, 2, p.queue.len())
+	assert.ErrorIs(t, p.TrySubmit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})), ErrTaskRejected)
+
+	close(wait)
+	assert.Equal(t, 1, <-ids)
+	assert.Equal(t, 2, <-ids)
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
 }
 
 func TestBlockTaskPool_state_machine(t *testing.T) {
@@ -673,6 +1100,8 @@ func TestBlockTaskPool_state_machine(t *testing.T) {
 
 		p := runningPool(t, initG, queueSize, WithCoreG(coreG), WithMaxIdleTime(2*time.Millisecond))
 		assert.Equal(t, initG, p.countG())
+		// 保证永久 goroutine 已经在等待任务
+		waitForIdleG(t, p, int(initG))
 
 		var err error
 		done := make(chan struct{})
@@ -689,7 +1118,7 @@ func TestBlockTaskPool_state_machine(t *testing.T) {
 			assert.NoError(t, err)
 		}
 
-		assert.Equal(t, 0, len(p.queue))
+		assert.Equal(t, 0, p.queue.len())
 		// 至少有 initG
 		// 这里用 LessOrEqual 判断是为了兼容并发竞争导致的核心 goroutine 创建
 		assert.LessOrEqual(t, initG, p.countG())
@@ -721,6 +1150,8 @@ func TestBlockTaskPool_state_machine(t *testing.T) {
 
 		p := runningPool(t, initG, queueSize, WithCoreG(coreG), WithMaxG(maxG), WithMaxIdleTime(2*time.Millisecond))
 		assert.Equal(t, initG, p.countG())
+		// 保证永久 goroutine 已经在等待任务
+		waitForIdleG(t, p, int(initG))
 
 		var err error
 		done := make(chan struct{})
@@ -737,7 +1168,7 @@ func TestBlockTaskPool_state_machine(t *testing.T) {
 			assert.NoError(t, err)
 		}
 
-		assert.Equal(t, 0, len(p.queue))
+		assert.Equal(t, 0, p.queue.len())
 		// 至少有 initG
 		// 这里用 LessOrEqual 判断是为了兼容并发竞争导致的核心 goroutine 创建
 		assert.LessOrEqual(t, initG, p.countG())
@@ -771,3 +1202,121 @@ func TestBlockTaskPool_state_machine(t *testing.T) {
 		assert.Equal(t, initG, p.countG())
 	})
 }
+
+func TestBlockTaskPool_Clock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(start)
+	p := runningPool(t, 1, 4, WithClock(clk))
+
+	// 延时任务按时钟执行
+	runAt := make(chan time.Time, 1)
+	require.NoError(t, p.SubmitAfter(context.Background(), TaskFunc(func(ctx context.Context) error {
+		runAt <- clk.Now()
+		return nil
+	}), time.Hour))
+
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	clk.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), <-runAt)
+
+	// 任务的执行超时按时钟计算
+	h, err := p.SubmitTask(context.Background(), TaskFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithTaskTimeout(time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	clk.Advance(time.Minute)
+	assert.ErrorIs(t, h.Wait(context.Background()), context.DeadlineExceeded)
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	_, err = NewBlockTaskPool(1, 1, WithClock(nil))
+	assert.ErrorIs(t, err, errInvalidParam)
+}
+
+func TestBlockTaskPool_SubmitLimiter(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	limiter, err := ratelimit.NewTokenBucket(1, time.Second, 1, ratelimit.WithClock(clk))
+	require.NoError(t, err)
+	p := runningPool(t, 1, 4, WithClock(clk), WithSubmitLimiter(limiter))
+
+	var runs atomic.Int32
+	task := TaskFunc(func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	require.NoError(t, p.Submit(context.Background(), task))
+	// 限流器不能立即放行
+	assert.ErrorIs(t, p.TrySubmit(context.Background(), task), ErrTaskRejected)
+
+	// 等待时间超过提交的 ctx 的超时时间
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Submit(ctx, task), context.DeadlineExceeded)
+
+	// 等待限流器放行后提交
+	res := make(chan error, 1)
+	go func() {
+		res <- p.Submit(context.Background(), task)
+	}()
+	// ctx 的超时定时器、提交超时定时器和限流等待定时器
+	require.NoError(t, clk.BlockUntil(context.Background(), 3))
+	clk.Advance(time.Second)
+	require.NoError(t, <-res)
+
+	assert.Eventually(t, func() bool {
+		return runs.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestBlockTaskPool_SubmitLimiterGiveBack(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	limiter, err := ratelimit.NewTokenBucket(1, time.Hour, 3, ratelimit.WithClock(clk))
+	require.NoError(t, err)
+	p := runningPool(t, 1, 1, WithClock(clk), WithSubmitLimiter(limiter), WithRejectPolicy(RejectPolicyAbort))
+
+	// 占满 goroutine 和任务队列
+	wait := make(chan struct{})
+	started := make(chan struct{}, 1)
+	block := TaskFunc(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-wait
+		return nil
+	})
+	require.NoError(t, p.Submit(context.Background(), block))
+	<-started
+	require.NoError(t, p.Submit(context.Background(), block))
+
+	// 队列已满导致提交失败时归还许可
+	assert.ErrorIs(t, p.Submit(context.Background(), block), errQueueIsFull)
+	assert.ErrorIs(t, p.TrySubmit(context.Background(), block), errQueueIsFull)
+	assert.InDelta(t, 1, limiter.Tokens(), 0.0001)
+
+	close(wait)
+	<-started
+	assert.Eventually(t, func() bool {
+		return p.queue.len() == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, p.TrySubmit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})))
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
@@ -0,0 +1,253 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errTaskIsCanceled 任务被取消，可以通过 errors.Is(err, context.Canceled) 判断。
+var errTaskIsCanceled = fmt.Errorf("[jit] task is canceled: %w", context.Canceled)
+
+// Future 异步任务的执行结果。
+type Future[T any] struct {
+	mu sync.Mutex
+
+	done chan struct{}
+	val  T
+	err  error
+
+	cancelRun context.CancelFunc // 取消正在执行的任务
+}
+
+// Get 阻塞等待任务执行完成并返回结果，ctx 结束时返回 ctx.Err()。
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done 返回任务完成（包括执行成功、失败和被取消）时关闭的 chan。
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result 返回任务的执行结果，任务未完成时返回零值。
+func (f *Future[T]) Result() T {
+	select {
+	case <-f.done:
+		return f.val
+	default:
+		var zero T
+		return zero
+	}
+}
+
+// Err 返回任务的执行错误，任务未完成时返回 nil。
+func (f *Future[T]) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Cancel 取消任务，返回是否取消成功。任务已经完成时取消失败。
+// 未开始执行的任务不会再执行，正在执行的任务通过 ctx 通知取消。
+// 取消后 Get 返回的 error 满足 errors.Is(err, context.Canceled)。
+func (f *Future[T]) Cancel() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var zero T
+	if !f.completeLocked(zero, errTaskIsCanceled) {
+		return false
+	}
+
+	if f.cancelRun != nil {
+		f.cancelRun()
+	}
+	return true
+}
+
+// complete 设置任务的执行结果，返回是否设置成功。结果只能被设置一次。
+func (f *Future[T]) complete(val T, err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completeLocked(val, err)
+}
+
+func (f *Future[T]) completeLocked(val T, err error) bool {
+	select {
+	case <-f.done:
+		return false
+	default:
+	}
+
+	f.val, f.err = val, err
+	close(f.done)
+	return true
+}
+
+// start 开始执行任务前调用，返回任务执行使用的 ctx。任务已经被取消时返回 false。
+func (f *Future[T]) start(ctx context.Context) (context.Context, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return nil, false
+	default:
+	}
+
+	ctx, f.cancelRun = context.WithCancel(ctx)
+	return ctx, true
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+var (
+	_ Task    = (*futureTask[any])(nil)
+	_ dropper = (*futureTask[any])(nil)
+)
+
+// futureTask 将带返回值的函数包装为 Task，执行结果写入 Future。
+type futureTask[T any] struct {
+	fn     func(ctx context.Context) (T, error)
+	future *Future[T]
+}
+
+func (t *futureTask[T]) Run(ctx context.Context) error {
+	ctx, ok := t.future.start(ctx)
+	if !ok {
+		// 任务在执行前被取消
+		return nil
+	}
+
+	defer func() {
+		t.future.cancelRun()
+
+		if r := recover(); r != nil {
+			var zero T
+			t.future.complete(zero, fmt.Errorf("%w: %+v", errTaskRunningPanic, r))
+			// 继续 panic，交给 taskWrapper 记录堆栈
+			panic(r)
+		}
+	}()
+
+	val, err := t.fn(ctx)
+	t.future.complete(val, err)
+	return err
+}
+
+// drop 任务被任务池丢弃，Future 以丢弃原因完成。
+func (t *futureTask[T]) drop(err error) {
+	var zero T
+	t.future.complete(zero, err)
+}
+
+// SubmitFunc 提交一个带返回值的任务，返回任务的 Future。
+// 任务被拒绝策略丢弃或者通过 ShutdownNow 返回时不会再被执行，Future 以丢弃原因（例如 ErrTaskDiscarded）完成。
+func SubmitFunc[T any](ctx context.Context, p TaskPool, fn func(ctx context.Context) (T, error)) (*Future[T], error) {
+	if fn == nil {
+		return nil, errInvalidTask
+	}
+
+	future := newFuture[T]()
+	if err := p.Submit(ctx, &futureTask[T]{fn: fn, future: future}); err != nil {
+		return nil, err
+	}
+	return future, nil
+}
+
+// InvokeAll 提交所有任务并等待全部完成，返回每个任务的 Future（与 fns 顺序一致）。
+// 提交失败或 ctx 结束时取消所有已提交的任务并返回 error。
+func InvokeAll[T any](ctx context.Context, p TaskPool, fns ...func(ctx context.Context) (T, error)) ([]*Future[T], error) {
+	futures, err := submitAll(ctx, p, fns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range futures {
+		select {
+		case <-f.Done():
+		case <-ctx.Done():
+			cancelAll(futures)
+			return nil, ctx.Err()
+		}
+	}
+	return futures, nil
+}
+
+// InvokeAny 提交所有任务，返回第一个执行成功的任务结果，并取消其他任务。
+// 所有任务都执行失败时返回所有任务的 error。
+// 提交失败或 ctx 结束时取消所有已提交的任务并返回 error。
+func InvokeAny[T any](ctx context.Context, p TaskPool, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if len(fns) == 0 {
+		return zero, fmt.Errorf("%w: no task to invoke", errInvalidParam)
+	}
+
+	for _, fn := range fns {
+		if fn == nil {
+			return zero, errInvalidTask
+		}
+	}
+
+	futures, err := submitAll(ctx, p, fns)
+	if err != nil {
+		return zero, err
+	}
+	defer cancelAll(futures)
+
+	// 按完成顺序收集 Future，包括 panic、被取消和被丢弃的任务。
+	// 返回时所有 Future 都会被取消，转发的 goroutine 随之退出。
+	done := make(chan *Future[T], len(futures))
+	for _, f := range futures {
+		go func() {
+			<-f.Done()
+			done <- f
+		}()
+	}
+
+	errs := make([]error, 0, len(fns))
+	for range futures {
+		select {
+		case f := <-done:
+			if f.Err() == nil {
+				return f.Result(), nil
+			}
+			errs = append(errs, f.Err())
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	return zero, errors.Join(errs...)
+}
+
+func submitAll[T any](ctx context.Context, p TaskPool, fns []func(ctx context.Context) (T, error)) ([]*Future[T], error) {
+	futures := make([]*Future[T], 0, len(fns))
+	for _, fn := range fns {
+		f, err := SubmitFunc(ctx, p, fn)
+		if err != nil {
+			cancelAll(futures)
+			return nil, err
+		}
+		futures = append(futures, f)
+	}
+	return futures, nil
+}
+
+func cancelAll[T any](futures []*Future[T]) {
+	for _, f := range futures {
+		f.Cancel()
+	}
+}
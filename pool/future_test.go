@@ -0,0 +1,349 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitFunc(t *testing.T) {
+	t.Parallel()
+
+	errBiz := errors.New("biz error")
+
+	tcs := []struct {
+		name    string
+		fn      func(ctx context.Context) (int, error)
+		wantVal int
+		wantErr error
+	}{
+		{
+			name: "basic",
+			fn: func(ctx context.Context) (int, error) {
+				return 1, nil
+			},
+			wantVal: 1,
+		}, {
+			name: "error",
+			fn: func(ctx context.Context) (int, error) {
+				return 0, errBiz
+			},
+			wantErr: errBiz,
+		}, {
+			name: "panic",
+			fn: func(ctx context.Context) (int, error) {
+				panic("panic")
+			},
+			wantErr: errTaskRunningPanic,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := runningPool(t, 1, 1)
+
+			f, err := SubmitFunc(context.Background(), p, tc.fn)
+			require.NoError(t, err)
+
+			val, err := f.Get(context.Background())
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantVal, val)
+
+			<-f.Done()
+			assert.Equal(t, tc.wantVal, f.Result())
+			assert.ErrorIs(t, f.Err(), tc.wantErr)
+			// 已经完成的任务不能取消
+			assert.False(t, f.Cancel())
+		})
+	}
+}
+
+func TestSubmitFunc_InvalidParam(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 1)
+
+	_, err := SubmitFunc[int](context.Background(), p, nil)
+	assert.ErrorIs(t, err, errInvalidTask)
+
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+
+	_, err = SubmitFunc(context.Background(), p, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+}
+
+func TestFuture_Get(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 1)
+
+	release := make(chan struct{})
+	f, err := SubmitFunc(context.Background(), p, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	// 任务未完成
+	assert.Equal(t, 0, f.Result())
+	assert.NoError(t, f.Err())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = f.Get(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	val, err := f.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, val)
+}
+
+func TestFuture_Cancel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cancel running task", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 1, 1)
+
+		started := make(chan struct{})
+		f, err := SubmitFunc(context.Background(), p, func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.NoError(t, err)
+
+		<-started
+		assert.True(t, f.Cancel())
+		assert.False(t, f.Cancel())
+
+		_, err = f.Get(context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errTaskIsCanceled)
+	})
+
+	t.Run("cancel queued task", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 1, 1)
+
+		// 阻塞唯一的 goroutine，保证后续任务在队列中
+		wait := make(chan struct{})
+		require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			<-wait
+			return nil
+		})))
+
+		var ran atomic.Bool
+		f, err := SubmitFunc(context.Background(), p, func(ctx context.Context) (int, error) {
+			ran.Store(true)
+			return 1, nil
+		})
+		require.NoError(t, err)
+		assert.True(t, f.Cancel())
+
+		close(wait)
+		done, err := p.Shutdown()
+		require.NoError(t, err)
+		<-done
+
+		// 被取消的任务不会执行
+		assert.False(t, ran.Load())
+		assert.ErrorIs(t, f.Err(), errTaskIsCanceled)
+	})
+}
+
+func TestFuture_Dropped(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 1)
+
+	// 阻塞唯一的 goroutine，保证后续任务在队列中
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	})))
+	<-started
+
+	f, err := SubmitFunc(context.Background(), p, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	tasks, err := p.ShutdownNow()
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	// 通过 ShutdownNow 返回的任务视为被丢弃，再次执行也不会运行
+	_, err = f.Get(context.Background())
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+	assert.NoError(t, tasks[0].Run(context.Background()))
+	assert.Zero(t, f.Result())
+}
+
+func TestInvokeAll(t *testing.T) {
+	t.Parallel()
+
+	errBiz := errors.New("biz error")
+	p := runningPool(t, 2, 4, WithMaxG(4))
+
+	fns := []func(ctx context.Context) (int, error){
+		func(ctx context.Context) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 1, nil
+		},
+		func(ctx context.Context) (int, error) {
+			return 0, errBiz
+		},
+		func(ctx context.Context) (int, error) {
+			return 3, nil
+		},
+	}
+
+	futures, err := InvokeAll(context.Background(), p, fns...)
+	require.NoError(t, err)
+	require.Len(t, futures, 3)
+
+	for _, f := range futures {
+		select {
+		case <-f.Done():
+		default:
+			t.Fatal("future is not done")
+		}
+	}
+
+	assert.Equal(t, 1, futures[0].Result())
+	assert.ErrorIs(t, futures[1].Err(), errBiz)
+	assert.Equal(t, 3, futures[2].Result())
+
+	// ctx 超时后取消所有任务
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = InvokeAll(ctx, p, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestInvokeAny(t *testing.T) {
+	t.Parallel()
+
+	errBiz := errors.New("biz error")
+
+	t.Run("first success", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 3, 3)
+
+		var canceled atomic.Bool
+		val, err := InvokeAny(context.Background(), p,
+			func(ctx context.Context) (int, error) {
+				return 0, errBiz
+			},
+			func(ctx context.Context) (int, error) {
+				time.Sleep(5 * time.Millisecond)
+				return 2, nil
+			},
+			func(ctx context.Context) (int, error) {
+				select {
+				case <-ctx.Done():
+					canceled.Store(true)
+					return 0, ctx.Err()
+				case <-time.After(time.Second):
+					return 3, nil
+				}
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, val)
+
+		// 其他任务被取消
+		assert.Eventually(t, canceled.Load, time.Second, time.Millisecond)
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 2, 2)
+
+		errOther := errors.New("other error")
+		_, err := InvokeAny(context.Background(), p,
+			func(ctx context.Context) (int, error) {
+				return 0, errBiz
+			},
+			func(ctx context.Context) (int, error) {
+				return 0, errOther
+			},
+		)
+		assert.ErrorIs(t, err, errBiz)
+		assert.ErrorIs(t, err, errOther)
+	})
+
+	t.Run("panic and failed", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 2, 2)
+
+		_, err := InvokeAny(context.Background(), p,
+			func(ctx context.Context) (int, error) {
+				panic("boom")
+			},
+			func(ctx context.Context) (int, error) {
+				return 0, errBiz
+			},
+		)
+		assert.ErrorIs(t, err, errTaskRunningPanic)
+		assert.ErrorIs(t, err, errBiz)
+	})
+
+	t.Run("discarded", func(t *testing.T) {
+		t.Parallel()
+
+		p, wait := runningPoolWithFilledQueue(t, 1, 1)
+		defer close(wait)
+		p.rejectPolicy = RejectPolicyDiscard
+
+		_, err := InvokeAny(context.Background(), p, func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		assert.ErrorIs(t, err, ErrTaskDiscarded)
+	})
+
+	t.Run("no task", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 1, 1)
+
+		_, err := InvokeAny[int](context.Background(), p)
+		assert.ErrorIs(t, err, errInvalidParam)
+	})
+
+	t.Run("context timeout", func(t *testing.T) {
+		t.Parallel()
+
+		p := runningPool(t, 1, 1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := InvokeAny(ctx, p, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,309 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/xsync"
+)
+
+const (
+	keyStarting int8 = iota // key 的 runner 正在提交到任务池
+	keyRunning              // key 的 runner 已提交到任务池
+)
+
+// keyedTask 等待执行的带 key 的任务。
+type keyedTask struct {
+	task        Task
+	submittedAt time.Time // 加入 key 的队列的时间，用于统计排队等待时间
+}
+
+// keyQueue 同一个 key 的等待执行的任务。
+type keyQueue struct {
+	tasks []keyedTask // 先进先出
+	state int8
+
+	cond *xsync.Cond // 通知 key 的状态变化和队列出队
+}
+
+var _ TaskPool = (*KeyedTaskPool[any])(nil)
+
+// KeyedTaskPool 按 key 有序执行的任务池。
+// 相同 key 的任务按提交顺序串行执行，不同 key 的任务并发执行。
+//
+// KeyedTaskPool 基于 BlockTaskPool 实现，复用其 goroutine 分层管理和生命周期。
+// 每个有等待任务的 key 在任务池中最多只有一个 runner，runner 每次执行 key 的一个任务，
+// 之后重新提交到任务池，让不同 key 的任务公平地获得执行机会。
+type KeyedTaskPool[K comparable] struct {
+	mu sync.Mutex
+
+	pool *BlockTaskPool
+
+	keys          map[K]*keyQueue
+	maxKeyBacklog int // 每个 key 最多积压的任务数
+}
+
+// Submit 提交一个无 key 的任务，与其他任务没有顺序保证。
+func (p *KeyedTaskPool[K]) Submit(ctx context.Context, task Task) error {
+	return p.pool.Submit(ctx, task)
+}
+
+// SubmitKeyed 提交一个带 key 的任务，相同 key 的任务按提交顺序串行执行。
+// key 积压的任务数达到上限时，调用者会被阻塞直到 ctx 结束（未设置超时时间时使用任务池的提交超时时间）。
+//
+// Observer 的任务事件以带 key 的任务为单位通知，runner 在任务池中的提交和执行不计入任务事件。
+// 设置了 WithSubmitLimiter 时每个带 key 的任务都经过限流，提交失败时归还许可。
+func (p *KeyedTaskPool[K]) SubmitKeyed(ctx context.Context, key K, task Task) error {
+	if err := p.submitKeyed(ctx, key, task); err != nil {
+		p.pool.observer.TaskRejected(err)
+		return err
+	}
+	return nil
+}
+
+func (p *KeyedTaskPool[K]) submitKeyed(ctx context.Context, key K, task Task) error {
+	if task == nil {
+		return errInvalidTask
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, p.pool.clock, p.pool.submitTimeout)
+		defer cancel()
+	}
+
+	if p.pool.limiter == nil {
+		return p.appendKeyed(ctx, key, task)
+	}
+
+	// 每个带 key 的任务都经过限流，runner 重新提交到任务池时不再限流
+	r := p.pool.limiter.Reserve()
+	if err := r.Wait(ctx); err != nil {
+		return err
+	}
+	err := p.appendKeyed(ctx, key, task)
+	if err != nil {
+		// 提交失败时归还限流器的许可
+		r.Cancel()
+	}
+	return err
+}
+
+// appendKeyed 把任务加入 key 的队列，key 没有等待执行的任务时提交 key 的 runner。
+func (p *KeyedTaskPool[K]) appendKeyed(ctx context.Context, key K, task Task) error {
+	task = &taskWrapper{
+		task:     task,
+		observer: p.pool.observer,
+		values:   context.WithoutCancel(ctx),
+		timeout:  p.pool.taskTimeout,
+		clock:    p.pool.clock,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if err := p.pool.checkSubmit(); err != nil {
+			return err
+		}
+
+		kq, ok := p.keys[key]
+		if !ok {
+			return p.startKey(ctx, key, task)
+		}
+
+		if kq.state == keyRunning && len(kq.tasks) < p.maxKeyBacklog {
+			kq.tasks = append(kq.tasks, keyedTask{task: task, submittedAt: p.pool.clock.Now()})
+			p.pool.observer.TaskSubmitted()
+			return nil
+		}
+
+		// key 的 runner 正在提交或者积压已满，等待后重试。
+		if err := kq.cond.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// startKey 为没有等待任务的 key 创建队列，并把 key 的 runner 提交到任务池。
+// 调用者需要持有锁，提交 runner 时会释放锁，在 runner 提交完成前其他提交者需要等待。
+func (p *KeyedTaskPool[K]) startKey(ctx context.Context, key K, task Task) error {
+	kq := &keyQueue{
+		tasks: []keyedTask{{task: task, submittedAt: p.pool.clock.Now()}},
+		state: keyStarting,
+		cond:  xsync.NewCond(&p.mu),
+	}
+	p.keys[key] = kq
+
+	p.mu.Unlock()
+	// runner 不继承提交者 ctx 中的值，每个任务在执行时各自继承提交时的值。
+	err := p.pool.submitQueued(ctx, p.pool.newQueuedTask(nil, &keyedRunner[K]{p: p, key: key, kq: kq}, TaskOptions{}))
+	p.mu.Lock()
+
+	if err != nil {
+		// 提交失败，队列中只有当前任务（其他提交者在等待），直接移除 key。
+		p.delKey(key, kq)
+		return err
+	}
+
+	p.pool.observer.TaskSubmitted()
+	kq.state = keyRunning
+	kq.cond.Broadcast()
+	return nil
+}
+
+// delKey 移除 key 的队列，并唤醒等待的提交者，调用者需要持有锁。
+func (p *KeyedTaskPool[K]) delKey(key K, kq *keyQueue) {
+	if p.keys[key] == kq {
+		delete(p.keys, key)
+	}
+	kq.tasks = nil
+	kq.cond.Broadcast()
+}
+
+// next 取出 key 的下一个任务，没有等待执行的任务时移除 key。
+func (p *KeyedTaskPool[K]) next(key K, kq *keyQueue) (keyedTask, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(kq.tasks) == 0 {
+		p.delKey(key, kq)
+		return keyedTask{}, false
+	}
+
+	task := kq.tasks[0]
+	kq.tasks[0] = keyedTask{}
+	kq.tasks = kq.tasks[1:]
+	kq.cond.Broadcast()
+	return task, true
+}
+
+// done 判断 key 的任务是否已全部执行，是则移除 key。
+func (p *KeyedTaskPool[K]) done(key K, kq *keyQueue) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(kq.tasks) == 0 {
+		p.delKey(key, kq)
+		return true
+	}
+	return false
+}
+
+// Start 开始调度执行。
+func (p *KeyedTaskPool[K]) Start() error {
+	return p.pool.Start()
+}
+
+// Shutdown 关闭任务池，已提交的任务（包括带 key 的任务）执行完成后通过 chan 通知调用者。
+func (p *KeyedTaskPool[K]) Shutdown() (<-chan struct{}, error) {
+	return p.pool.Shutdown()
+}
+
+// ShutdownNow 立即关闭任务池，并返回剩余的任务（包括带 key 的任务）。
+// 同一个 key 的任务按提交顺序返回。
+func (p *KeyedTaskPool[K]) ShutdownNow() ([]Task, error) {
+	tasks, err := p.pool.ShutdownNow()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		if tw, ok := task.(*taskWrapper); ok {
+			if _, ok = tw.task.(*keyedRunner[K]); ok {
+				// runner 对应的任务在 key 的队列中
+				continue
+			}
+		}
+		res = append(res, task)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, kq := range p.keys {
+		for _, kt := range kq.tasks {
+			res = append(res, kt.task)
+		}
+		p.delKey(key, kq)
+	}
+	return res, nil
+}
+
+// State 查询任务池内部状态，带 key 的任务以 runner 为单位统计。
+func (p *KeyedTaskPool[K]) State(ctx context.Context, interval time.Duration) (<-chan State, error) {
+	return p.pool.State(ctx, interval)
+}
+
+// keyedRunner 在任务池中按顺序执行一个 key 的任务。
+type keyedRunner[K comparable] struct {
+	p   *KeyedTaskPool[K]
+	key K
+	kq  *keyQueue
+}
+
+func (r *keyedRunner[K]) Run(ctx context.Context) error {
+	for {
+		kt, ok := r.p.next(r.key, r.kq)
+		if !ok {
+			return nil
+		}
+
+		err := r.run(ctx, kt)
+		if r.p.done(r.key, r.kq) {
+			return err
+		}
+
+		// 还有等待执行的任务，把 runner 重新提交到任务池。
+		// 任务池队列已满或已关闭时，在当前 goroutine 中继续执行，避免阻塞工作 goroutine。
+		if r.p.pool.tryEnqueue(ctx, r.p.pool.newQueuedTask(nil, r, TaskOptions{})) == nil {
+			return err
+		}
+		r.p.pool.handleErr(err)
+	}
+}
+
+// run 执行 key 的一个任务，并以任务为单位通知 Observer。
+func (r *keyedRunner[K]) run(ctx context.Context, kt keyedTask) error {
+	pool := r.p.pool
+	pool.observer.TaskStarted(pool.clock.Since(kt.submittedAt))
+	startAt := pool.clock.Now()
+	err := kt.task.Run(ctx)
+	pool.observer.TaskFinished(pool.clock.Since(startAt), err)
+	return err
+}
+
+// NewKeyedTaskPool 创建按 key 有序执行的任务池，maxKeyBacklog 为每个 key 最多积压的任务数。
+//
+// 注意：
+//
+//	runner 被丢弃会导致 key 的任务无法执行，
+//	所以不支持 RejectPolicyDiscard、RejectPolicyDiscardOldest 和自定义拒绝处理器。
+func NewKeyedTaskPool[K comparable](
+	initG int32, queueSize int32, maxKeyBacklog int, opts ...option.Opt[BlockTaskPool],
+) (*KeyedTaskPool[K], error) {
+	if maxKeyBacklog <= 0 {
+		return nil, fmt.Errorf("%w: max key backlog should be greater than 0", errInvalidParam)
+	}
+
+	pool, err := NewBlockTaskPool(initG, queueSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if pool.rejectHandler != nil ||
+		pool.rejectPolicy == RejectPolicyDiscard || pool.rejectPolicy == RejectPolicyDiscardOldest {
+		return nil, fmt.Errorf("%w: reject policy %s is not supported by keyed task pool", errInvalidParam, pool.rejectPolicy)
+	}
+
+	return &KeyedTaskPool[K]{
+		pool:          pool,
+		keys:          make(map[K]*keyQueue),
+		maxKeyBacklog: maxKeyBacklog,
+	}, nil
+}
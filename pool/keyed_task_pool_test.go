@@ -0,0 +1,342 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyedTaskPool(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name          string
+		maxKeyBacklog int
+		opts          []option.Opt[BlockTaskPool]
+		wantErr       error
+	}{
+		{
+			name:          "basic",
+			maxKeyBacklog: 1,
+		}, {
+			name:          "max key backlog is 0",
+			maxKeyBacklog: 0,
+			wantErr:       errInvalidParam,
+		}, {
+			name:          "invalid pool param",
+			maxKeyBacklog: 1,
+			opts:          []option.Opt[BlockTaskPool]{WithQueueBacklogRate(2)},
+			wantErr:       errInvalidParam,
+		}, {
+			name:          "caller runs",
+			maxKeyBacklog: 1,
+			opts:          []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyCallerRuns)},
+		}, {
+			name:          "discard",
+			maxKeyBacklog: 1,
+			opts:          []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyDiscard)},
+			wantErr:       errInvalidParam,
+		}, {
+			name:          "reject handler",
+			maxKeyBacklog: 1,
+			opts: []option.Opt[BlockTaskPool]{WithRejectHandler(func(ctx context.Context, task Task) error {
+				return nil
+			})},
+			wantErr: errInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewKeyedTaskPool[string](1, 1, tc.maxKeyBacklog, tc.opts...)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.NotNil(t, p)
+			}
+		})
+	}
+}
+
+func TestKeyedTaskPool_SubmitKeyed(t *testing.T) {
+	t.Parallel()
+
+	const keyCnt, taskCnt = 5, 50
+
+	p, err := NewKeyedTaskPool[string](4, 8, taskCnt)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	var mu sync.Mutex
+	seqs := make(map[string][]int, keyCnt)
+	var running, maxRunning atomic.Int32
+	keyRunning := make(map[string]*atomic.Bool, keyCnt)
+	for k := range keyCnt {
+		keyRunning[fmt.Sprintf("key-%d", k)] = &atomic.Bool{}
+	}
+
+	var wg sync.WaitGroup
+	for k := range keyCnt {
+		key := fmt.Sprintf("key-%d", k)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range taskCnt {
+				err := p.SubmitKeyed(context.Background(), key, TaskFunc(func(ctx context.Context) error {
+					// 相同 key 的任务不能并发执行
+					assert.True(t, keyRunning[key].CompareAndSwap(false, true))
+					defer keyRunning[key].Store(false)
+
+					cur := running.Add(1)
+					defer running.Add(-1)
+					for {
+						old := maxRunning.Load()
+						if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+							break
+						}
+					}
+					time.Sleep(100 * time.Microsecond)
+
+					mu.Lock()
+					seqs[key] = append(seqs[key], i)
+					mu.Unlock()
+					return nil
+				}))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	wantSeq := make([]int, taskCnt)
+	for i := range wantSeq {
+		wantSeq[i] = i
+	}
+	for k := range keyCnt {
+		assert.Equal(t, wantSeq, seqs[fmt.Sprintf("key-%d", k)])
+	}
+	// 不同 key 的任务并发执行
+	assert.Greater(t, maxRunning.Load(), int32(1))
+
+	p.mu.Lock()
+	assert.Empty(t, p.keys)
+	p.mu.Unlock()
+
+	assert.ErrorIs(t, p.SubmitKeyed(context.Background(), "key-0", TaskFunc(func(ctx context.Context) error {
+		return nil
+	})), ErrPoolIsClosed)
+}
+
+func TestKeyedTaskPool_Observer(t *testing.T) {
+	t.Parallel()
+
+	const taskCnt = 5
+
+	o, err := NewMetricsObserver()
+	require.NoError(t, err)
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	p, err := NewKeyedTaskPool[string](1, 4, taskCnt, WithObserver(o), WithClock(clk))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, p.SubmitKeyed(context.Background(), "key", TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})))
+	<-started
+	// 加入正在执行的 key 的队列的任务
+	for range taskCnt - 1 {
+		require.NoError(t, p.SubmitKeyed(context.Background(), "key", TaskFunc(func(ctx context.Context) error {
+			return nil
+		})))
+	}
+
+	clk.Advance(time.Second)
+	close(release)
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	// 以带 key 的任务为单位统计，不包括 runner
+	assert.Equal(t, uint64(taskCnt), o.submitted.Load())
+	assert.Equal(t, uint64(taskCnt), o.started.Load())
+	assert.Equal(t, uint64(taskCnt), o.finished.Load())
+
+	// 排队等待时间从加入 key 的队列开始计算
+	queueWait := o.QueueWait()
+	assert.Equal(t, uint64(taskCnt), queueWait.Count)
+	assert.InDelta(t, float64(taskCnt-1), queueWait.Sum, 1e-9)
+	runTime := o.RunTime()
+	assert.Equal(t, uint64(taskCnt), runTime.Count)
+	assert.InDelta(t, 1, runTime.Sum, 1e-9)
+}
+
+func TestKeyedTaskPool_SubmitLimiter(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	limiter, err := ratelimit.NewTokenBucket(1, time.Second, 1, ratelimit.WithClock(clk))
+	require.NoError(t, err)
+	p, err := NewKeyedTaskPool[string](1, 4, 1, WithClock(clk), WithSubmitLimiter(limiter))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, p.SubmitKeyed(context.Background(), "key", TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})))
+	<-started
+
+	task := TaskFunc(func(ctx context.Context) error {
+		return nil
+	})
+
+	// 加入正在执行的 key 的任务同样被限流
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 100*time.Millisecond)
+	assert.ErrorIs(t, p.SubmitKeyed(ctx, "key", task), ratelimit.ErrWouldExceedDeadline)
+	cancel()
+
+	clk.Advance(time.Second)
+	require.NoError(t, p.SubmitKeyed(context.Background(), "key", task))
+
+	// key 积压已满导致提交失败时归还许可
+	clk.Advance(time.Second)
+	ctx, cancel = context.WithCancel(context.Background())
+	res := make(chan error, 1)
+	go func() {
+		res <- p.SubmitKeyed(ctx, "key", task)
+	}()
+	assert.Eventually(t, func() bool {
+		return limiter.Tokens() < 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-res, context.Canceled)
+	assert.InDelta(t, 1, limiter.Tokens(), 0.0001)
+
+	close(release)
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestKeyedTaskPool_KeyBacklog(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewKeyedTaskPool[string](2, 4, 1)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	assert.ErrorIs(t, p.SubmitKeyed(context.Background(), "a", nil), errInvalidTask)
+
+	wait := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.SubmitKeyed(context.Background(), "a", TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})))
+	<-started
+
+	ids := make(chan int, 3)
+	require.NoError(t, p.SubmitKeyed(context.Background(), "a", TaskFunc(func(ctx context.Context) error {
+		ids <- 1
+		return nil
+	})))
+
+	// key a 的积压已满
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	err = p.SubmitKeyed(ctx, "a", TaskFunc(func(ctx context.Context) error {
+		ids <- 2
+		return nil
+	}))
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 其他 key 不受影响
+	require.NoError(t, p.SubmitKeyed(context.Background(), "b", TaskFunc(func(ctx context.Context) error {
+		ids <- 3
+		return nil
+	})))
+	assert.Equal(t, 3, <-ids)
+
+	// key a 的积压有空闲位置后，阻塞的提交成功
+	submitted := make(chan error)
+	go func() {
+		submitted <- p.SubmitKeyed(context.Background(), "a", TaskFunc(func(ctx context.Context) error {
+			ids <- 4
+			return nil
+		}))
+	}()
+	close(wait)
+	require.NoError(t, <-submitted)
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+	close(ids)
+
+	res := make([]int, 0, 2)
+	for id := range ids {
+		res = append(res, id)
+	}
+	assert.Equal(t, []int{1, 4}, res)
+}
+
+func TestKeyedTaskPool_ShutdownNow(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewKeyedTaskPool[string](1, 4, 4)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	wait := make(chan struct{})
+	defer close(wait)
+	started := make(chan struct{})
+	require.NoError(t, p.SubmitKeyed(context.Background(), "a", TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})))
+	<-started
+
+	require.NoError(t, p.SubmitKeyed(context.Background(), "a", &idTask{id: 1}))
+	require.NoError(t, p.SubmitKeyed(context.Background(), "a", &idTask{id: 2}))
+	require.NoError(t, p.SubmitKeyed(context.Background(), "b", &idTask{id: 3}))
+	require.NoError(t, p.Submit(context.Background(), &idTask{id: 4}))
+
+	tasks, err := p.ShutdownNow()
+	require.NoError(t, err)
+
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.(*taskWrapper).task.(*idTask).id)
+	}
+	// 无 key 的任务在前，相同 key 的任务按提交顺序返回
+	assert.Equal(t, 4, ids[0])
+	assert.Equal(t, []int{1, 2, 3}, slices.Sorted(slices.Values(ids[1:])))
+	assert.Less(t, slices.Index(ids, 1), slices.Index(ids, 2))
+
+	_, err = p.ShutdownNow()
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+}
@@ -0,0 +1,249 @@
+package pool
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+)
+
+const defaultMetricsNamespace = "jit_pool"
+
+// DefaultBuckets 默认的直方图桶上界（单位：秒），与 Prometheus 客户端的默认桶一致。
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HistogramSnapshot 直方图快照。
+type HistogramSnapshot struct {
+	Buckets []float64 // 桶上界（单位：秒），升序，不包含 +Inf
+	Counts  []uint64  // 各个桶的累计计数，即观察值 <= Buckets[i] 的数量
+	Count   uint64    // 观察值总数，等于 +Inf 桶的计数
+	Sum     float64   // 观察值总和（单位：秒）
+}
+
+// histogram 无锁直方图。
+type histogram struct {
+	buckets []float64
+	counts  []atomic.Uint64 // 非累计计数，最后一个为 +Inf 桶
+	count   atomic.Uint64
+	sumBits atomic.Uint64 // float64 的位表示
+}
+
+func (h *histogram) observe(d time.Duration) {
+	v := d.Seconds()
+
+	i, _ := slices.BinarySearch(h.buckets, v)
+	h.counts[i].Add(1)
+
+	for {
+		oldBits := h.sumBits.Load()
+		newBits := math.Float64bits(math.Float64frombits(oldBits) + v)
+		if h.sumBits.CompareAndSwap(oldBits, newBits) {
+			break
+		}
+	}
+	h.count.Add(1)
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Buckets: slices.Clone(h.buckets),
+		Counts:  make([]uint64, len(h.buckets)),
+	}
+
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += h.counts[i].Load()
+		s.Counts[i] = cumulative
+	}
+	// 并发观察时 count 与各个桶不是原子快照，以桶的累计值为准保证 +Inf 桶不小于其他桶。
+	s.Count = cumulative + h.counts[len(h.buckets)].Load()
+	s.Sum = math.Float64frombits(h.sumBits.Load())
+	return s
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+var (
+	_ Observer     = (*MetricsObserver)(nil)
+	_ http.Handler = (*MetricsObserver)(nil)
+)
+
+// MetricsObserver 统计任务池指标的 Observer。
+// 同时实现了 http.Handler，以 Prometheus 文本格式暴露指标。
+//
+// 多个任务池可以共用一个 MetricsObserver，此时指标为所有任务池的汇总。
+type MetricsObserver struct {
+	namespace string
+	buckets   []float64
+
+	submitted atomic.Uint64
+	rejected  atomic.Uint64
+	started   atomic.Uint64
+	finished  atomic.Uint64
+	failed    atomic.Uint64
+	panicked  atomic.Uint64
+
+	goroutineCreated atomic.Uint64
+	goroutineExited  [GoroutineExitShutdown + 1]atomic.Uint64
+
+	queueWait *histogram
+	runTime   *histogram
+}
+
+func (o *MetricsObserver) TaskSubmitted() {
+	o.submitted.Add(1)
+}
+
+func (o *MetricsObserver) TaskRejected(error) {
+	o.rejected.Add(1)
+}
+
+func (o *MetricsObserver) TaskStarted(queueWait time.Duration) {
+	o.started.Add(1)
+	o.queueWait.observe(queueWait)
+}
+
+func (o *MetricsObserver) TaskFinished(runTime time.Duration, err error) {
+	if err != nil {
+		o.failed.Add(1)
+	}
+	o.finished.Add(1)
+	o.runTime.observe(runTime)
+}
+
+func (o *MetricsObserver) TaskPanicked(any, []byte) {
+	o.panicked.Add(1)
+}
+
+func (o *MetricsObserver) GoroutineCreated(int32) {
+	o.goroutineCreated.Add(1)
+}
+
+func (o *MetricsObserver) GoroutineExited(_ int32, reason GoroutineExitReason) {
+	if int(reason) < len(o.goroutineExited) {
+		o.goroutineExited[reason].Add(1)
+	}
+}
+
+// QueueWait 返回任务排队等待时间的直方图快照。
+func (o *MetricsObserver) QueueWait() HistogramSnapshot {
+	return o.queueWait.snapshot()
+}
+
+// RunTime 返回任务执行耗时的直方图快照。
+func (o *MetricsObserver) RunTime() HistogramSnapshot {
+	return o.runTime.snapshot()
+}
+
+// ServeHTTP 以 Prometheus 文本格式（text/plain; version=0.0.4）输出指标。
+func (o *MetricsObserver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	bw := bufio.NewWriter(w)
+	o.writeCounter(bw, "tasks_submitted_total", "Total number of tasks submitted.", o.submitted.Load())
+	o.writeCounter(bw, "tasks_rejected_total", "Total number of tasks rejected.", o.rejected.Load())
+	o.writeCounter(bw, "tasks_started_total", "Total number of tasks started.", o.started.Load())
+	o.writeCounter(bw, "tasks_finished_total", "Total number of tasks finished.", o.finished.Load())
+	o.writeCounter(bw, "tasks_failed_total", "Total number of tasks finished with error.", o.failed.Load())
+	o.writeCounter(bw, "tasks_panicked_total", "Total number of tasks panicked.", o.panicked.Load())
+
+	started, finished := o.started.Load(), o.finished.Load()
+	o.writeGauge(bw, "tasks_running", "Number of tasks running.", started-min(finished, started))
+
+	created := o.goroutineCreated.Load()
+	o.writeCounter(bw, "goroutines_created_total", "Total number of goroutines created.", created)
+
+	name := o.namespace + "_goroutines_exited_total"
+	_, _ = fmt.Fprintf(bw, "# HELP %s Total number of goroutines exited by reason.\n", name)
+	_, _ = fmt.Fprintf(bw, "# TYPE %s counter\n", name)
+	var exited uint64
+	for reason := range o.goroutineExited {
+		cnt := o.goroutineExited[reason].Load()
+		exited += cnt
+		_, _ = fmt.Fprintf(bw, "%s{reason=%q} %d\n", name, GoroutineExitReason(reason).String(), cnt)
+	}
+	o.writeGauge(bw, "goroutines", "Number of goroutines alive.", created-min(exited, created))
+
+	o.writeHistogram(bw, "task_queue_wait_seconds", "Time tasks spent waiting in the queue.", o.QueueWait())
+	o.writeHistogram(bw, "task_run_seconds", "Time tasks spent running.", o.RunTime())
+
+	_ = bw.Flush()
+}
+
+func (o *MetricsObserver) writeCounter(w *bufio.Writer, name string, help string, val uint64) {
+	o.writeSingle(w, name, help, "counter", val)
+}
+
+func (o *MetricsObserver) writeGauge(w *bufio.Writer, name string, help string, val uint64) {
+	o.writeSingle(w, name, help, "gauge", val)
+}
+
+func (o *MetricsObserver) writeSingle(w *bufio.Writer, name string, help string, typ string, val uint64) {
+	name = o.namespace + "_" + name
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+	_, _ = fmt.Fprintf(w, "%s %d\n", name, val)
+}
+
+func (o *MetricsObserver) writeHistogram(w *bufio.Writer, name string, help string, s HistogramSnapshot) {
+	name = o.namespace + "_" + name
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	_, _ = fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i, le := range s.Buckets {
+		_, _ = fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, strconv.FormatFloat(le, 'g', -1, 64), s.Counts[i])
+	}
+	_, _ = fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, s.Count)
+	_, _ = fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(s.Sum, 'g', -1, 64))
+	_, _ = fmt.Fprintf(w, "%s_count %d\n", name, s.Count)
+}
+
+// WithNamespace 设置指标名称的前缀，默认为 "jit_pool"。
+func WithNamespace(namespace string) option.Opt[MetricsObserver] {
+	return func(o *MetricsObserver) {
+		o.namespace = namespace
+	}
+}
+
+// WithBuckets 设置直方图的桶上界（单位：秒），默认为 DefaultBuckets。
+func WithBuckets(buckets ...float64) option.Opt[MetricsObserver] {
+	return func(o *MetricsObserver) {
+		o.buckets = buckets
+	}
+}
+
+// NewMetricsObserver 创建 MetricsObserver。
+func NewMetricsObserver(opts ...option.Opt[MetricsObserver]) (*MetricsObserver, error) {
+	o := &MetricsObserver{
+		namespace: defaultMetricsNamespace,
+		buckets:   DefaultBuckets,
+	}
+	option.Apply(o, opts...)
+
+	if o.namespace == "" {
+		return nil, fmt.Errorf("%w: metrics namespace should not be empty", errInvalidParam)
+	}
+	if len(o.buckets) == 0 {
+		return nil, fmt.Errorf("%w: histogram buckets should not be empty", errInvalidParam)
+	}
+	for i := 1; i < len(o.buckets); i++ {
+		if o.buckets[i-1] >= o.buckets[i] {
+			return nil, fmt.Errorf("%w: histogram buckets should be in increasing order", errInvalidParam)
+		}
+	}
+	o.buckets = slices.Clone(o.buckets)
+
+	o.queueWait = newHistogram(o.buckets)
+	o.runTime = newHistogram(o.buckets)
+	return o, nil
+}
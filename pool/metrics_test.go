@@ -0,0 +1,143 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsObserver(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		opts    []option.Opt[MetricsObserver]
+		wantErr error
+	}{
+		{
+			name: "default",
+		}, {
+			name:    "empty namespace",
+			opts:    []option.Opt[MetricsObserver]{WithNamespace("")},
+			wantErr: errInvalidParam,
+		}, {
+			name:    "empty buckets",
+			opts:    []option.Opt[MetricsObserver]{WithBuckets()},
+			wantErr: errInvalidParam,
+		}, {
+			name:    "unordered buckets",
+			opts:    []option.Opt[MetricsObserver]{WithBuckets(1, 0.5)},
+			wantErr: errInvalidParam,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := NewMetricsObserver(tc.opts...)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.Equal(t, DefaultBuckets, o.QueueWait().Buckets)
+			}
+		})
+	}
+}
+
+func TestMetricsObserver_Histogram(t *testing.T) {
+	t.Parallel()
+
+	o, err := NewMetricsObserver(WithBuckets(0.01, 0.1, 1))
+	require.NoError(t, err)
+
+	for _, d := range []time.Duration{
+		5 * time.Millisecond,
+		10 * time.Millisecond, // 等于桶上界的值计入该桶
+		50 * time.Millisecond,
+		2 * time.Second,
+	} {
+		o.TaskFinished(d, nil)
+	}
+
+	s := o.RunTime()
+	assert.Equal(t, []float64{0.01, 0.1, 1}, s.Buckets)
+	assert.Equal(t, []uint64{2, 3, 3}, s.Counts)
+	assert.Equal(t, uint64(4), s.Count)
+	assert.InDelta(t, 2.065, s.Sum, 1e-9)
+
+	assert.Equal(t, uint64(0), o.QueueWait().Count)
+}
+
+func TestMetricsObserver_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	o, err := NewMetricsObserver(WithNamespace("test_pool"), WithBuckets(0.1, 1))
+	require.NoError(t, err)
+
+	p, err := NewBlockTaskPool(1, 4, WithObserver(o))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})))
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return errors.New("task error")
+	})))
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		panic("task panic")
+	})))
+
+	require.NoError(t, p.Start())
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	assert.ErrorIs(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})), ErrPoolIsClosed)
+
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	text := string(body)
+	for _, line := range []string{
+		"# TYPE test_pool_tasks_submitted_total counter",
+		"test_pool_tasks_submitted_total 3",
+		"test_pool_tasks_rejected_total 1",
+		"test_pool_tasks_started_total 3",
+		"test_pool_tasks_finished_total 3",
+		"test_pool_tasks_failed_total 2",
+		"test_pool_tasks_panicked_total 1",
+		"# TYPE test_pool_tasks_running gauge",
+		"test_pool_tasks_running 0",
+		"test_pool_goroutines_created_total 1",
+		`test_pool_goroutines_exited_total{reason="idle"} 0`,
+		`test_pool_goroutines_exited_total{reason="shutdown"} 1`,
+		"test_pool_goroutines 0",
+		"# TYPE test_pool_task_queue_wait_seconds histogram",
+		`test_pool_task_queue_wait_seconds_bucket{le="+Inf"} 3`,
+		"test_pool_task_queue_wait_seconds_count 3",
+		`test_pool_task_run_seconds_bucket{le="0.1"} 3`,
+		`test_pool_task_run_seconds_bucket{le="1"} 3`,
+		`test_pool_task_run_seconds_bucket{le="+Inf"} 3`,
+		"test_pool_task_run_seconds_count 3",
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+}
@@ -0,0 +1,163 @@
+package pool
+
+import (
+	"log/slog"
+	"time"
+)
+
+// GoroutineExitReason goroutine 退出的原因。
+type GoroutineExitReason int8
+
+const (
+	// GoroutineExitIdle 超时组中的 goroutine 在 maxIdleTime 内没有获取到任务，空闲超时退出。
+	GoroutineExitIdle GoroutineExitReason = iota
+	// GoroutineExitTemporary 临时 goroutine 执行完任务后队列没有积压任务，快速退出。
+	GoroutineExitTemporary
+	// GoroutineExitInterrupt 任务池被中断（ShutdownNow）退出。
+	GoroutineExitInterrupt
+	// GoroutineExitShutdown 任务池关闭（Shutdown）且剩余任务执行完成后退出。
+	GoroutineExitShutdown
+)
+
+func (r GoroutineExitReason) String() string {
+	switch r {
+	case GoroutineExitIdle:
+		return "idle"
+	case GoroutineExitTemporary:
+		return "temporary"
+	case GoroutineExitInterrupt:
+		return "interrupt"
+	case GoroutineExitShutdown:
+		return "shutdown"
+	default:
+		return "unknown"
+	}
+}
+
+// Observer 任务池观察者，接收任务池运行过程中产生的事件。
+//
+// 注意：
+//
+//	事件在任务池的调用路径上同步通知，Observer 的实现需要是并发安全的，且不能阻塞。
+//	只关心部分事件时可以内嵌 NopObserver。
+type Observer interface {
+	// TaskSubmitted 任务提交成功。
+	TaskSubmitted()
+	// TaskRejected 任务被拒绝，err 为拒绝的原因。
+	// 一般为 Submit 返回的错误，被拒绝策略丢弃的任务为 ErrTaskDiscarded。
+	TaskRejected(err error)
+	// TaskStarted 任务开始执行，queueWait 为任务在队列中等待的时间。
+	// 延时任务的等待时间从到达执行时间开始计算。
+	TaskStarted(queueWait time.Duration)
+	// TaskFinished 任务执行结束，runTime 为任务执行耗时，err 为任务返回的错误（包括 panic 转换的错误）。
+	TaskFinished(runTime time.Duration, err error)
+	// TaskPanicked 任务执行时发生 panic，在 TaskFinished 之前通知。
+	TaskPanicked(r any, stack []byte)
+
+	// GoroutineCreated 任务池创建了新的 goroutine。
+	GoroutineCreated(id int32)
+	// GoroutineExited goroutine 退出。
+	GoroutineExited(id int32, reason GoroutineExitReason)
+}
+
+var _ Observer = NopObserver{}
+
+// NopObserver 忽略所有事件的 Observer。
+type NopObserver struct{}
+
+func (NopObserver) TaskSubmitted()                             {}
+func (NopObserver) TaskRejected(error)                         {}
+func (NopObserver) TaskStarted(time.Duration)                  {}
+func (NopObserver) TaskFinished(time.Duration, error)          {}
+func (NopObserver) TaskPanicked(any, []byte)                   {}
+func (NopObserver) GoroutineCreated(int32)                     {}
+func (NopObserver) GoroutineExited(int32, GoroutineExitReason) {}
+
+var _ Observer = (*LogObserver)(nil)
+
+// LogObserver 通过 slog 记录 panic 和 goroutine 创建的 Observer，是任务池默认的 Observer。
+type LogObserver struct {
+	NopObserver
+
+	logger *slog.Logger
+}
+
+func (o *LogObserver) TaskPanicked(r any, stack []byte) {
+	o.log().Error(
+		"[jit] panic when running task",
+		"panic", r,
+		"stack", string(stack),
+	)
+}
+
+func (o *LogObserver) GoroutineCreated(id int32) {
+	o.log().Info("[jit] create new goroutine", "id", id)
+}
+
+func (o *LogObserver) log() *slog.Logger {
+	if o.logger == nil {
+		return slog.Default()
+	}
+	return o.logger
+}
+
+// NewLogObserver 创建 LogObserver，logger 为 nil 时使用 slog.Default()。
+func NewLogObserver(logger *slog.Logger) *LogObserver {
+	return &LogObserver{logger: logger}
+}
+
+// multiObserver 将事件依次通知给多个 Observer。
+type multiObserver []Observer
+
+func (m multiObserver) TaskSubmitted() {
+	for _, o := range m {
+		o.TaskSubmitted()
+	}
+}
+
+func (m multiObserver) TaskRejected(err error) {
+	for _, o := range m {
+		o.TaskRejected(err)
+	}
+}
+
+func (m multiObserver) TaskStarted(queueWait time.Duration) {
+	for _, o := range m {
+		o.TaskStarted(queueWait)
+	}
+}
+
+func (m multiObserver) TaskFinished(runTime time.Duration, err error) {
+	for _, o := range m {
+		o.TaskFinished(runTime, err)
+	}
+}
+
+func (m multiObserver) TaskPanicked(r any, stack []byte) {
+	for _, o := range m {
+		o.TaskPanicked(r, stack)
+	}
+}
+
+func (m multiObserver) GoroutineCreated(id int32) {
+	for _, o := range m {
+		o.GoroutineCreated(id)
+	}
+}
+
+func (m multiObserver) GoroutineExited(id int32, reason GoroutineExitReason) {
+	for _, o := range m {
+		o.GoroutineExited(id, reason)
+	}
+}
+
+// MultiObserver 组合多个 Observer，事件按顺序通知给每个 Observer。
+func MultiObserver(observers ...Observer) Observer {
+	res := make(multiObserver, 0, len(observers))
+	for _, o := range observers {
+		if o != nil {
+			res = append(res, o)
+		}
+	}
+	return res
+}
@@ -0,0 +1,180 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordObserver 记录事件的 Observer。
+type recordObserver struct {
+	mu sync.Mutex
+
+	submitted int
+	rejected  []error
+	started   int
+	finished  []error
+	panicked  []any
+	stacks    [][]byte
+
+	created []int32
+	exited  map[GoroutineExitReason]int
+}
+
+func (o *recordObserver) TaskSubmitted() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.submitted++
+}
+
+func (o *recordObserver) TaskRejected(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rejected = append(o.rejected, err)
+}
+
+func (o *recordObserver) TaskStarted(time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started++
+}
+
+func (o *recordObserver) TaskFinished(_ time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.finished = append(o.finished, err)
+}
+
+func (o *recordObserver) TaskPanicked(r any, stack []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.panicked = append(o.panicked, r)
+	o.stacks = append(o.stacks, stack)
+}
+
+func (o *recordObserver) GoroutineCreated(id int32) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.created = append(o.created, id)
+}
+
+func (o *recordObserver) GoroutineExited(_ int32, reason GoroutineExitReason) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.exited[reason]++
+}
+
+func (o *recordObserver) exitedCnt(reason GoroutineExitReason) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.exited[reason]
+}
+
+func newRecordObserver() *recordObserver {
+	return &recordObserver{exited: make(map[GoroutineExitReason]int)}
+}
+
+func TestBlockTaskPool_Observer(t *testing.T) {
+	t.Parallel()
+
+	obs := newRecordObserver()
+	p, err := NewBlockTaskPool(2, 4, WithObserver(obs))
+	require.NoError(t, err)
+
+	errTask := errors.New("task error")
+	tasks := []Task{
+		TaskFunc(func(ctx context.Context) error { return nil }),
+		TaskFunc(func(ctx context.Context) error { return errTask }),
+		TaskFunc(func(ctx context.Context) error { panic("task panic") }),
+	}
+	for _, task := range tasks {
+		require.NoError(t, p.Submit(context.Background(), task))
+	}
+	assert.ErrorIs(t, p.Submit(context.Background(), nil), errInvalidTask)
+
+	require.NoError(t, p.Start())
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	assert.ErrorIs(t, p.Submit(context.Background(), tasks[0]), ErrPoolIsClosed)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+
+	assert.Equal(t, 3, obs.submitted)
+	assert.Len(t, obs.rejected, 2)
+	assert.ErrorIs(t, obs.rejected[0], errInvalidTask)
+	assert.ErrorIs(t, obs.rejected[1], ErrPoolIsClosed)
+
+	assert.Equal(t, 3, obs.started)
+	assert.Len(t, obs.finished, 3)
+	failed := 0
+	for _, err := range obs.finished {
+		if err != nil {
+			failed++
+		}
+	}
+	assert.Equal(t, 2, failed)
+
+	assert.Equal(t, []any{"task panic"}, obs.panicked)
+	assert.NotEmpty(t, obs.stacks[0])
+
+	assert.Len(t, obs.created, 2)
+	assert.Equal(t, 2, obs.exited[GoroutineExitShutdown])
+}
+
+func TestBlockTaskPool_ObserverGoroutineExit(t *testing.T) {
+	t.Parallel()
+
+	obs := newRecordObserver()
+	p := runningPool(t, 1, 1, WithMaxG(2), WithMaxIdleTime(10*time.Millisecond), WithObserver(obs))
+	waitForIdleG(t, p, 1)
+
+	wait := make(chan struct{})
+	blockTask := TaskFunc(func(ctx context.Context) error {
+		<-wait
+		return nil
+	})
+
+	// 第一个任务占用永久 goroutine，第二个任务积压在队列中触发扩容
+	require.NoError(t, p.Submit(context.Background(), blockTask))
+	waitForIdleG(t, p, 0)
+	require.NoError(t, p.Submit(context.Background(), blockTask))
+	close(wait)
+
+	// 扩容的 goroutine 空闲超时退出
+	assert.Eventually(t, func() bool {
+		return obs.exitedCnt(GoroutineExitIdle) == 1
+	}, time.Second, time.Millisecond)
+
+	_, err := p.ShutdownNow()
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return obs.exitedCnt(GoroutineExitInterrupt) == 1
+	}, time.Second, time.Millisecond)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	assert.Len(t, obs.created, 2)
+}
+
+func TestMultiObserver(t *testing.T) {
+	t.Parallel()
+
+	obs1, obs2 := newRecordObserver(), newRecordObserver()
+	obs := MultiObserver(obs1, nil, obs2)
+
+	obs.TaskSubmitted()
+	obs.GoroutineExited(1, GoroutineExitTemporary)
+
+	for _, o := range []*recordObserver{obs1, obs2} {
+		assert.Equal(t, 1, o.submitted)
+		assert.Equal(t, 1, o.exitedCnt(GoroutineExitTemporary))
+	}
+}
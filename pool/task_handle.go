@@ -0,0 +1,155 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+)
+
+// TaskState 任务的执行状态。
+type TaskState int32
+
+const (
+	// TaskStateQueued 任务在队列中等待执行（包括未到执行时间的延时任务）。
+	TaskStateQueued TaskState = iota
+	// TaskStateRunning 任务正在执行。
+	TaskStateRunning
+	// TaskStateDone 任务执行完成（包括执行失败）。
+	TaskStateDone
+	// TaskStateCanceled 任务在开始执行前被取消或丢弃。
+	TaskStateCanceled
+)
+
+func (s TaskState) String() string {
+	switch s {
+	case TaskStateQueued:
+		return "queued"
+	case TaskStateRunning:
+		return "running"
+	case TaskStateDone:
+		return "done"
+	case TaskStateCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// TaskHandle 已提交任务的句柄，用于查询任务状态和取消未开始执行的任务。
+type TaskHandle struct {
+	state atomic.Int32
+
+	done chan struct{}
+	err  error // done 关闭前写入
+}
+
+// State 返回任务当前的状态。
+func (h *TaskHandle) State() TaskState {
+	return TaskState(h.state.Load())
+}
+
+// Cancel 取消在队列中等待的任务，返回是否取消成功。任务已经开始执行或已经结束时取消失败。
+// 取消后 Err 返回的 error 满足 errors.Is(err, context.Canceled)。
+//
+// 注意：
+//
+//	被取消的任务在出队时才会被丢弃，出队前仍然占用任务队列的容量。
+func (h *TaskHandle) Cancel() bool {
+	return h.cancel(errTaskIsCanceled)
+}
+
+// Done 返回任务结束（包括执行完成和被取消）时关闭的 chan。
+func (h *TaskHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Err 返回任务的执行错误或取消原因，任务未结束时返回 nil。
+func (h *TaskHandle) Err() error {
+	select {
+	case <-h.done:
+		return h.err
+	default:
+		return nil
+	}
+}
+
+// Wait 阻塞等待任务结束并返回 Err，ctx 结束时返回 ctx.Err()。
+func (h *TaskHandle) Wait(ctx context.Context) error {
+	select {
+	case <-h.done:
+		return h.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start 开始执行任务前调用，任务已经被取消时返回 false。
+func (h *TaskHandle) start() bool {
+	return h.state.CompareAndSwap(int32(TaskStateQueued), int32(TaskStateRunning))
+}
+
+// finish 任务执行完成后调用。
+func (h *TaskHandle) finish(err error) {
+	h.err = err
+	h.state.Store(int32(TaskStateDone))
+	close(h.done)
+}
+
+// cancel 以 err 为原因取消在队列中等待的任务。
+func (h *TaskHandle) cancel(err error) bool {
+	if !h.state.CompareAndSwap(int32(TaskStateQueued), int32(TaskStateCanceled)) {
+		return false
+	}
+	h.err = err
+	close(h.done)
+	return true
+}
+
+func newTaskHandle() *TaskHandle {
+	return &TaskHandle{done: make(chan struct{})}
+}
+
+// TaskOptions 提交单个任务时的参数。
+type TaskOptions struct {
+	priority int
+	runAt    time.Time
+	timeout  time.Duration
+}
+
+// WithTaskPriority 设置任务的优先级，priority 越大越先执行，默认为 0。
+func WithTaskPriority(priority int) option.Opt[TaskOptions] {
+	return func(o *TaskOptions) {
+		o.priority = priority
+	}
+}
+
+// WithTaskRunAt 设置任务可执行的时间，任务在 runAt 之后才可以被执行。
+func WithTaskRunAt(runAt time.Time) option.Opt[TaskOptions] {
+	return func(o *TaskOptions) {
+		o.runAt = runAt
+	}
+}
+
+// WithTaskTimeout 设置任务的执行超时时间，从任务开始执行时计时，超时后通过 ctx 通知任务。
+// 优先于 WithDefaultTaskTimeout 设置的默认超时时间。
+func WithTaskTimeout(timeout time.Duration) option.Opt[TaskOptions] {
+	return func(o *TaskOptions) {
+		o.timeout = timeout
+	}
+}
+
+// inheritCtx 任务执行使用的 ctx。
+// 值从提交任务的 ctx 中继承，生命周期（Deadline / Done / Err）与任务池的 ctx 一致。
+type inheritCtx struct {
+	context.Context
+	values context.Context // context.WithoutCancel 包装的提交任务的 ctx
+}
+
+func (c *inheritCtx) Value(key any) any {
+	if v := c.values.Value(key); v != nil {
+		return v
+	}
+	return c.Context.Value(key)
+}
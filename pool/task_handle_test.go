@@ -0,0 +1,285 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestBlockTaskPool_ContextValues(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewBlockTaskPool(1, 4)
+	require.NoError(t, err)
+
+	type result struct {
+		val any
+		err error
+	}
+	results := make(chan result, 1)
+
+	// 任务池启动前提交，提交后 ctx 被取消
+	valCtx := context.WithValue(context.Background(), ctxKey{}, "val")
+	ctx, cancel := context.WithCancel(valCtx)
+	require.NoError(t, p.Submit(ctx, TaskFunc(func(ctx context.Context) error {
+		results <- result{val: ctx.Value(ctxKey{}), err: ctx.Err()}
+		return nil
+	})))
+	cancel()
+
+	require.NoError(t, p.Start())
+	res := <-results
+	assert.Equal(t, "val", res.val)
+	// 任务不受提交时 ctx 的取消影响
+	assert.NoError(t, res.err)
+
+	// 任务池中断时通知任务
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(valCtx, TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		results <- result{val: ctx.Value(ctxKey{}), err: ctx.Err()}
+		return nil
+	})))
+	<-started
+
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+	res = <-results
+	assert.Equal(t, "val", res.val)
+	assert.ErrorIs(t, res.err, context.Canceled)
+}
+
+func TestBlockTaskPool_TaskTimeout(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		poolOpts []option.Opt[BlockTaskPool]
+		taskOpts []option.Opt[TaskOptions]
+		wantErr  error
+	}{
+		{
+			name: "no timeout",
+		}, {
+			name:     "default task timeout",
+			poolOpts: []option.Opt[BlockTaskPool]{WithDefaultTaskTimeout(10 * time.Millisecond)},
+			wantErr:  context.DeadlineExceeded,
+		}, {
+			name:     "task timeout",
+			taskOpts: []option.Opt[TaskOptions]{WithTaskTimeout(10 * time.Millisecond)},
+			wantErr:  context.DeadlineExceeded,
+		}, {
+			name:     "task timeout takes precedence",
+			poolOpts: []option.Opt[BlockTaskPool]{WithDefaultTaskTimeout(time.Minute)},
+			taskOpts: []option.Opt[TaskOptions]{WithTaskTimeout(10 * time.Millisecond)},
+			wantErr:  context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := runningPool(t, 1, 1, tc.poolOpts...)
+			h, err := p.SubmitTask(context.Background(), TaskFunc(func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+					return nil
+				}
+			}), tc.taskOpts...)
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, h.Wait(context.Background()), tc.wantErr)
+			assert.Equal(t, TaskStateDone, h.State())
+
+			done, err := p.Shutdown()
+			require.NoError(t, err)
+			<-done
+		})
+	}
+
+	_, err := NewBlockTaskPool(1, 1, WithDefaultTaskTimeout(-1))
+	assert.ErrorIs(t, err, errInvalidParam)
+}
+
+func TestBlockTaskPool_SubmitTask(t *testing.T) {
+	t.Parallel()
+
+	p, wait, ids := fullPool(t, WithRejectPolicy(RejectPolicyAbort))
+	require.NoError(t, p.SetQueueSize(3))
+
+	_, err := p.SubmitTask(context.Background(), nil)
+	assert.ErrorIs(t, err, errInvalidTask)
+
+	taskErr := errors.New("task error")
+	h2, err := p.SubmitTask(context.Background(), TaskFunc(func(ctx context.Context) error {
+		ids <- 2
+		return taskErr
+	}))
+	require.NoError(t, err)
+	h3, err := p.SubmitTask(context.Background(), TaskFunc(func(ctx context.Context) error {
+		ids <- 3
+		return nil
+	}), WithTaskPriority(1))
+	require.NoError(t, err)
+
+	// 取消排队中的任务
+	assert.Equal(t, TaskStateQueued, h3.State())
+	assert.Nil(t, h3.Err())
+	assert.True(t, h3.Cancel())
+	assert.False(t, h3.Cancel())
+	assert.Equal(t, TaskStateCanceled, h3.State())
+	assert.ErrorIs(t, h3.Err(), context.Canceled)
+
+	// 被取消的任务在出队前仍然占用队列容量
+	_, err = p.SubmitTask(context.Background(), &idTask{id: 4})
+	assert.ErrorIs(t, err, ErrTaskRejected)
+
+	close(wait)
+	assert.ErrorIs(t, h2.Wait(context.Background()), taskErr)
+	assert.Equal(t, TaskStateDone, h2.State())
+	assert.False(t, h2.Cancel())
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+	close(ids)
+
+	res := make([]int, 0, 2)
+	for id := range ids {
+		res = append(res, id)
+	}
+	assert.Equal(t, []int{1, 2}, res)
+}
+
+func TestBlockTaskPool_SubmitTaskRunning(t *testing.T) {
+	t.Parallel()
+
+	p := runningPool(t, 1, 1)
+
+	wait := make(chan struct{})
+	started := make(chan struct{})
+	h, err := p.SubmitTask(context.Background(), TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	}))
+	require.NoError(t, err)
+	<-started
+
+	// 执行中的任务不能取消
+	assert.Equal(t, TaskStateRunning, h.State())
+	assert.False(t, h.Cancel())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	assert.ErrorIs(t, h.Wait(ctx), context.DeadlineExceeded)
+	cancel()
+
+	close(wait)
+	<-h.Done()
+	assert.NoError(t, h.Err())
+	assert.Equal(t, TaskStateDone, h.State())
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestBlockTaskPool_SubmitTaskDiscarded(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		opts    []option.Opt[BlockTaskPool]
+		wantErr error
+	}{
+		{
+			name:    "discard",
+			opts:    []option.Opt[BlockTaskPool]{WithRejectPolicy(RejectPolicyDiscard)},
+			wantErr: ErrTaskDiscarded,
+		}, {
+			name: "reject handler",
+			opts: []option.Opt[BlockTaskPool]{WithRejectHandler(func(ctx context.Context, task Task) error {
+				return nil
+			})},
+			wantErr: ErrTaskRejected,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, wait, _ := fullPool(t, tc.opts...)
+			defer close(wait)
+
+			h, err := p.SubmitTask(context.Background(), &idTask{id: 2})
+			require.NoError(t, err)
+			assert.Equal(t, TaskStateCanceled, h.State())
+			assert.ErrorIs(t, h.Err(), tc.wantErr)
+
+			_, err = p.ShutdownNow()
+			require.NoError(t, err)
+		})
+	}
+
+	// 丢弃队首任务
+	p, wait, _ := fullPool(t, WithRejectPolicy(RejectPolicyDiscardOldest))
+	defer close(wait)
+
+	h2, err := p.SubmitTask(context.Background(), &idTask{id: 2})
+	require.NoError(t, err)
+	h3, err := p.SubmitTask(context.Background(), &idTask{id: 3})
+	require.NoError(t, err)
+	assert.ErrorIs(t, h2.Err(), ErrTaskDiscarded)
+	assert.Equal(t, TaskStateQueued, h3.State())
+
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+}
+
+func TestBlockTaskPool_SubmitTaskShutdownNow(t *testing.T) {
+	t.Parallel()
+
+	p, wait, _ := fullPool(t)
+	defer close(wait)
+	require.NoError(t, p.SetQueueSize(2))
+
+	h2, err := p.SubmitTask(context.Background(), &idTask{id: 2})
+	require.NoError(t, err)
+	h3, err := p.SubmitTask(context.Background(), &idTask{id: 3}, WithTaskRunAt(time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	h4, err := p.SubmitTask(context.Background(), &idTask{id: 4}, WithTaskRunAt(time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	assert.True(t, h4.Cancel())
+
+	tasks, err := p.ShutdownNow()
+	require.NoError(t, err)
+	// 被取消的任务不返回
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, 3, tasks[2].(*taskWrapper).task.(*idTask).id)
+
+	assert.ErrorIs(t, h2.Err(), ErrPoolIsClosed)
+	assert.ErrorIs(t, h3.Err(), ErrPoolIsClosed)
+	assert.ErrorIs(t, h4.Err(), context.Canceled)
+}
+
+func TestTaskState_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "queued", TaskStateQueued.String())
+	assert.Equal(t, "running", TaskStateRunning.String())
+	assert.Equal(t, "done", TaskStateDone.String())
+	assert.Equal(t, "canceled", TaskStateCanceled.String())
+	assert.Equal(t, "unknown", TaskState(-1).String())
+}
@@ -0,0 +1,299 @@
+package pool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/queue"
+	"github.com/JrMarcco/jit/xsync"
+)
+
+var errQueueIsClosed = errors.New("[jit] task queue is closed")
+
+// queuedTask 队列中的任务。
+type queuedTask struct {
+	task     Task
+	priority int       // 优先级，值越大越先执行
+	runAt    time.Time // 任务可执行的时间，零值表示立即执行
+	seq      int64     // 入队序号，保证同优先级的任务先进先出
+	readyAt  time.Time // 任务进入 ready 队列的时间，用于统计排队等待时间
+
+	handle   *TaskHandle // 通过 SubmitTask 提交的任务的句柄
+	internal bool        // 任务池内部的任务（例如 keyedRunner），不计入 Observer 的任务事件，也不经过限流器
+}
+
+// cancel 以 err 为原因取消任务的句柄并通知任务被丢弃，返回任务是否仍需要处理（没有句柄或取消成功）。
+func (qt *queuedTask) cancel(err error) bool {
+	if qt.handle != nil && !qt.handle.cancel(err) {
+		return false
+	}
+	dropTask(qt.task, err)
+	return true
+}
+
+// dropper 任务被任务池丢弃（不会再被执行）时得到通知。
+type dropper interface {
+	drop(err error)
+}
+
+// dropTask 以 err 为原因通知任务被丢弃。
+func dropTask(task Task, err error) {
+	if tw, ok := task.(*taskWrapper); ok {
+		task = tw.task
+	}
+	if d, ok := task.(dropper); ok {
+		d.drop(err)
+	}
+}
+
+// taskQueue 任务池的任务队列，支持优先级和延时任务。
+//
+// 队列分为两部分：
+//
+//	ready:   可执行的任务，按优先级从高到低出队，同优先级先进先出。受 capacity 限制。
+//	delayed: 未到执行时间的延时任务，按执行时间排序。不受 capacity 限制。
+//
+// 延时任务在到达执行时间后由 take 移入 ready，与普通任务一起按优先级出队。
+type taskQueue struct {
+	mu sync.Mutex
+
+	capacity int
+	ready    *queue.PriorityQueue[*queuedTask]
+	delayed  *queue.PriorityQueue[*queuedTask]
+
+	seq     int64
+	waiters int // 阻塞在 take 的 goroutine 数
+	closed  bool
+
+	notEmpty *xsync.Cond
+	notFull  *xsync.Cond
+
+	clock clock.Clock
+}
+
+// backlog 返回积压的任务数，即 ready 队列中不能被等待中的 goroutine 立即取走的任务数。
+// 与 chan 一致，存在等待任务的 goroutine 时，任务直接交付给 goroutine，不占用队列容量（capacity 为 0 时即直接交付）。
+func (q *taskQueue) backlog() int {
+	return max(q.ready.Len()-q.waiters, 0)
+}
+
+func (q *taskQueue) isFull() bool {
+	return q.ready.Len()-q.waiters >= q.capacity
+}
+
+// offer 非阻塞入队，队列已满时返回 false。
+// 延时任务总是能成功入队。
+func (q *taskQueue) offer(qt *queuedTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	if !qt.runAt.IsZero() && qt.runAt.After(q.clock.Now()) {
+		q.seq++
+		qt.seq = q.seq
+		// 无界队列，error 可以忽略。
+		_ = q.delayed.Enqueue(qt)
+
+		if head, _ := q.delayed.Peek(); head == qt {
+			// 新任务的执行时间最早，唤醒等待的 goroutine 重新计算等待时间。
+			q.notEmpty.Broadcast()
+		}
+		return true
+	}
+
+	if q.isFull() {
+		return false
+	}
+
+	q.pushReady(qt)
+	return true
+}
+
+func (q *taskQueue) pushReady(qt *queuedTask) {
+	q.seq++
+	qt.seq = q.seq
+	qt.readyAt = q.clock.Now()
+	// 容量由 isFull 控制，底层为无界队列，error 可以忽略。
+	_ = q.ready.Enqueue(qt)
+	q.notEmpty.Signal()
+}
+
+// full 判断 ready 队列是否已满。
+func (q *taskQueue) full() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return !q.closed && q.isFull()
+}
+
+// poll 非阻塞地取出 ready 队列的队首任务，队列为空时返回 nil。
+func (q *taskQueue) poll() *queuedTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	qt, err := q.ready.Dequeue()
+	if err != nil {
+		return nil
+	}
+	q.notFull.Signal()
+	return qt
+}
+
+// waitNotFull 阻塞直到 ready 队列不满、队列被关闭或 ctx 结束。
+func (q *taskQueue) waitNotFull(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && q.isFull() {
+		if err := q.notFull.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// take 阻塞获取一个可执行的任务，直到获取到任务或 ctx 结束。
+// 队列被关闭且没有剩余任务（包括延时任务）时返回 errQueueIsClosed。
+func (q *taskQueue) take(ctx context.Context) (*queuedTask, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		q.promoteDelayed()
+
+		if q.ready.Len() > 0 {
+			qt, _ := q.ready.Dequeue()
+			q.notFull.Signal()
+			return qt, nil
+		}
+
+		if q.closed && q.delayed.Len() == 0 {
+			return nil, errQueueIsClosed
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if head, err := q.delayed.Peek(); err == nil {
+			// 最多等待到最早的延时任务可执行。
+			waitCtx, cancel = clock.WithDeadline(ctx, q.clock, head.runAt)
+		}
+
+		q.waiters++
+		// 有 goroutine 等待任务时，队列允许接收新任务。
+		q.notFull.Signal()
+		_ = q.notEmpty.Wait(waitCtx)
+		q.waiters--
+		cancel()
+	}
+}
+
+// promoteDelayed 将到达执行时间的延时任务移入 ready 队列。
+func (q *taskQueue) promoteDelayed() {
+	now := q.clock.Now()
+	for {
+		head, err := q.delayed.Peek()
+		if err != nil || head.runAt.After(now) {
+			return
+		}
+
+		_, _ = q.delayed.Dequeue()
+		q.pushReady(head)
+	}
+}
+
+// close 关闭队列，拒绝新任务入队，已入队的任务仍然可以被取出。
+func (q *taskQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// drain 取出所有剩余任务，先是 ready 队列中的任务（按出队顺序），然后是延时任务（按执行时间）。
+// 已经通过句柄取消的任务被丢弃，其他任务的句柄以 ErrPoolIsClosed 为原因取消。
+func (q *taskQueue) drain() []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := make([]Task, 0, q.ready.Len()+q.delayed.Len())
+	for q.ready.Len() > 0 {
+		qt, _ := q.ready.Dequeue()
+		if qt.cancel(ErrPoolIsClosed) {
+			tasks = append(tasks, qt.task)
+		}
+	}
+	for q.delayed.Len() > 0 {
+		qt, _ := q.delayed.Dequeue()
+		if qt.cancel(ErrPoolIsClosed) {
+			tasks = append(tasks, qt.task)
+		}
+	}
+	return tasks
+}
+
+// len 返回积压的任务数。
+func (q *taskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.backlog()
+}
+
+// delayedLen 返回未到执行时间的延时任务数。
+func (q *taskQueue) delayedLen() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.delayed.Len()
+}
+
+func (q *taskQueue) cap() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.capacity
+}
+
+// setCap 调整 ready 队列的容量，已入队的任务不受影响。
+func (q *taskQueue) setCap(capacity int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.capacity = capacity
+	q.notFull.Broadcast()
+}
+
+func newTaskQueue(capacity int, clk clock.Clock) *taskQueue {
+	// 比较器不为 nil，error 可以忽略。
+	ready, _ := queue.NewPriorityQueue[*queuedTask](0, func(src, dst *queuedTask) int {
+		if src.priority != dst.priority {
+			// 优先级高的先出队
+			return cmp.Compare(dst.priority, src.priority)
+		}
+		return cmp.Compare(src.seq, dst.seq)
+	})
+	delayed, _ := queue.NewPriorityQueue[*queuedTask](0, func(src, dst *queuedTask) int {
+		if c := src.runAt.Compare(dst.runAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(src.seq, dst.seq)
+	})
+
+	q := &taskQueue{
+		capacity: capacity,
+		ready:    ready,
+		delayed:  delayed,
+		clock:    clk,
+	}
+	q.notEmpty = xsync.NewCond(&q.mu)
+	q.notFull = xsync.NewCond(&q.mu)
+	return q
+}
@@ -0,0 +1,130 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type idTask struct {
+	id int
+}
+
+func (t *idTask) Run(context.Context) error {
+	return nil
+}
+
+func taskIds(tasks []Task) []int {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.(*idTask).id)
+	}
+	return ids
+}
+
+func TestTaskQueue_Take(t *testing.T) {
+	now := time.Now()
+
+	tcs := []struct {
+		name    string
+		tasks   []*queuedTask
+		wantIds []int
+	}{
+		{
+			name: "fifo",
+			tasks: []*queuedTask{
+				{task: &idTask{id: 1}},
+				{task: &idTask{id: 2}},
+				{task: &idTask{id: 3}},
+			},
+			wantIds: []int{1, 2, 3},
+		}, {
+			name: "priority",
+			tasks: []*queuedTask{
+				{task: &idTask{id: 1}},
+				{task: &idTask{id: 2}, priority: 1},
+				{task: &idTask{id: 3}, priority: -1},
+				{task: &idTask{id: 4}, priority: 1},
+			},
+			wantIds: []int{2, 4, 1, 3},
+		}, {
+			name: "delayed",
+			tasks: []*queuedTask{
+				{task: &idTask{id: 1}, runAt: now.Add(20 * time.Millisecond)},
+				{task: &idTask{id: 2}, runAt: now.Add(10 * time.Millisecond)},
+				{task: &idTask{id: 3}},
+				// run at a past time is the same as immediate
+				{task: &idTask{id: 4}, runAt: now.Add(-time.Second)},
+			},
+			wantIds: []int{3, 4, 2, 1},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newTaskQueue(len(tc.tasks), clock.Real())
+			for _, qt := range tc.tasks {
+				require.True(t, q.offer(qt))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			tasks := make([]Task, 0, len(tc.tasks))
+			for range tc.tasks {
+				qt, err := q.take(ctx)
+				require.NoError(t, err)
+				tasks = append(tasks, qt.task)
+			}
+			assert.Equal(t, tc.wantIds, taskIds(tasks))
+		})
+	}
+}
+
+func TestTaskQueue_Capacity(t *testing.T) {
+	q := newTaskQueue(1, clock.Real())
+
+	assert.True(t, q.offer(&queuedTask{task: &idTask{id: 1}}))
+	assert.False(t, q.offer(&queuedTask{task: &idTask{id: 2}}))
+	// delayed task does not take up the capacity
+	assert.True(t, q.offer(&queuedTask{task: &idTask{id: 3}, runAt: time.Now().Add(time.Hour)}))
+	assert.Equal(t, 1, q.len())
+	assert.Equal(t, 1, q.delayedLen())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.waitNotFull(ctx), context.DeadlineExceeded)
+
+	// waiting goroutine takes the task directly even if the capacity is 0
+	handoff := newTaskQueue(0, clock.Real())
+	go func() {
+		qt, err := handoff.take(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, qt.task.(*idTask).id)
+	}()
+	require.NoError(t, handoff.waitNotFull(context.Background()))
+	assert.True(t, handoff.offer(&queuedTask{task: &idTask{id: 1}}))
+}
+
+func TestTaskQueue_Close(t *testing.T) {
+	q := newTaskQueue(4, clock.Real())
+	now := time.Now()
+
+	require.True(t, q.offer(&queuedTask{task: &idTask{id: 1}}))
+	require.True(t, q.offer(&queuedTask{task: &idTask{id: 2}, priority: 1}))
+	require.True(t, q.offer(&queuedTask{task: &idTask{id: 3}, runAt: now.Add(2 * time.Hour)}))
+	require.True(t, q.offer(&queuedTask{task: &idTask{id: 4}, runAt: now.Add(time.Hour)}))
+
+	q.close()
+	assert.False(t, q.offer(&queuedTask{task: &idTask{id: 5}}))
+	assert.NoError(t, q.waitNotFull(context.Background()))
+
+	assert.Equal(t, []int{2, 1, 4, 3}, taskIds(q.drain()))
+
+	_, err := q.take(context.Background())
+	assert.ErrorIs(t, err, errQueueIsClosed)
+}
@@ -40,6 +40,7 @@ type State struct {
 	GoroutineCnt int32
 
 	WaitingCnt int32
+	DelayedCnt int32 // 未到执行时间的延时任务数
 	RunningCnt int32
 
 	PoolState int32
@@ -0,0 +1,110 @@
+package pool
+
+import (
+	"sync"
+	"time"
+)
+
+// dequeTask 本地队列中的任务。
+type dequeTask struct {
+	task      Task
+	enqueueAt time.Time
+}
+
+// workDeque 工作 goroutine 的本地任务队列，有界的环形缓冲区。
+//
+// 所属的工作 goroutine 从队首取任务，其他空闲的工作 goroutine 从队尾窃取任务。
+// 每个工作 goroutine 有独立的队列和锁，提交和获取任务的锁竞争分散到各个队列上。
+type workDeque struct {
+	mu sync.Mutex
+
+	buf    []dequeTask
+	head   int // 队首下标
+	size   int
+	closed bool // 关闭后拒绝新任务入队
+}
+
+// push 任务入队尾，队列已满或已关闭时返回 false。
+func (d *workDeque) push(dt dequeTask) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed || d.size == len(d.buf) {
+		return false
+	}
+
+	d.buf[(d.head+d.size)%len(d.buf)] = dt
+	d.size++
+	return true
+}
+
+// pop 从队首取出一个任务。
+func (d *workDeque) pop() (dequeTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.size == 0 {
+		return dequeTask{}, false
+	}
+
+	dt := d.buf[d.head]
+	d.buf[d.head] = dequeTask{}
+	d.head = (d.head + 1) % len(d.buf)
+	d.size--
+	return dt, true
+}
+
+// steal 从队尾窃取一个任务。
+func (d *workDeque) steal() (dequeTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.size == 0 {
+		return dequeTask{}, false
+	}
+
+	idx := (d.head + d.size - 1) % len(d.buf)
+	dt := d.buf[idx]
+	d.buf[idx] = dequeTask{}
+	d.size--
+	return dt, true
+}
+
+// drain 取出所有任务。
+func (d *workDeque) drain() []dequeTask {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	res := make([]dequeTask, 0, d.size)
+	for d.size > 0 {
+		res = append(res, d.buf[d.head])
+		d.buf[d.head] = dequeTask{}
+		d.head = (d.head + 1) % len(d.buf)
+		d.size--
+	}
+	return res
+}
+
+// close 关闭队列，拒绝新任务入队，已入队的任务仍然可以取出。
+func (d *workDeque) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+}
+
+// isEmpty 判断队列是否为空，同时返回队列是否已关闭。
+func (d *workDeque) isEmpty() (empty bool, closed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.size == 0, d.closed
+}
+
+func (d *workDeque) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.size
+}
+
+func newWorkDeque(capacity int) *workDeque {
+	return &workDeque{buf: make([]dequeTask, capacity)}
+}
@@ -0,0 +1,422 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/xsync"
+)
+
+var _ TaskPool = (*WorkStealingTaskPool)(nil)
+
+// WorkStealingTaskPool 基于工作窃取的任务池。
+//
+// 每个工作 goroutine 有自己的本地任务队列，提交的任务轮流分配到各个队列。
+// 工作 goroutine 优先执行本地队列的任务，本地队列为空时从其他队列的队尾窃取任务。
+// 与 BlockTaskPool 所有任务经过同一个队列相比，提交和获取任务的锁竞争分散到了各个队列上，
+// 适合提交频率高、任务执行时间短的场景。
+//
+// WorkStealingTaskPool 的 goroutine 数量固定，不支持优先级和延时任务。
+type WorkStealingTaskPool struct {
+	mu sync.Mutex // 保护 notEmpty / notFull 的等待和通知
+
+	state    int32 // 内部状态
+	workerG  int32 // 工作 goroutine 数量
+	aliveG   int32 // 存活的工作 goroutine 数量
+	runningG int32 // 正在执行任务的 goroutine 数量
+	idleG    int32 // 等待任务的 goroutine 数量
+	blockedS int32 // 等待队列空闲位置的提交者数量
+
+	deques []*workDeque // 工作 goroutine 的本地队列
+	next   uint32       // 下一个任务分配的队列
+
+	notEmpty *xsync.Cond
+	notFull  *xsync.Cond
+
+	clock            clock.Clock // 计算提交超时和任务耗时的时钟
+	submitTimeout    time.Duration
+	errHandler       func(ctx context.Context, err error) // 错误处理器
+	errHandleTimeout time.Duration
+	observer         Observer // 任务池事件观察者
+
+	interruptCtx        context.Context
+	interruptCancelFunc context.CancelFunc
+}
+
+// Submit 提交一个任务。
+// 所有本地队列都已满时，调用者会被阻塞直到有空闲位置或者 ctx 结束（未设置超时时间时使用提交超时时间）。
+func (p *WorkStealingTaskPool) Submit(ctx context.Context, task Task) error {
+	if err := p.submit(ctx, task); err != nil {
+		p.observer.TaskRejected(err)
+		return err
+	}
+	p.observer.TaskSubmitted()
+	return nil
+}
+
+func (p *WorkStealingTaskPool) submit(ctx context.Context, task Task) error {
+	if task == nil {
+		return errInvalidTask
+	}
+	if err := p.checkSubmit(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	dt := dequeTask{
+		task: &taskWrapper{
+			task:     task,
+			observer: p.observer,
+			values:   context.WithoutCancel(ctx),
+		},
+		enqueueAt: p.clock.Now(),
+	}
+
+	// 快速路径，不需要获取任务池的锁。
+	if p.offer(dt) {
+		p.notify(&p.idleG, p.notEmpty)
+		return nil
+	}
+
+	// 所有队列已满，等待队列有空闲位置后重试。
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, p.clock, p.submitTimeout)
+		defer cancel()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 先增加等待计数再重试，保证工作 goroutine 取走任务后能看到等待的提交者。
+	atomic.AddInt32(&p.blockedS, 1)
+	defer atomic.AddInt32(&p.blockedS, -1)
+
+	for {
+		if err := p.checkSubmit(); err != nil {
+			return err
+		}
+		if p.offer(dt) {
+			p.notEmpty.Signal()
+			return nil
+		}
+		if err := p.notFull.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *WorkStealingTaskPool) checkSubmit() error {
+	switch atomic.LoadInt32(&p.state) {
+	case stateClosing:
+		return ErrPoolIsClosing
+	case stateClosed:
+		return ErrPoolIsClosed
+	default:
+		return nil
+	}
+}
+
+// offer 从下一个队列开始轮流尝试入队，所有队列已满或已关闭时返回 false。
+func (p *WorkStealingTaskPool) offer(dt dequeTask) bool {
+	n := uint32(len(p.deques))
+	start := atomic.AddUint32(&p.next, 1)
+	for i := range n {
+		if p.deques[(start+i)%n].push(dt) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify 存在等待者时唤醒一个等待者，没有等待者时不需要获取任务池的锁。
+func (p *WorkStealingTaskPool) notify(waiting *int32, cond *xsync.Cond) {
+	if atomic.LoadInt32(waiting) > 0 {
+		p.mu.Lock()
+		cond.Signal()
+		p.mu.Unlock()
+	}
+}
+
+// worker 工作 goroutine，idx 为本地队列的下标。
+func (p *WorkStealingTaskPool) worker(id int32, idx int) {
+	local := p.deques[idx]
+	for {
+		if p.interruptCtx.Err() != nil {
+			p.exit(id, GoroutineExitInterrupt)
+			return
+		}
+
+		dt, ok := local.pop()
+		if !ok {
+			dt, ok = p.steal(idx)
+		}
+
+		if ok {
+			p.notify(&p.blockedS, p.notFull)
+			p.runTask(dt)
+			continue
+		}
+
+		if reason, exit := p.park(); exit {
+			p.exit(id, reason)
+			return
+		}
+	}
+}
+
+// steal 从其他队列的队尾窃取一个任务。
+func (p *WorkStealingTaskPool) steal(idx int) (dequeTask, bool) {
+	n := len(p.deques)
+	for i := 1; i < n; i++ {
+		if dt, ok := p.deques[(idx+i)%n].steal(); ok {
+			return dt, true
+		}
+	}
+	return dequeTask{}, false
+}
+
+// park 等待新任务，返回 goroutine 是否需要退出以及退出的原因。
+//
+// 注意：
+//
+//	先增加等待计数，再在持有锁的情况下检查队列，
+//	保证检查之后提交的任务一定能唤醒等待的 goroutine。
+func (p *WorkStealingTaskPool) park() (GoroutineExitReason, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	atomic.AddInt32(&p.idleG, 1)
+	defer atomic.AddInt32(&p.idleG, -1)
+
+	for {
+		if p.interruptCtx.Err() != nil {
+			return GoroutineExitInterrupt, true
+		}
+
+		allClosed := true
+		for _, d := range p.deques {
+			empty, closed := d.isEmpty()
+			if !empty {
+				return 0, false
+			}
+			allClosed = allClosed && closed
+		}
+		if allClosed {
+			// 任务池关闭且没有剩余任务
+			return GoroutineExitShutdown, true
+		}
+
+		_ = p.notEmpty.Wait(p.interruptCtx)
+	}
+}
+
+// exit 工作 goroutine 退出，最后一个退出的 goroutine 负责状态迁移，并通知外部调用者。
+func (p *WorkStealingTaskPool) exit(id int32, reason GoroutineExitReason) {
+	p.observer.GoroutineExited(id, reason)
+	if atomic.AddInt32(&p.aliveG, -1) == 0 {
+		if atomic.CompareAndSwapInt32(&p.state, stateClosing, stateClosed) {
+			p.interruptCancelFunc()
+		}
+	}
+}
+
+func (p *WorkStealingTaskPool) runTask(dt dequeTask) {
+	p.observer.TaskStarted(p.clock.Since(dt.enqueueAt))
+	atomic.AddInt32(&p.runningG, 1)
+	startAt := p.clock.Now()
+	err := dt.task.Run(p.interruptCtx)
+	runTime := p.clock.Since(startAt)
+	atomic.AddInt32(&p.runningG, -1)
+	p.observer.TaskFinished(runTime, err)
+
+	goHandleErr(p.interruptCtx, p.errHandler, p.errHandleTimeout, err)
+}
+
+// Start 开始调度执行。
+func (p *WorkStealingTaskPool) Start() error {
+	for {
+		switch atomic.LoadInt32(&p.state) {
+		case stateClosing:
+			return ErrPoolIsClosing
+		case stateClosed:
+			return ErrPoolIsClosed
+		case stateRunning:
+			return ErrPoolIsRunning
+		case stateLocked:
+			return ErrPoolIsLocked
+		}
+
+		if atomic.CompareAndSwapInt32(&p.state, stateCreated, stateLocked) {
+			atomic.StoreInt32(&p.aliveG, p.workerG)
+			for i := range p.workerG {
+				id := i + 1
+				p.observer.GoroutineCreated(id)
+				go p.worker(id, int(i))
+			}
+			atomic.CompareAndSwapInt32(&p.state, stateLocked, stateRunning)
+			return nil
+		}
+	}
+}
+
+// Shutdown 关闭任务池。
+// 调用后将拒绝 Submit 调用，但会继续执行队列中剩下的任务。
+// 所有任务执行完成后会发送信号到 chan 并负责关闭 chan。
+func (p *WorkStealingTaskPool) Shutdown() (<-chan struct{}, error) {
+	for {
+		switch atomic.LoadInt32(&p.state) {
+		case stateCreated:
+			return nil, ErrPoolIsNotRunning
+		case stateClosing:
+			return nil, ErrPoolIsClosing
+		case stateClosed:
+			return nil, ErrPoolIsClosed
+		}
+
+		if atomic.CompareAndSwapInt32(&p.state, stateRunning, stateClosing) {
+			p.closeDeques()
+			return p.interruptCtx.Done(), nil
+		}
+	}
+}
+
+// ShutdownNow 立即关闭任务池，并返回剩余的任务（不包含执行中的任务）。
+// 返回的任务视为被丢弃，通过 SubmitFunc 提交的任务的 Future 以 ErrPoolIsClosed 完成。
+func (p *WorkStealingTaskPool) ShutdownNow() ([]Task, error) {
+	for {
+		switch atomic.LoadInt32(&p.state) {
+		case stateCreated:
+			return nil, ErrPoolIsNotRunning
+		case stateClosing:
+			return nil, ErrPoolIsClosing
+		case stateClosed:
+			return nil, ErrPoolIsClosed
+		}
+
+		if atomic.CompareAndSwapInt32(&p.state, stateRunning, stateClosed) {
+			p.interruptCancelFunc()
+			p.closeDeques()
+
+			tasks := make([]Task, 0)
+			for _, d := range p.deques {
+				for _, dt := range d.drain() {
+					dropTask(dt.task, ErrPoolIsClosed)
+					tasks = append(tasks, dt.task)
+				}
+			}
+			return tasks, nil
+		}
+	}
+}
+
+// closeDeques 关闭所有本地队列，并唤醒等待的工作 goroutine 和提交者。
+func (p *WorkStealingTaskPool) closeDeques() {
+	for _, d := range p.deques {
+		d.close()
+	}
+
+	p.mu.Lock()
+	p.notEmpty.Broadcast()
+	p.notFull.Broadcast()
+	p.mu.Unlock()
+}
+
+// State 查询任务池内部状态。
+func (p *WorkStealingTaskPool) State(ctx context.Context, interval time.Duration) (<-chan State, error) {
+	return watchState(ctx, p.interruptCtx, p.clock, interval, p.getState)
+}
+
+func (p *WorkStealingTaskPool) getState(timestamp int64) State {
+	waiting := 0
+	for _, d := range p.deques {
+		waiting += d.len()
+	}
+
+	return State{
+		QueueSize:    p.workerG * int32(len(p.deques[0].buf)),
+		GoroutineCnt: atomic.LoadInt32(&p.aliveG),
+		WaitingCnt:   int32(waiting),
+		RunningCnt:   atomic.LoadInt32(&p.runningG),
+		PoolState:    atomic.LoadInt32(&p.state),
+		Timestamp:    timestamp,
+	}
+}
+
+func WithStealingSubmitTimeout(submitTimeout time.Duration) option.Opt[WorkStealingTaskPool] {
+	return func(p *WorkStealingTaskPool) {
+		p.submitTimeout = submitTimeout
+	}
+}
+
+func WithStealingErrorHandler(errHandler func(ctx context.Context, err error)) option.Opt[WorkStealingTaskPool] {
+	return func(p *WorkStealingTaskPool) {
+		p.errHandler = errHandler
+	}
+}
+
+func WithStealingErrHandleTimeout(errHandleTimeout time.Duration) option.Opt[WorkStealingTaskPool] {
+	return func(p *WorkStealingTaskPool) {
+		p.errHandleTimeout = errHandleTimeout
+	}
+}
+
+// WithStealingObserver 设置任务池的事件观察者，与 WithObserver 相同。
+func WithStealingObserver(observers ...Observer) option.Opt[WorkStealingTaskPool] {
+	return func(p *WorkStealingTaskPool) {
+		if len(observers) == 1 && observers[0] != nil {
+			p.observer = observers[0]
+			return
+		}
+		p.observer = MultiObserver(observers...)
+	}
+}
+
+// WithStealingClock 设置任务池使用的时钟，与 WithClock 相同，默认为 clock.Real()。
+func WithStealingClock(c clock.Clock) option.Opt[WorkStealingTaskPool] {
+	return func(p *WorkStealingTaskPool) {
+		p.clock = c
+	}
+}
+
+// NewWorkStealingTaskPool 创建工作窃取任务池。
+// workerG 为工作 goroutine 的数量，queueSize 为每个工作 goroutine 本地队列的容量。
+func NewWorkStealingTaskPool(workerG int32, queueSize int32, opts ...option.Opt[WorkStealingTaskPool]) (*WorkStealingTaskPool, error) {
+	if workerG <= 0 {
+		return nil, fmt.Errorf("%w: worker goroutine should be greater than 0", errInvalidParam)
+	}
+	if queueSize <= 0 {
+		return nil, fmt.Errorf("%w: queue size should be greater than 0", errInvalidParam)
+	}
+
+	p := &WorkStealingTaskPool{
+		workerG:          workerG,
+		deques:           make([]*workDeque, workerG),
+		clock:            clock.Real(),
+		submitTimeout:    defaultSubmitTimeout,
+		errHandleTimeout: defaultErrHandleTimeout,
+		observer:         NewLogObserver(nil),
+	}
+	for i := range p.deques {
+		p.deques[i] = newWorkDeque(int(queueSize))
+	}
+	p.notEmpty = xsync.NewCond(&p.mu)
+	p.notFull = xsync.NewCond(&p.mu)
+
+	p.interruptCtx, p.interruptCancelFunc = context.WithCancel(context.Background())
+	atomic.StoreInt32(&p.state, stateCreated)
+
+	option.Apply(p, opts...)
+
+	if p.clock == nil {
+		return nil, fmt.Errorf("%w: clock should not be nil", errInvalidParam)
+	}
+	return p, nil
+}
@@ -0,0 +1,354 @@
+package pool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkDeque(t *testing.T) {
+	t.Parallel()
+
+	d := newWorkDeque(3)
+	for i := 1; i <= 3; i++ {
+		assert.True(t, d.push(dequeTask{task: &idTask{id: i}}))
+	}
+	assert.False(t, d.push(dequeTask{task: &idTask{id: 4}}))
+
+	// 所属 goroutine 从队首取任务，窃取者从队尾窃取
+	dt, ok := d.pop()
+	require.True(t, ok)
+	assert.Equal(t, 1, dt.task.(*idTask).id)
+	dt, ok = d.steal()
+	require.True(t, ok)
+	assert.Equal(t, 3, dt.task.(*idTask).id)
+
+	// 环形缓冲区绕回
+	assert.True(t, d.push(dequeTask{task: &idTask{id: 4}}))
+	assert.True(t, d.push(dequeTask{task: &idTask{id: 5}}))
+	assert.Equal(t, 3, d.len())
+
+	d.close()
+	assert.False(t, d.push(dequeTask{task: &idTask{id: 6}}))
+
+	tasks := make([]Task, 0, 3)
+	for _, dt := range d.drain() {
+		tasks = append(tasks, dt.task)
+	}
+	assert.Equal(t, []int{2, 4, 5}, taskIds(tasks))
+
+	empty, closed := d.isEmpty()
+	assert.True(t, empty)
+	assert.True(t, closed)
+	_, ok = d.pop()
+	assert.False(t, ok)
+	_, ok = d.steal()
+	assert.False(t, ok)
+}
+
+func TestNewWorkStealingTaskPool(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name      string
+		workerG   int32
+		queueSize int32
+		wantErr   error
+	}{
+		{name: "basic", workerG: 2, queueSize: 4},
+		{name: "worker goroutine is 0", workerG: 0, queueSize: 4, wantErr: errInvalidParam},
+		{name: "queue size is 0", workerG: 2, queueSize: 0, wantErr: errInvalidParam},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := NewWorkStealingTaskPool(tc.workerG, tc.queueSize)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.Equal(t, stateCreated, atomic.LoadInt32(&p.state))
+				assert.Equal(t, int(tc.workerG), len(p.deques))
+			}
+		})
+	}
+}
+
+func TestWorkStealingTaskPool_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWorkStealingTaskPool(4, 8)
+	require.NoError(t, err)
+
+	_, err = p.Shutdown()
+	assert.ErrorIs(t, err, ErrPoolIsNotRunning)
+	_, err = p.ShutdownNow()
+	assert.ErrorIs(t, err, ErrPoolIsNotRunning)
+
+	var cnt atomic.Int32
+	task := TaskFunc(func(ctx context.Context) error {
+		cnt.Add(1)
+		return nil
+	})
+
+	// 任务池启动前可以提交任务
+	for range 8 {
+		require.NoError(t, p.Submit(context.Background(), task))
+	}
+	assert.ErrorIs(t, p.Submit(context.Background(), nil), errInvalidTask)
+
+	require.NoError(t, p.Start())
+	assert.ErrorIs(t, p.Start(), ErrPoolIsRunning)
+
+	for range 100 {
+		require.NoError(t, p.Submit(context.Background(), task))
+	}
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+
+	assert.Equal(t, int32(108), cnt.Load())
+	assert.Equal(t, stateClosed, atomic.LoadInt32(&p.state))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&p.aliveG))
+
+	assert.ErrorIs(t, p.Submit(context.Background(), task), ErrPoolIsClosed)
+	assert.ErrorIs(t, p.Start(), ErrPoolIsClosed)
+	_, err = p.Shutdown()
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+}
+
+func TestWorkStealingTaskPool_Steal(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWorkStealingTaskPool(2, 8)
+	require.NoError(t, err)
+
+	wait := make(chan struct{})
+	started := make(chan struct{})
+	ids := make(chan int, 4)
+
+	// 第一个工作 goroutine 被阻塞，其本地队列中的任务只能被窃取执行
+	require.True(t, p.deques[0].push(dequeTask{task: TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})}))
+	for i := 1; i <= 3; i++ {
+		require.True(t, p.deques[0].push(dequeTask{task: TaskFunc(func(ctx context.Context) error {
+			ids <- i
+			return nil
+		})}))
+	}
+
+	require.NoError(t, p.Start())
+	<-started
+
+	// 从队尾窃取
+	assert.Equal(t, 3, <-ids)
+	assert.Equal(t, 2, <-ids)
+	assert.Equal(t, 1, <-ids)
+
+	close(wait)
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestWorkStealingTaskPool_SubmitBlocked(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWorkStealingTaskPool(1, 1)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	wait := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})))
+	<-started
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})))
+
+	// 队列已满
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	err = p.Submit(ctx, TaskFunc(func(ctx context.Context) error {
+		return nil
+	}))
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 队列有空闲位置后，阻塞的提交成功
+	submitted := make(chan error)
+	go func() {
+		submitted <- p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			return nil
+		}))
+	}()
+	close(wait)
+	require.NoError(t, <-submitted)
+
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestWorkStealingTaskPool_Clock(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewWorkStealingTaskPool(1, 1, WithStealingClock(nil))
+	assert.ErrorIs(t, err, errInvalidParam)
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	p, err := NewWorkStealingTaskPool(1, 1, WithStealingClock(clk), WithStealingSubmitTimeout(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	wait := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		close(started)
+		<-wait
+		return nil
+	})))
+	<-started
+	require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+		return nil
+	})))
+
+	// 队列已满，提交超时按时钟计算
+	submitted := make(chan error)
+	go func() {
+		submitted <- p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			return nil
+		}))
+	}()
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	clk.Advance(time.Minute)
+	assert.ErrorIs(t, <-submitted, context.DeadlineExceeded)
+
+	close(wait)
+	done, err := p.Shutdown()
+	require.NoError(t, err)
+	<-done
+}
+
+func TestWorkStealingTaskPool_ShutdownNow(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWorkStealingTaskPool(2, 4)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	wait := make(chan struct{})
+	defer close(wait)
+
+	var startedWg sync.WaitGroup
+	startedWg.Add(2)
+	for range 2 {
+		require.NoError(t, p.Submit(context.Background(), TaskFunc(func(ctx context.Context) error {
+			startedWg.Done()
+			<-wait
+			return nil
+		})))
+	}
+	startedWg.Wait()
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, p.Submit(context.Background(), &idTask{id: i}))
+	}
+
+	state := p.getState(time.Now().UnixMilli())
+	assert.Equal(t, int32(8), state.QueueSize)
+	assert.Equal(t, int32(2), state.GoroutineCnt)
+	assert.Equal(t, int32(5), state.WaitingCnt)
+	assert.Equal(t, int32(2), state.RunningCnt)
+
+	tasks, err := p.ShutdownNow()
+	require.NoError(t, err)
+	assert.Len(t, tasks, 5)
+
+	_, err = p.ShutdownNow()
+	assert.ErrorIs(t, err, ErrPoolIsClosed)
+	assert.ErrorIs(t, p.Submit(context.Background(), &idTask{id: 6}), ErrPoolIsClosed)
+}
+
+func TestWorkStealingTaskPool_State(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWorkStealingTaskPool(2, 4)
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := p.State(ctx, time.Millisecond)
+	require.NoError(t, err)
+
+	state := <-ch
+	assert.Equal(t, int32(8), state.QueueSize)
+	assert.Equal(t, stateRunning, state.PoolState)
+	cancel()
+
+	for range ch {
+	}
+
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+	_, err = p.State(context.Background(), time.Millisecond)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func benchmarkTaskPool(b *testing.B, p TaskPool) {
+	var wg sync.WaitGroup
+	task := TaskFunc(func(ctx context.Context) error {
+		wg.Done()
+		return nil
+	})
+
+	require.NoError(b, p.Start())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			wg.Add(1)
+			if err := p.Submit(context.Background(), task); err != nil {
+				b.Error(err)
+				wg.Done()
+			}
+		}
+	})
+	wg.Wait()
+	b.StopTimer()
+
+	done, err := p.Shutdown()
+	require.NoError(b, err)
+	<-done
+}
+
+func BenchmarkTaskPool_Submit(b *testing.B) {
+	workerG := int32(runtime.GOMAXPROCS(0))
+
+	b.Run("BlockTaskPool", func(b *testing.B) {
+		p, err := NewBlockTaskPool(workerG, 1024, WithObserver(NopObserver{}))
+		require.NoError(b, err)
+		benchmarkTaskPool(b, p)
+	})
+
+	b.Run("WorkStealingTaskPool", func(b *testing.B) {
+		p, err := NewWorkStealingTaskPool(workerG, 1024/workerG+1, WithStealingObserver(NopObserver{}))
+		require.NoError(b, err)
+		benchmarkTaskPool(b, p)
+	})
+}
@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/JrMarcco/jit"
+	"github.com/JrMarcco/jit/xsync"
+)
+
+var _ BlockingQueue[any] = (*ConcurrentPriorityQueue[any])(nil)
+
+// ConcurrentPriorityQueue is a thread-safe blocking priority queue.
+type ConcurrentPriorityQueue[T any] struct {
+	mu sync.Mutex
+	pq *PriorityQueue[T]
+
+	notEmpty *xsync.Cond
+	notFull  *xsync.Cond
+}
+
+func (q *ConcurrentPriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Len()
+}
+
+// Peek returns the least element without removing it, it does not block.
+func (q *ConcurrentPriorityQueue[T]) Peek() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Peek()
+}
+
+// Enqueue adds the element, blocks until the queue is not full or ctx is done.
+func (q *ConcurrentPriorityQueue[T]) Enqueue(ctx context.Context, elem T) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.pq.isFull() {
+		if err := q.notFull.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	// the queue is not full, error can be ignored.
+	_ = q.pq.Enqueue(elem)
+	q.notEmpty.Signal()
+	return nil
+}
+
+// Dequeue removes and returns the least element, blocks until the queue is not empty or ctx is done.
+func (q *ConcurrentPriorityQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	if ctx.Err() != nil {
+		var zero T
+		return zero, ctx.Err()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.pq.Len() == 0 {
+		if err := q.notEmpty.Wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	// the queue is not empty, error can be ignored.
+	elem, _ := q.pq.Dequeue()
+	q.notFull.Signal()
+	return elem, nil
+}
+
+// NewConcurrentPriorityQueue creates a blocking priority queue, capacity <= 0 means unbounded.
+func NewConcurrentPriorityQueue[T any](capacity int, cmp jit.Comparator[T]) (*ConcurrentPriorityQueue[T], error) {
+	pq, err := NewPriorityQueue[T](capacity, cmp)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &ConcurrentPriorityQueue[T]{pq: pq}
+	q.notEmpty = xsync.NewCond(&q.mu)
+	q.notFull = xsync.NewCond(&q.mu)
+	return q, nil
+}
@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentPriorityQueue_Enqueue(t *testing.T) {
+	q, err := NewConcurrentPriorityQueue[int](2, cmp.Compare[int])
+	require.NoError(t, err)
+
+	require.NoError(t, q.Enqueue(context.Background(), 2))
+	require.NoError(t, q.Enqueue(context.Background(), 1))
+
+	// blocked until timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.Enqueue(ctx, 3))
+
+	// canceled context
+	canceledCtx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	assert.Equal(t, context.Canceled, q.Enqueue(canceledCtx, 3))
+
+	// blocked until dequeue
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		elem, err := q.Dequeue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, elem)
+	}()
+	require.NoError(t, q.Enqueue(context.Background(), 0))
+
+	head, err := q.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, 0, head)
+	assert.Equal(t, 2, q.Len())
+}
+
+func TestConcurrentPriorityQueue_Dequeue(t *testing.T) {
+	q, err := NewConcurrentPriorityQueue[int](0, cmp.Compare[int])
+	require.NoError(t, err)
+
+	// blocked until timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = q.Dequeue(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// blocked until enqueue
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, q.Enqueue(context.Background(), 1))
+	}()
+	elem, err := q.Dequeue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, elem)
+}
+
+func TestConcurrentPriorityQueue_Parallel(t *testing.T) {
+	const (
+		producerCnt = 8
+		consumerCnt = 8
+		elemCnt     = 1000
+	)
+
+	q, err := NewConcurrentPriorityQueue[int](16, cmp.Compare[int])
+	require.NoError(t, err)
+
+	var producerWg sync.WaitGroup
+	for p := range producerCnt {
+		producerWg.Add(1)
+		go func() {
+			defer producerWg.Done()
+			for i := range elemCnt {
+				assert.NoError(t, q.Enqueue(context.Background(), p*elemCnt+i))
+			}
+		}()
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]struct{}, producerCnt*elemCnt)
+
+	var consumerWg sync.WaitGroup
+	for range consumerCnt {
+		consumerWg.Add(1)
+		go func() {
+			defer consumerWg.Done()
+			for range producerCnt * elemCnt / consumerCnt {
+				elem, err := q.Dequeue(context.Background())
+				assert.NoError(t, err)
+
+				mu.Lock()
+				seen[elem] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producerWg.Wait()
+	consumerWg.Wait()
+
+	assert.Equal(t, producerCnt*elemCnt, len(seen))
+	assert.Equal(t, 0, q.Len())
+}
@@ -0,0 +1,115 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/xsync"
+)
+
+var _ BlockingQueue[Delayable] = (*DelayQueue[Delayable])(nil)
+
+// DelayQueue is a thread-safe blocking queue of Delayable elements.
+// An element can only be dequeued after its deadline, the element with the earliest deadline is dequeued first.
+type DelayQueue[T Delayable] struct {
+	mu sync.Mutex
+	pq *PriorityQueue[T]
+
+	// notEmpty is broadcast when the head of the queue changes,
+	// so that the waiting consumers recalculate how long to wait.
+	notEmpty *xsync.Cond
+	notFull  *xsync.Cond
+
+	now func() time.Time
+}
+
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Len()
+}
+
+// Enqueue adds the element, blocks until the queue is not full or ctx is done.
+func (q *DelayQueue[T]) Enqueue(ctx context.Context, elem T) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.pq.isFull() {
+		if err := q.notFull.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	// the queue is not full, error can be ignored.
+	_ = q.pq.Enqueue(elem)
+
+	if head, _ := q.pq.Peek(); head.Deadline().Equal(elem.Deadline()) {
+		// the new element may be the new head with an earlier deadline.
+		q.notEmpty.Broadcast()
+	}
+	return nil
+}
+
+// Dequeue removes and returns the element with the earliest deadline,
+// blocks until the deadline of the element is reached or ctx is done.
+func (q *DelayQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	if ctx.Err() != nil {
+		var zero T
+		return zero, ctx.Err()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		head, err := q.pq.Peek()
+		if err != nil {
+			// empty queue, wait for new element.
+			if err = q.notEmpty.Wait(ctx); err != nil {
+				var zero T
+				return zero, err
+			}
+			continue
+		}
+
+		delay := head.Deadline().Sub(q.now())
+		if delay <= 0 {
+			elem, _ := q.pq.Dequeue()
+			q.notFull.Signal()
+			// the other consumers may be waiting for the next element.
+			q.notEmpty.Signal()
+			return elem, nil
+		}
+
+		// wait until the deadline of the head, or a new head is enqueued.
+		waitCtx, cancel := context.WithTimeout(ctx, delay)
+		err = q.notEmpty.Wait(waitCtx)
+		cancel()
+
+		if err != nil && ctx.Err() != nil {
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// NewDelayQueue creates a delay queue, capacity <= 0 means unbounded.
+func NewDelayQueue[T Delayable](capacity int) *DelayQueue[T] {
+	// comparator is never nil, error can be ignored.
+	pq, _ := NewPriorityQueue[T](capacity, func(src, dst T) int {
+		return src.Deadline().Compare(dst.Deadline())
+	})
+
+	q := &DelayQueue[T]{
+		pq:  pq,
+		now: time.Now,
+	}
+	q.notEmpty = xsync.NewCond(&q.mu)
+	q.notFull = xsync.NewCond(&q.mu)
+	return q
+}
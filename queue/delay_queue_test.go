@@ -0,0 +1,153 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delayElem struct {
+	id       int
+	deadline time.Time
+}
+
+func (e delayElem) Deadline() time.Time {
+	return e.deadline
+}
+
+func TestDelayQueue_Dequeue(t *testing.T) {
+	now := time.Now()
+
+	tcs := []struct {
+		name    string
+		elems   []delayElem
+		timeout time.Duration
+		wantId  int
+		wantErr error
+	}{
+		{
+			name:    "empty queue",
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		}, {
+			name:    "deadline not reached",
+			elems:   []delayElem{{id: 1, deadline: now.Add(time.Minute)}},
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		}, {
+			name:    "deadline reached",
+			elems:   []delayElem{{id: 1, deadline: now.Add(-time.Second)}},
+			timeout: 10 * time.Millisecond,
+			wantId:  1,
+		}, {
+			name: "earliest deadline first",
+			elems: []delayElem{
+				{id: 1, deadline: now.Add(50 * time.Millisecond)},
+				{id: 2, deadline: now.Add(20 * time.Millisecond)},
+				{id: 3, deadline: now.Add(time.Minute)},
+			},
+			timeout: time.Second,
+			wantId:  2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			q := NewDelayQueue[delayElem](0)
+			for _, elem := range tc.elems {
+				require.NoError(t, q.Enqueue(context.Background(), elem))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+
+			elem, err := q.Dequeue(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantId, elem.id)
+			assert.False(t, time.Now().Before(elem.deadline))
+		})
+	}
+}
+
+func TestDelayQueue_EarlierHead(t *testing.T) {
+	q := NewDelayQueue[delayElem](0)
+	require.NoError(t, q.Enqueue(context.Background(), delayElem{id: 1, deadline: time.Now().Add(time.Minute)}))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		// the waiting consumer is woken up by the new head with an earlier deadline.
+		assert.NoError(t, q.Enqueue(context.Background(), delayElem{id: 2, deadline: time.Now().Add(10 * time.Millisecond)}))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	elem, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, elem.id)
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestDelayQueue_Enqueue(t *testing.T) {
+	q := NewDelayQueue[delayElem](1)
+	require.NoError(t, q.Enqueue(context.Background(), delayElem{id: 1, deadline: time.Now().Add(10 * time.Millisecond)}))
+
+	// blocked until timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.Enqueue(ctx, delayElem{id: 2}))
+
+	// blocked until dequeue
+	go func() {
+		elem, err := q.Dequeue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, elem.id)
+	}()
+	require.NoError(t, q.Enqueue(context.Background(), delayElem{id: 2}))
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestDelayQueue_Parallel(t *testing.T) {
+	const (
+		consumerCnt = 4
+		elemCnt     = 200
+	)
+
+	q := NewDelayQueue[delayElem](0)
+
+	now := time.Now()
+	for i := range elemCnt {
+		deadline := now.Add(time.Duration(i%20) * time.Millisecond)
+		require.NoError(t, q.Enqueue(context.Background(), delayElem{id: i, deadline: deadline}))
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]struct{}, elemCnt)
+
+	var wg sync.WaitGroup
+	for range consumerCnt {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range elemCnt / consumerCnt {
+				elem, err := q.Dequeue(context.Background())
+				assert.NoError(t, err)
+				assert.False(t, time.Now().Before(elem.deadline))
+
+				mu.Lock()
+				seen[elem.id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, elemCnt, len(seen))
+}
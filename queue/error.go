@@ -0,0 +1,14 @@
+package queue
+
+import (
+	"errors"
+
+	"github.com/JrMarcco/jit/xmap"
+)
+
+var (
+	ErrNilComparator = xmap.ErrNilComparator
+
+	ErrEmptyQueue    = errors.New("[jit] queue is empty")
+	ErrOutOfCapacity = errors.New("[jit] queue is out of capacity")
+)
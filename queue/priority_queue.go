@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"github.com/JrMarcco/jit"
+	"github.com/JrMarcco/jit/internal/slice"
+)
+
+// PriorityQueue is a binary heap based priority queue, it is not thread-safe.
+// The least element according to the comparator is dequeued first.
+type PriorityQueue[T any] struct {
+	cmp      jit.Comparator[T]
+	capacity int // capacity <= 0 means unbounded
+	data     []T
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.data)
+}
+
+// Cap returns the capacity of the queue, 0 means unbounded.
+func (pq *PriorityQueue[T]) Cap() int {
+	return pq.capacity
+}
+
+func (pq *PriorityQueue[T]) isBounded() bool {
+	return pq.capacity > 0
+}
+
+func (pq *PriorityQueue[T]) isFull() bool {
+	return pq.isBounded() && len(pq.data) >= pq.capacity
+}
+
+// Peek returns the least element without removing it.
+func (pq *PriorityQueue[T]) Peek() (T, error) {
+	if len(pq.data) == 0 {
+		var zero T
+		return zero, ErrEmptyQueue
+	}
+	return pq.data[0], nil
+}
+
+func (pq *PriorityQueue[T]) Enqueue(elem T) error {
+	if pq.isFull() {
+		return ErrOutOfCapacity
+	}
+
+	pq.data = append(pq.data, elem)
+	pq.up(len(pq.data) - 1)
+	return nil
+}
+
+// Dequeue removes and returns the least element.
+func (pq *PriorityQueue[T]) Dequeue() (T, error) {
+	if len(pq.data) == 0 {
+		var zero T
+		return zero, ErrEmptyQueue
+	}
+
+	last := len(pq.data) - 1
+	res := pq.data[0]
+
+	pq.data[0] = pq.data[last]
+	// clear the reference for GC
+	var zero T
+	pq.data[last] = zero
+	pq.data = pq.data[:last]
+
+	pq.down(0)
+
+	if !pq.isBounded() {
+		pq.data = slice.Shrink(pq.data)
+	}
+	return res, nil
+}
+
+// up moves the element at index i up to its position.
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if pq.cmp(pq.data[i], pq.data[parent]) >= 0 {
+			return
+		}
+
+		pq.data[i], pq.data[parent] = pq.data[parent], pq.data[i]
+		i = parent
+	}
+}
+
+// down moves the element at index i down to its position.
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.data)
+	for {
+		least := i
+
+		left, right := 2*i+1, 2*i+2
+		if left < n && pq.cmp(pq.data[left], pq.data[least]) < 0 {
+			least = left
+		}
+		if right < n && pq.cmp(pq.data[right], pq.data[least]) < 0 {
+			least = right
+		}
+
+		if least == i {
+			return
+		}
+
+		pq.data[i], pq.data[least] = pq.data[least], pq.data[i]
+		i = least
+	}
+}
+
+// NewPriorityQueue creates a priority queue, capacity <= 0 means unbounded.
+func NewPriorityQueue[T any](capacity int, cmp jit.Comparator[T]) (*PriorityQueue[T], error) {
+	if cmp == nil {
+		return nil, ErrNilComparator
+	}
+
+	pq := &PriorityQueue[T]{cmp: cmp}
+	if capacity > 0 {
+		pq.capacity = capacity
+		pq.data = make([]T, 0, capacity)
+	}
+	return pq, nil
+}
@@ -0,0 +1,120 @@
+package queue
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriorityQueue(t *testing.T) {
+	tcs := []struct {
+		name     string
+		capacity int
+		cmp      func(src, dst int) int
+		wantCap  int
+		wantErr  error
+	}{
+		{name: "bounded", capacity: 8, cmp: cmp.Compare[int], wantCap: 8},
+		{name: "unbounded", capacity: 0, cmp: cmp.Compare[int], wantCap: 0},
+		{name: "negative capacity", capacity: -1, cmp: cmp.Compare[int], wantCap: 0},
+		{name: "nil comparator", capacity: 8, wantErr: ErrNilComparator},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			pq, err := NewPriorityQueue[int](tc.capacity, tc.cmp)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantCap, pq.Cap())
+			assert.Equal(t, 0, pq.Len())
+		})
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	tcs := []struct {
+		name       string
+		capacity   int
+		elems      []int
+		wantErr    error
+		wantOutput []int
+	}{
+		{
+			name:       "unbounded",
+			elems:      []int{5, 1, 4, 2, 3, 1},
+			wantOutput: []int{1, 1, 2, 3, 4, 5},
+		}, {
+			name:       "bounded",
+			capacity:   3,
+			elems:      []int{3, 1, 2, 4},
+			wantErr:    ErrOutOfCapacity,
+			wantOutput: []int{1, 2, 3},
+		}, {
+			name:       "empty",
+			wantOutput: []int{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			pq, err := NewPriorityQueue[int](tc.capacity, cmp.Compare[int])
+			require.NoError(t, err)
+
+			for _, elem := range tc.elems {
+				if err = pq.Enqueue(elem); err != nil {
+					break
+				}
+			}
+			assert.Equal(t, tc.wantErr, err)
+
+			if len(tc.wantOutput) > 0 {
+				head, err := pq.Peek()
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantOutput[0], head)
+			}
+
+			output := make([]int, 0, pq.Len())
+			for pq.Len() > 0 {
+				elem, err := pq.Dequeue()
+				require.NoError(t, err)
+				output = append(output, elem)
+			}
+			assert.Equal(t, tc.wantOutput, output)
+
+			_, err = pq.Peek()
+			assert.Equal(t, ErrEmptyQueue, err)
+			_, err = pq.Dequeue()
+			assert.Equal(t, ErrEmptyQueue, err)
+		})
+	}
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	pq, err := NewPriorityQueue[int](0, cmp.Compare[int])
+	require.NoError(t, err)
+
+	want := make([]int, 0)
+	for range 1000 {
+		if r.IntN(3) == 0 && len(want) > 0 {
+			slices.Sort(want)
+			elem, err := pq.Dequeue()
+			require.NoError(t, err)
+			assert.Equal(t, want[0], elem)
+			want = want[1:]
+			continue
+		}
+
+		elem := r.IntN(100)
+		require.NoError(t, pq.Enqueue(elem))
+		want = append(want, elem)
+	}
+	assert.Equal(t, len(want), pq.Len())
+}
@@ -0,0 +1,21 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// BlockingQueue is a thread-safe queue.
+// Enqueue blocks when the queue is full and Dequeue blocks when the queue is empty,
+// until the operation can proceed or ctx is done.
+type BlockingQueue[T any] interface {
+	Enqueue(ctx context.Context, elem T) error
+	Dequeue(ctx context.Context) (T, error)
+	Len() int
+}
+
+// Delayable is the element of DelayQueue.
+type Delayable interface {
+	// Deadline returns the time when the element becomes visible to Dequeue.
+	Deadline() time.Time
+}
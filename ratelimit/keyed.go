@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+// KeyedLimiter keeps a Limiter for each key, e.g. a limit per user.
+//
+// Limiters are created on first use by newLimiter, and evicted after they are not used for idleTimeout
+// so that the map does not grow with the keys forever. An evicted key starts over with a new limiter,
+// so idleTimeout should be long enough for a limiter to recover, e.g. the period of a TokenBucket.
+// Eviction is done lazily by the callers, no background goroutine is needed.
+type KeyedLimiter[K comparable] struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	newLimiter  func(key K) Limiter
+	idleTimeout time.Duration
+
+	entries   map[K]*keyedEntry
+	lastSweep time.Time
+}
+
+type keyedEntry struct {
+	limiter  Limiter
+	lastUsed time.Time
+}
+
+// Get returns the limiter of key, creating it if necessary.
+func (kl *KeyedLimiter[K]) Get(key K) Limiter {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	now := kl.clock.Now()
+	if now.Sub(kl.lastSweep) >= kl.idleTimeout {
+		kl.sweep(now)
+	}
+
+	e, ok := kl.entries[key]
+	if !ok {
+		e = &keyedEntry{limiter: kl.newLimiter(key)}
+		kl.entries[key] = e
+	}
+	e.lastUsed = now
+	return e.limiter
+}
+
+// Allow takes a permit of key if it is available now.
+func (kl *KeyedLimiter[K]) Allow(key K) bool {
+	return kl.Get(key).Allow()
+}
+
+// Wait blocks until a permit of key is available or ctx is done.
+func (kl *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	return kl.Get(key).Wait(ctx)
+}
+
+// Reserve takes a permit of key which is available after Reservation.Delay.
+func (kl *KeyedLimiter[K]) Reserve(key K) *Reservation {
+	return kl.Get(key).Reserve()
+}
+
+// Len returns the number of keys having a limiter.
+func (kl *KeyedLimiter[K]) Len() int {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	return len(kl.entries)
+}
+
+// sweep evicts the limiters idle for idleTimeout.
+func (kl *KeyedLimiter[K]) sweep(now time.Time) {
+	for key, e := range kl.entries {
+		if now.Sub(e.lastUsed) >= kl.idleTimeout {
+			delete(kl.entries, key)
+		}
+	}
+	kl.lastSweep = now
+}
+
+// NewKeyedLimiter creates a KeyedLimiter creating limiters by newLimiter and evicting them after idleTimeout.
+func NewKeyedLimiter[K comparable](
+	newLimiter func(key K) Limiter, idleTimeout time.Duration, opts ...option.Opt[Options],
+) (*KeyedLimiter[K], error) {
+	if newLimiter == nil {
+		return nil, fmt.Errorf("%w: newLimiter should not be nil", errInvalidParam)
+	}
+	if idleTimeout <= 0 {
+		return nil, fmt.Errorf("%w: idle timeout should be greater than 0", errInvalidParam)
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyedLimiter[K]{
+		clock:       o.clock,
+		newLimiter:  newLimiter,
+		idleTimeout: idleTimeout,
+		entries:     make(map[K]*keyedEntry),
+		lastSweep:   o.clock.Now(),
+	}, nil
+}
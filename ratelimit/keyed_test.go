@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch)
+	kl, err := NewKeyedLimiter(func(key string) Limiter {
+		// the limit depends on the key
+		limit := 1
+		if key == "vip" {
+			limit = 3
+		}
+		fw, err := NewFixedWindow(limit, time.Second, WithClock(clk))
+		require.NoError(t, err)
+		return fw
+	}, time.Minute, WithClock(clk))
+	require.NoError(t, err)
+
+	assert.True(t, kl.Allow("alice"))
+	assert.False(t, kl.Allow("alice"))
+	assert.Equal(t, 3, allowed(kl.Get("vip")))
+	assert.Equal(t, 2, kl.Len())
+
+	assert.Equal(t, time.Second, kl.Reserve("alice").Delay())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, kl.Wait(ctx, "bob"), context.Canceled)
+	assert.Equal(t, 3, kl.Len())
+
+	// keys used recently are kept
+	clk.Advance(30 * time.Second)
+	assert.True(t, kl.Allow("alice"))
+	clk.Advance(30 * time.Second)
+	kl.Get("carol")
+	assert.Equal(t, 2, kl.Len())
+
+	_, err = NewKeyedLimiter[string](nil, time.Minute)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = NewKeyedLimiter(func(string) Limiter { return nil }, 0)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = NewKeyedLimiter(func(string) Limiter { return nil }, time.Minute, WithClock(nil))
+	assert.ErrorIs(t, err, errInvalidParam)
+}
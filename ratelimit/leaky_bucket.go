@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var _ Limiter = (*LeakyBucket)(nil)
+
+// LeakyBucket lets permits out at a constant interval of period / limit without bursts,
+// at most capacity callers wait in the bucket and the others are rejected.
+type LeakyBucket struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	interval time.Duration
+	capacity int
+
+	next time.Time // the earliest time of the next permit
+}
+
+func (lb *LeakyBucket) Allow() bool {
+	return allow(lb.clock, lb)
+}
+
+func (lb *LeakyBucket) Wait(ctx context.Context) error {
+	return wait(ctx, lb.clock, lb)
+}
+
+func (lb *LeakyBucket) Reserve() *Reservation {
+	return reserve(lb.clock, lb)
+}
+
+func (lb *LeakyBucket) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	at := now
+	if lb.next.After(now) {
+		at = lb.next
+	}
+
+	delay := at.Sub(now)
+	if delay > time.Duration(lb.capacity)*lb.interval {
+		// the bucket is full
+		return time.Time{}, false
+	}
+	if delay > maxWait {
+		return at, false
+	}
+
+	lb.next = at.Add(lb.interval)
+	return at, true
+}
+
+func (lb *LeakyBucket) cancel(at time.Time, _ time.Time) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	// only the latest permit can be given back, the later ones are not moved forward
+	if at.Add(lb.interval).Equal(lb.next) {
+		lb.next = at
+	}
+}
+
+// NewLeakyBucket creates a LeakyBucket letting limit permits out every period,
+// capacity is the maximum number of callers waiting in the bucket.
+func NewLeakyBucket(limit int, period time.Duration, capacity int, opts ...option.Opt[Options]) (*LeakyBucket, error) {
+	if limit <= 0 || period <= 0 {
+		return nil, fmt.Errorf("%w: limit and period should be greater than 0", errInvalidParam)
+	}
+	if capacity < 0 {
+		return nil, fmt.Errorf("%w: capacity should not be negative", errInvalidParam)
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &LeakyBucket{
+		clock:    o.clock,
+		interval: period / time.Duration(limit),
+		capacity: capacity,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var (
+	errInvalidParam = errors.New("[jit] invalid param")
+
+	// ErrLimitExceeded is returned by Wait when the limiter can never grant the permit,
+	// e.g. the queue of a LeakyBucket is full.
+	ErrLimitExceeded = errors.New("[jit] rate limit exceeded")
+	// ErrWouldExceedDeadline is returned by Wait when the permit is not available before the deadline of ctx.
+	ErrWouldExceedDeadline = fmt.Errorf("[jit] rate limit wait would exceed context deadline: %w", context.DeadlineExceeded)
+)
+
+// Limiter limits how often something happens.
+type Limiter interface {
+	// Allow takes a permit if it is available now, it never blocks.
+	Allow() bool
+	// Wait blocks until a permit is available or ctx is done.
+	// It returns ErrWouldExceedDeadline immediately if the permit is not available before the deadline of ctx.
+	Wait(ctx context.Context) error
+	// Reserve takes a permit which is available after Reservation.Delay, the caller should wait for it itself.
+	Reserve() *Reservation
+}
+
+// Reservation is a permit taken by Limiter.Reserve.
+type Reservation struct {
+	ok     bool
+	at     time.Time
+	clock  clock.Clock
+	cancel func()
+
+	canceled atomic.Bool
+}
+
+// OK reports whether the permit is granted, Delay and Cancel are meaningless if not.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns how long the caller should wait before acting.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return math.MaxInt64
+	}
+	return max(r.at.Sub(r.clock.Now()), 0)
+}
+
+// Wait blocks until the permit is available or ctx is done.
+// It returns ErrLimitExceeded if the permit is not granted,
+// and ErrWouldExceedDeadline immediately if the permit is not available before the deadline of ctx.
+// The permit is given back when ErrWouldExceedDeadline or ctx.Err() is returned.
+func (r *Reservation) Wait(ctx context.Context) error {
+	if !r.ok {
+		return ErrLimitExceeded
+	}
+	if err := ctx.Err(); err != nil {
+		r.Cancel()
+		return err
+	}
+
+	delay := r.Delay()
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(r.at) {
+		r.Cancel()
+		return ErrWouldExceedDeadline
+	}
+
+	timer := r.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// Cancel gives the permit back, so that the following callers wait less.
+// It should be called only if the action the permit is reserved for did not happen,
+// e.g. the caller gave up waiting or failed before acting. Calling it more than once does nothing.
+func (r *Reservation) Cancel() {
+	if r.ok && r.canceled.CompareAndSwap(false, true) {
+		r.cancel()
+	}
+}
+
+// Options configures the limiters.
+type Options struct {
+	clock clock.Clock
+}
+
+// WithClock sets the clock of the limiter, clock.Real() by default.
+func WithClock(c clock.Clock) option.Opt[Options] {
+	return func(o *Options) {
+		o.clock = c
+	}
+}
+
+func newOptions(opts []option.Opt[Options]) (*Options, error) {
+	o := &Options{clock: clock.Real()}
+	option.Apply(o, opts...)
+	if o.clock == nil {
+		return nil, fmt.Errorf("%w: clock should not be nil", errInvalidParam)
+	}
+	return o, nil
+}
+
+// reserver is the algorithm of a limiter, it is called with the clock of the limiter.
+type reserver interface {
+	// reserve takes a permit available at or after now, returns when it is available.
+	// If the permit is available later than now + maxWait nothing is taken and false is returned,
+	// a zero time means the permit is never available.
+	reserve(now time.Time, maxWait time.Duration) (time.Time, bool)
+	// cancel gives back the permit reserved at at.
+	cancel(at time.Time, now time.Time)
+}
+
+func allow(c clock.Clock, r reserver) bool {
+	_, ok := r.reserve(c.Now(), 0)
+	return ok
+}
+
+func reserve(c clock.Clock, r reserver) *Reservation {
+	now := c.Now()
+	at, ok := r.reserve(now, math.MaxInt64)
+	return &Reservation{
+		ok:    ok,
+		at:    at,
+		clock: c,
+		cancel: func() {
+			r.cancel(at, c.Now())
+		},
+	}
+}
+
+func wait(ctx context.Context, c clock.Clock, r reserver) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := c.Now()
+	maxWait := time.Duration(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = deadline.Sub(now)
+	}
+
+	at, ok := r.reserve(now, maxWait)
+	if !ok {
+		if at.IsZero() {
+			return ErrLimitExceeded
+		}
+		return ErrWouldExceedDeadline
+	}
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := c.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		r.cancel(at, c.Now())
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,255 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// allowed counts the permits Allow grants now.
+func allowed(l Limiter) int {
+	n := 0
+	for l.Allow() {
+		n++
+		if n > 1000 {
+			break
+		}
+	}
+	return n
+}
+
+func TestNewLimiter(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		new  func() error
+	}{
+		{name: "token bucket limit", new: func() error { _, err := NewTokenBucket(0, time.Second, 1); return err }},
+		{name: "token bucket burst", new: func() error { _, err := NewTokenBucket(1, time.Second, 0); return err }},
+		{name: "leaky bucket period", new: func() error { _, err := NewLeakyBucket(1, 0, 1); return err }},
+		{name: "leaky bucket capacity", new: func() error { _, err := NewLeakyBucket(1, time.Second, -1); return err }},
+		{name: "fixed window", new: func() error { _, err := NewFixedWindow(0, time.Second); return err }},
+		{name: "sliding window log", new: func() error { _, err := NewSlidingWindowLog(1, 0); return err }},
+		{
+			name: "nil clock",
+			new:  func() error { _, err := NewFixedWindow(1, time.Second, WithClock(nil)); return err },
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.ErrorIs(t, tc.new(), errInvalidParam)
+		})
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch)
+	// 10 tokens per second, up to 5 tokens
+	tb, err := NewTokenBucket(10, time.Second, 5, WithClock(clk))
+	require.NoError(t, err)
+
+	// burst
+	assert.Equal(t, 5, allowed(tb))
+
+	clk.Advance(250 * time.Millisecond)
+	assert.InDelta(t, 2.5, tb.Tokens(), 0.0001)
+	assert.Equal(t, 2, allowed(tb))
+
+	// never more than burst
+	clk.Advance(time.Hour)
+	assert.Equal(t, 5, allowed(tb))
+
+	// reservations take tokens from the future
+	r1 := tb.Reserve()
+	r2 := tb.Reserve()
+	assert.True(t, r1.OK())
+	assert.Equal(t, 100*time.Millisecond, r1.Delay())
+	assert.Equal(t, 200*time.Millisecond, r2.Delay())
+
+	r2.Cancel()
+	assert.Equal(t, 200*time.Millisecond, tb.Reserve().Delay())
+
+	clk.Advance(time.Second)
+	// canceling an available reservation gives the token back, but never more than burst
+	r1.Cancel()
+	assert.Equal(t, 5, allowed(tb))
+	clk.Advance(100 * time.Millisecond)
+	r3 := tb.Reserve()
+	assert.Zero(t, r3.Delay())
+	// canceling more than once gives back only one token
+	r3.Cancel()
+	r3.Cancel()
+	assert.Equal(t, 1, allowed(tb))
+}
+
+func TestLeakyBucket(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch)
+	// a permit every 100ms, at most 2 callers waiting
+	lb, err := NewLeakyBucket(10, time.Second, 2, WithClock(clk))
+	require.NoError(t, err)
+
+	// no burst
+	assert.Equal(t, 1, allowed(lb))
+
+	r1 := lb.Reserve()
+	r2 := lb.Reserve()
+	assert.Equal(t, 100*time.Millisecond, r1.Delay())
+	assert.Equal(t, 200*time.Millisecond, r2.Delay())
+
+	// the bucket is full
+	r3 := lb.Reserve()
+	assert.False(t, r3.OK())
+	err = lb.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	r2.Cancel()
+	assert.Equal(t, 200*time.Millisecond, lb.Reserve().Delay())
+
+	clk.Advance(time.Second)
+	assert.Equal(t, 1, allowed(lb))
+}
+
+func TestFixedWindow(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch.Add(900 * time.Millisecond))
+	fw, err := NewFixedWindow(3, time.Second, WithClock(clk))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, allowed(fw))
+
+	// the next window starts at the second boundary
+	r := fw.Reserve()
+	assert.Equal(t, 100*time.Millisecond, r.Delay())
+	r.Cancel()
+
+	clk.Advance(100 * time.Millisecond)
+	// up to 2 * limit around the boundary
+	assert.Equal(t, 3, allowed(fw))
+
+	r = fw.Reserve()
+	assert.Equal(t, time.Second, r.Delay())
+	for range 3 {
+		fw.Reserve()
+	}
+	assert.Equal(t, 2*time.Second, fw.Reserve().Delay())
+}
+
+func TestSlidingWindowLog(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(epoch)
+	sw, err := NewSlidingWindowLog(3, time.Second, WithClock(clk))
+	require.NoError(t, err)
+
+	assert.True(t, sw.Allow())
+	clk.Advance(400 * time.Millisecond)
+	assert.Equal(t, 2, allowed(sw))
+
+	// the first permit leaves the window at 1s
+	clk.Advance(500 * time.Millisecond)
+	r := sw.Reserve()
+	assert.Equal(t, 100*time.Millisecond, r.Delay())
+	r.Cancel()
+
+	clk.Advance(100 * time.Millisecond)
+	// no burst around the boundary
+	assert.Equal(t, 1, allowed(sw))
+	assert.Equal(t, 400*time.Millisecond, sw.Reserve().Delay())
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		new  func(c clock.Clock) (Limiter, error)
+	}{
+		{
+			name: "token bucket",
+			new:  func(c clock.Clock) (Limiter, error) { return NewTokenBucket(1, time.Second, 1, WithClock(c)) },
+		}, {
+			name: "leaky bucket",
+			new:  func(c clock.Clock) (Limiter, error) { return NewLeakyBucket(1, time.Second, 1, WithClock(c)) },
+		}, {
+			name: "fixed window",
+			new:  func(c clock.Clock) (Limiter, error) { return NewFixedWindow(1, time.Second, WithClock(c)) },
+		}, {
+			name: "sliding window log",
+			new:  func(c clock.Clock) (Limiter, error) { return NewSlidingWindowLog(1, time.Second, WithClock(c)) },
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clk := clock.NewManual(epoch)
+			l, err := tc.new(clk)
+			require.NoError(t, err)
+
+			require.NoError(t, l.Wait(context.Background()))
+
+			// wait for the next permit
+			res := make(chan error, 1)
+			go func() {
+				res <- l.Wait(context.Background())
+			}()
+			require.NoError(t, clk.BlockUntil(context.Background(), 1))
+			clk.Advance(time.Second)
+			require.NoError(t, <-res)
+
+			// the next permit is later than the deadline
+			ctx, cancel := clock.WithTimeout(context.Background(), clk, 500*time.Millisecond)
+			defer cancel()
+			err = l.Wait(ctx)
+			assert.ErrorIs(t, err, ErrWouldExceedDeadline)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+			// the permit is given back when ctx is canceled
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				res <- l.Wait(ctx)
+			}()
+			require.NoError(t, clk.BlockUntil(context.Background(), 2))
+			cancel()
+			assert.ErrorIs(t, <-res, context.Canceled)
+
+			assert.Equal(t, time.Second, l.Reserve().Delay())
+
+			ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+			assert.ErrorIs(t, l.Wait(ctx), context.Canceled)
+		})
+	}
+}
+
+func TestReservation(t *testing.T) {
+	t.Parallel()
+
+	lb, err := NewLeakyBucket(1, time.Second, 0, WithClock(clock.NewManual(epoch)))
+	require.NoError(t, err)
+
+	r := lb.Reserve()
+	assert.True(t, r.OK())
+	assert.Zero(t, r.Delay())
+
+	r = lb.Reserve()
+	assert.False(t, r.OK())
+	assert.Greater(t, r.Delay(), time.Hour)
+	// canceling a rejected reservation does nothing
+	r.Cancel()
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// TokenBucket refills limit tokens every period up to burst tokens, each permit takes a token.
+// It allows bursts of up to burst permits while keeping the average rate.
+type TokenBucket struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	rate  float64 // tokens per nanosecond
+	burst float64
+
+	tokens float64   // tokens at last, negative when there are reservations in the future
+	last   time.Time // last time tokens is updated
+}
+
+func (tb *TokenBucket) Allow() bool {
+	return allow(tb.clock, tb)
+}
+
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, tb.clock, tb)
+}
+
+func (tb *TokenBucket) Reserve() *Reservation {
+	return reserve(tb.clock, tb)
+}
+
+// Tokens returns the available tokens now.
+func (tb *TokenBucket) Tokens() float64 {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.advance(tb.clock.Now())
+	return tb.tokens
+}
+
+func (tb *TokenBucket) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.advance(now)
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return now, true
+	}
+
+	at := now.Add(time.Duration(math.Ceil((1 - tb.tokens) / tb.rate)))
+	if at.Sub(now) > maxWait {
+		return at, false
+	}
+	tb.tokens--
+	return at, true
+}
+
+func (tb *TokenBucket) cancel(_ time.Time, now time.Time) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.advance(now)
+	tb.tokens = min(tb.tokens+1, tb.burst)
+}
+
+func (tb *TokenBucket) advance(now time.Time) {
+	if now.After(tb.last) {
+		tb.tokens = min(tb.tokens+float64(now.Sub(tb.last))*tb.rate, tb.burst)
+		tb.last = now
+	}
+}
+
+// NewTokenBucket creates a TokenBucket refilling limit tokens every period, it starts full with burst tokens.
+func NewTokenBucket(limit int, period time.Duration, burst int, opts ...option.Opt[Options]) (*TokenBucket, error) {
+	if limit <= 0 || period <= 0 {
+		return nil, fmt.Errorf("%w: limit and period should be greater than 0", errInvalidParam)
+	}
+	if burst <= 0 {
+		return nil, fmt.Errorf("%w: burst should be greater than 0", errInvalidParam)
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenBucket{
+		clock:  o.clock,
+		rate:   float64(limit) / float64(period),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   o.clock.Now(),
+	}, nil
+}
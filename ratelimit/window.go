@@ -0,0 +1,190 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var (
+	_ Limiter = (*FixedWindow)(nil)
+	_ Limiter = (*SlidingWindowLog)(nil)
+)
+
+// FixedWindow grants at most limit permits in each window aligned to the zero time.
+// It is cheap but allows up to 2 * limit permits around the boundary of two windows.
+type FixedWindow struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	limit  int
+	window time.Duration
+
+	counts map[int64]int // permits of the current window and the reserved future windows
+}
+
+func (fw *FixedWindow) Allow() bool {
+	return allow(fw.clock, fw)
+}
+
+func (fw *FixedWindow) Wait(ctx context.Context) error {
+	return wait(ctx, fw.clock, fw)
+}
+
+func (fw *FixedWindow) Reserve() *Reservation {
+	return reserve(fw.clock, fw)
+}
+
+func (fw *FixedWindow) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	cur := fw.index(now)
+	for idx := range fw.counts {
+		if idx < cur {
+			delete(fw.counts, idx)
+		}
+	}
+
+	idx := cur
+	for fw.counts[idx] >= fw.limit {
+		idx++
+	}
+
+	at := now
+	if idx > cur {
+		at = time.Unix(0, idx*int64(fw.window)).In(now.Location())
+	}
+	if at.Sub(now) > maxWait {
+		return at, false
+	}
+
+	fw.counts[idx]++
+	return at, true
+}
+
+func (fw *FixedWindow) cancel(at time.Time, _ time.Time) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	idx := fw.index(at)
+	if fw.counts[idx] > 0 {
+		fw.counts[idx]--
+	}
+}
+
+func (fw *FixedWindow) index(t time.Time) int64 {
+	return t.UnixNano() / int64(fw.window)
+}
+
+// NewFixedWindow creates a FixedWindow granting at most limit permits in each window.
+func NewFixedWindow(limit int, window time.Duration, opts ...option.Opt[Options]) (*FixedWindow, error) {
+	if limit <= 0 || window <= 0 {
+		return nil, fmt.Errorf("%w: limit and window should be greater than 0", errInvalidParam)
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &FixedWindow{
+		clock:  o.clock,
+		limit:  limit,
+		window: window,
+		counts: make(map[int64]int),
+	}, nil
+}
+
+// SlidingWindowLog grants at most limit permits in any window of the given duration
+// by logging the time of every permit, it is exact but takes memory proportional to limit.
+type SlidingWindowLog struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	limit  int
+	window time.Duration
+
+	log []time.Time // time of the permits in the window in ascending order, including the reserved ones
+}
+
+func (sw *SlidingWindowLog) Allow() bool {
+	return allow(sw.clock, sw)
+}
+
+func (sw *SlidingWindowLog) Wait(ctx context.Context) error {
+	return wait(ctx, sw.clock, sw)
+}
+
+func (sw *SlidingWindowLog) Reserve() *Reservation {
+	return reserve(sw.clock, sw)
+}
+
+func (sw *SlidingWindowLog) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	// drop the permits out of the window (now - window, now]
+	expired := 0
+	for expired < len(sw.log) && !sw.log[expired].After(now.Add(-sw.window)) {
+		expired++
+	}
+	sw.log = sw.log[expired:]
+
+	at := now
+	if n := len(sw.log); n > 0 {
+		// keep the log in ascending order
+		at = laterOf(at, sw.log[n-1])
+		if n >= sw.limit {
+			// any limit consecutive permits span at least a window
+			at = laterOf(at, sw.log[n-sw.limit].Add(sw.window))
+		}
+	}
+	if at.Sub(now) > maxWait {
+		return at, false
+	}
+
+	sw.log = append(sw.log, at)
+	return at, true
+}
+
+func (sw *SlidingWindowLog) cancel(at time.Time, _ time.Time) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	for i := len(sw.log) - 1; i >= 0; i-- {
+		if sw.log[i].Equal(at) {
+			sw.log = slices.Delete(sw.log, i, i+1)
+			return
+		}
+	}
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// NewSlidingWindowLog creates a SlidingWindowLog granting at most limit permits in any window.
+func NewSlidingWindowLog(limit int, window time.Duration, opts ...option.Opt[Options]) (*SlidingWindowLog, error) {
+	if limit <= 0 || window <= 0 {
+		return nil, fmt.Errorf("%w: limit and window should be greater than 0", errInvalidParam)
+	}
+
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SlidingWindowLog{
+		clock:  o.clock,
+		limit:  limit,
+		window: window,
+		log:    make([]time.Time, 0, limit),
+	}, nil
+}
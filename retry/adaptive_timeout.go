@@ -8,6 +8,12 @@ import (
 
 var _ Strategy = (*AdaptiveTimeoutStrategy)(nil)
 
+// AdaptiveTimeoutStrategy wraps a Strategy and stops retrying once there are too many failures recently.
+//
+// The results reported by Report are recorded in a sliding window of bufferSize * 64 bits,
+// a set bit means a failure. The window is a lock-free ring buffer:
+// each report takes the next bit position atomically and overwrites the oldest result,
+// so the window rotates as new results come in.
 type AdaptiveTimeoutStrategy struct {
 	strategy  Strategy // basic retry strategy
 	threshold int      // timeout threshold
@@ -15,7 +21,8 @@ type AdaptiveTimeoutStrategy struct {
 	bufferSize int      // size of the slide window
 	ringBuffer []uint64 // using as a slide window to store timeout information
 
-	totalBit uint64
+	totalBit uint64 // total bits of the slide window
+	reqCnt   uint64 // count of reported results, the next bit position is reqCnt % totalBit
 }
 
 func (a *AdaptiveTimeoutStrategy) Next() (time.Duration, bool) {
@@ -37,16 +44,61 @@ func (a *AdaptiveTimeoutStrategy) NextWithRetried(retriedTimes int32) (time.Dura
 func (a *AdaptiveTimeoutStrategy) Report(err error) Strategy {
 	if err == nil {
 		a.markAsSuccess()
-		return a
+	} else {
+		a.markAsFailure()
 	}
 
-	a.markAsFailure()
+	a.strategy.Report(err)
 	return a
 }
 
-func (a *AdaptiveTimeoutStrategy) markAsSuccess() {}
+// Reset resets the wrapped strategy, the slide window is kept
+// since it records the failures of all the calls rather than a single one.
+func (a *AdaptiveTimeoutStrategy) Reset() {
+	a.strategy.Reset()
+}
+
+// FailureCount returns the count of failures in the slide window.
+func (a *AdaptiveTimeoutStrategy) FailureCount() int {
+	return a.getFailureCnt()
+}
 
-func (a *AdaptiveTimeoutStrategy) markAsFailure() {}
+// FailureRatio returns the ratio of failures to the results in the slide window,
+// 0 if nothing has been reported yet.
+func (a *AdaptiveTimeoutStrategy) FailureRatio() float64 {
+	total := min(atomic.LoadUint64(&a.reqCnt), a.totalBit)
+	if total == 0 {
+		return 0
+	}
+	return float64(a.getFailureCnt()) / float64(total)
+}
+
+func (a *AdaptiveTimeoutStrategy) markAsSuccess() {
+	slot, mask := a.nextBit()
+	for {
+		old := atomic.LoadUint64(&a.ringBuffer[slot])
+		if old&mask == 0 || atomic.CompareAndSwapUint64(&a.ringBuffer[slot], old, old&^mask) {
+			return
+		}
+	}
+}
+
+func (a *AdaptiveTimeoutStrategy) markAsFailure() {
+	slot, mask := a.nextBit()
+	for {
+		old := atomic.LoadUint64(&a.ringBuffer[slot])
+		if old&mask != 0 || atomic.CompareAndSwapUint64(&a.ringBuffer[slot], old, old|mask) {
+			return
+		}
+	}
+}
+
+// nextBit takes the next bit position of the slide window,
+// returns the index of the uint64 in ring buffer and the mask of the bit.
+func (a *AdaptiveTimeoutStrategy) nextBit() (int, uint64) {
+	idx := (atomic.AddUint64(&a.reqCnt, 1) - 1) % a.totalBit
+	return int(idx / 64), uint64(1) << (idx % 64)
+}
 
 func (a *AdaptiveTimeoutStrategy) getFailureCnt() int {
 	var cnt int
@@ -58,12 +110,16 @@ func (a *AdaptiveTimeoutStrategy) getFailureCnt() int {
 	return cnt
 }
 
+// NewAdaptiveTimeoutStrategy creates an AdaptiveTimeoutStrategy whose slide window holds the latest
+// bufferSize * 64 results, it stops retrying when the failures in the window reach threshold.
+// bufferSize less than 1 is treated as 1.
 func NewAdaptiveTimeoutStrategy(strategy Strategy, bufferSize int, threshold int) *AdaptiveTimeoutStrategy {
+	bufferSize = max(bufferSize, 1)
 	return &AdaptiveTimeoutStrategy{
 		strategy:   strategy,
 		threshold:  threshold,
 		bufferSize: bufferSize,
 		ringBuffer: make([]uint64, bufferSize),
-		totalBit:   uint64(64) & uint64(bufferSize),
+		totalBit:   uint64(64) * uint64(bufferSize),
 	}
 }
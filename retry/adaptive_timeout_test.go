@@ -1,10 +1,15 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/JrMarcco/jit/internal/errs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -128,6 +133,104 @@ func TestAdaptiveTimeoutRetry_NextWithRetried(t *testing.T) {
 	}
 }
 
+func TestAdaptiveTimeoutStrategy_Report(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	fis, err := NewFixedIntervalStrategy(time.Second, 0)
+	require.NoError(t, err)
+	s := NewAdaptiveTimeoutStrategy(fis, 1, 32)
+	assert.Zero(t, s.FailureRatio())
+
+	for range 16 {
+		s.Report(bizErr)
+		s.Report(nil)
+	}
+	assert.Equal(t, 16, s.FailureCount())
+	assert.InDelta(t, 0.5, s.FailureRatio(), 0.0001)
+
+	_, ok := s.Next()
+	assert.True(t, ok)
+
+	// the window is full, new failures overwrite the oldest successes
+	for range 32 {
+		s.Report(bizErr)
+	}
+	assert.Equal(t, 48, s.FailureCount())
+	assert.InDelta(t, 0.75, s.FailureRatio(), 0.0001)
+	_, ok = s.Next()
+	assert.False(t, ok)
+	_, ok = s.NextWithRetried(1)
+	assert.False(t, ok)
+
+	// recovered after successes rotate the failures out of the window
+	for range 64 {
+		s.Report(nil)
+	}
+	assert.Zero(t, s.FailureCount())
+	assert.Zero(t, s.FailureRatio())
+	_, ok = s.Next()
+	assert.True(t, ok)
+}
+
+func TestAdaptiveTimeoutStrategy_ReportConcurrently(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	fis, err := NewFixedIntervalStrategy(time.Second, 0)
+	require.NoError(t, err)
+	s := NewAdaptiveTimeoutStrategy(fis, 4, 200)
+
+	report := func(err error) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 64 {
+					s.Report(err)
+					_, _ = s.Next()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	// 512 failures fill the window of 256 bits twice,
+	// no bit is lost although the goroutines update the same uint64 concurrently
+	report(bizErr)
+	assert.Equal(t, 256, s.FailureCount())
+	assert.InDelta(t, 1.0, s.FailureRatio(), 0.0001)
+	_, ok := s.Next()
+	assert.False(t, ok)
+
+	report(nil)
+	assert.Zero(t, s.FailureCount())
+	_, ok = s.Next()
+	assert.True(t, ok)
+}
+
+func TestAdaptiveTimeoutStrategy_Retry(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	fis, err := NewFixedIntervalStrategy(time.Millisecond, 0)
+	require.NoError(t, err)
+	s := NewAdaptiveTimeoutStrategy(fis, 1, 3)
+
+	var calls atomic.Int32
+	err = Retry(context.Background(), s, func() error {
+		calls.Add(1)
+		return bizErr
+	})
+	assert.Equal(t, errs.ErrRetryTimeExhausted(bizErr), err)
+	// the strategy stops retrying once 3 failures are reported
+	assert.Equal(t, int32(3), calls.Load())
+}
+
 func ExampleAdaptiveTimeoutStrategy() {
 	ebs, err := NewExponentialBackoffStrategy(time.Second, 30*time.Second, 10)
 	if err != nil {
@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/internal/errs"
+)
+
+// ErrBudgetExhausted is returned by Retry and RetryValue when the RetryBudget does not allow more retries.
+var ErrBudgetExhausted = errors.New("[jit] retry budget exhausted")
+
+const budgetBuckets = 10
+
+// RetryBudget limits the retries of all the callers sharing it, so that retries do not multiply the load
+// of a callee in an outage. Within the sliding window, the retries allowed are
+// minRetries plus ratio times the successful requests.
+//
+// RetryBudget is safe for concurrent use, it is usually shared by all the calls to the same callee.
+type RetryBudget struct {
+	mu    sync.Mutex
+	clock clock.Clock
+
+	ratio      float64
+	minRetries int
+
+	width   time.Duration // duration of a bucket
+	buckets [budgetBuckets]budgetBucket
+}
+
+type budgetBucket struct {
+	epoch     int64
+	successes int
+	retries   int
+}
+
+// Success records a successful request.
+func (b *RetryBudget) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bucket(b.clock.Now()).successes++
+}
+
+// TryRetry takes a retry from the budget, returns false if the budget is exhausted.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	successes, retries := b.counts(now)
+	if float64(retries) >= float64(b.minRetries)+b.ratio*float64(successes) {
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+func (b *RetryBudget) bucket(now time.Time) *budgetBucket {
+	epoch := b.epoch(now)
+	bucket := &b.buckets[epoch%budgetBuckets]
+	if bucket.epoch != epoch {
+		*bucket = budgetBucket{epoch: epoch}
+	}
+	return bucket
+}
+
+func (b *RetryBudget) counts(now time.Time) (successes int, retries int) {
+	oldest := b.epoch(now) - budgetBuckets
+	for _, bucket := range b.buckets {
+		if bucket.epoch > oldest {
+			successes += bucket.successes
+			retries += bucket.retries
+		}
+	}
+	return successes, retries
+}
+
+func (b *RetryBudget) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(b.width)
+}
+
+// WithBudgetClock sets the clock of RetryBudget, clock.Real() by default.
+func WithBudgetClock(c clock.Clock) option.Opt[RetryBudget] {
+	return func(b *RetryBudget) {
+		if c != nil {
+			b.clock = c
+		}
+	}
+}
+
+// NewRetryBudget creates a RetryBudget allowing minRetries plus ratio times the successful requests
+// to be retried within window, e.g. a ratio of 0.1 allows 10% extra load in retries.
+func NewRetryBudget(
+	ratio float64, minRetries int, window time.Duration, opts ...option.Opt[RetryBudget],
+) (*RetryBudget, error) {
+	if ratio < 0 || minRetries < 0 {
+		return nil, errs.ErrInvalidRetryBudget(ratio, minRetries)
+	}
+	if window < budgetBuckets {
+		return nil, errs.ErrInvalidInterval(window)
+	}
+
+	b := &RetryBudget{
+		clock:      clock.Real(),
+		ratio:      ratio,
+		minRetries: minRetries,
+		width:      window / budgetBuckets,
+	}
+	option.Apply(b, opts...)
+	return b, nil
+}
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRetryBudget(-0.1, 0, time.Second)
+	assert.Error(t, err)
+	_, err = NewRetryBudget(0.1, -1, time.Second)
+	assert.Error(t, err)
+	_, err = NewRetryBudget(0.1, 1, 0)
+	assert.Error(t, err)
+
+	_, err = NewRetryBudget(0.1, 1, time.Second, WithBudgetClock(nil))
+	assert.NoError(t, err)
+}
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	b, err := NewRetryBudget(0.5, 1, 10*time.Second, WithBudgetClock(clk))
+	require.NoError(t, err)
+
+	// min retries
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	// 4 successes allow 2 more retries
+	for range 4 {
+		b.Success()
+	}
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	clk.Advance(5 * time.Second)
+	b.Success()
+	b.Success()
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	// the successes and retries of the first 5 seconds expire from the window,
+	// 2 successes and 1 retry are left
+	clk.Advance(5 * time.Second)
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+}
+
+func TestRetry_Budget(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	b, err := NewRetryBudget(0, 2, time.Minute)
+	require.NoError(t, err)
+
+	newStrategy := func() Strategy {
+		s, err := NewFixedIntervalStrategy(time.Millisecond, 10)
+		require.NoError(t, err)
+		return s
+	}
+
+	// the budget is shared by the calls
+	calls := 0
+	err = Retry(context.Background(), newStrategy(), func() error {
+		calls++
+		if calls == 2 {
+			return nil
+		}
+		return bizErr
+	}, WithBudget(b))
+	require.NoError(t, err)
+
+	calls = 0
+	err = Retry(context.Background(), newStrategy(), func() error {
+		calls++
+		return bizErr
+	}, WithBudget(b))
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	assert.ErrorIs(t, err, bizErr)
+	assert.Equal(t, 2, calls)
+}
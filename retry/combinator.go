@@ -0,0 +1,183 @@
+package retry
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+)
+
+var (
+	_ Strategy = (*maxElapsedStrategy)(nil)
+	_ Strategy = (*maxAttemptsStrategy)(nil)
+	_ Strategy = (*chainStrategy)(nil)
+	_ Strategy = (*cappedStrategy)(nil)
+)
+
+// maxElapsedStrategy stops retrying once the next retry would start after maxElapsed.
+type maxElapsedStrategy struct {
+	strategy   Strategy
+	maxElapsed time.Duration
+	clock      clock.Clock
+
+	mu    sync.Mutex
+	start time.Time // time the strategy is created or reset
+}
+
+func (m *maxElapsedStrategy) Next() (time.Duration, bool) {
+	return m.check(m.strategy.Next())
+}
+
+func (m *maxElapsedStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	return m.check(m.strategy.NextWithRetried(retriedTimes))
+}
+
+func (m *maxElapsedStrategy) Report(err error) Strategy {
+	m.strategy.Report(err)
+	return m
+}
+
+func (m *maxElapsedStrategy) Reset() {
+	m.strategy.Reset()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.start = m.clock.Now()
+}
+
+func (m *maxElapsedStrategy) check(interval time.Duration, ok bool) (time.Duration, bool) {
+	if !ok {
+		return 0, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.clock.Now().Add(interval).Sub(m.start) > m.maxElapsed {
+		return 0, false
+	}
+	return interval, true
+}
+
+// WithMaxElapsed wraps strategy to stop retrying once the next retry would start more than maxElapsed
+// after the strategy is created or reset, so the time spent on the first attempt counts too.
+// Create or reset it right before the first attempt. Only WithClock in opts takes effect.
+func WithMaxElapsed(strategy Strategy, maxElapsed time.Duration, opts ...option.Opt[Options]) Strategy {
+	clk := newOptions(opts).clock
+	return &maxElapsedStrategy{
+		strategy:   strategy,
+		maxElapsed: maxElapsed,
+		clock:      clk,
+		start:      clk.Now(),
+	}
+}
+
+// maxAttemptsStrategy stops retrying after maxTimes retries.
+type maxAttemptsStrategy struct {
+	strategy     Strategy
+	maxTimes     int32
+	retriedTimes int32
+}
+
+func (m *maxAttemptsStrategy) Next() (time.Duration, bool) {
+	if atomic.AddInt32(&m.retriedTimes, 1) > m.maxTimes {
+		return 0, false
+	}
+	return m.strategy.Next()
+}
+
+func (m *maxAttemptsStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	if retriedTimes > m.maxTimes {
+		return 0, false
+	}
+	return m.strategy.NextWithRetried(retriedTimes)
+}
+
+func (m *maxAttemptsStrategy) Report(err error) Strategy {
+	m.strategy.Report(err)
+	return m
+}
+
+func (m *maxAttemptsStrategy) Reset() {
+	m.strategy.Reset()
+	atomic.StoreInt32(&m.retriedTimes, 0)
+}
+
+// WithMaxAttempts wraps strategy to retry at most maxTimes times.
+func WithMaxAttempts(strategy Strategy, maxTimes int32) Strategy {
+	return &maxAttemptsStrategy{strategy: strategy, maxTimes: maxTimes}
+}
+
+// chainStrategy uses first for the first n retries and then for the others.
+type chainStrategy struct {
+	first        Strategy
+	n            int32
+	then         Strategy
+	retriedTimes int32
+}
+
+func (c *chainStrategy) Next() (time.Duration, bool) {
+	if atomic.AddInt32(&c.retriedTimes, 1) <= c.n {
+		return c.first.Next()
+	}
+	return c.then.Next()
+}
+
+func (c *chainStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	if retriedTimes <= c.n {
+		return c.first.NextWithRetried(retriedTimes)
+	}
+	return c.then.NextWithRetried(retriedTimes - c.n)
+}
+
+func (c *chainStrategy) Report(err error) Strategy {
+	c.first.Report(err)
+	c.then.Report(err)
+	return c
+}
+
+func (c *chainStrategy) Reset() {
+	c.first.Reset()
+	c.then.Reset()
+	atomic.StoreInt32(&c.retriedTimes, 0)
+}
+
+// Chain uses first for the first n retries and then for the following retries,
+// e.g. a few quick fixed interval retries followed by an exponential backoff.
+// first should allow at least n retries, otherwise retrying stops once first is exhausted.
+// The results reported are passed to both strategies.
+func Chain(first Strategy, n int32, then Strategy) Strategy {
+	return &chainStrategy{first: first, n: n, then: then}
+}
+
+// cappedStrategy limits the intervals of strategy to maxInterval.
+type cappedStrategy struct {
+	strategy    Strategy
+	maxInterval time.Duration
+}
+
+func (c *cappedStrategy) Next() (time.Duration, bool) {
+	interval, ok := c.strategy.Next()
+	return min(interval, c.maxInterval), ok
+}
+
+func (c *cappedStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	interval, ok := c.strategy.NextWithRetried(retriedTimes)
+	return min(interval, c.maxInterval), ok
+}
+
+func (c *cappedStrategy) Report(err error) Strategy {
+	c.strategy.Report(err)
+	return c
+}
+
+func (c *cappedStrategy) Reset() {
+	c.strategy.Reset()
+}
+
+// Capped wraps strategy to wait at most maxInterval between retries.
+func Capped(strategy Strategy, maxInterval time.Duration) Strategy {
+	return &cappedStrategy{strategy: strategy, maxInterval: maxInterval}
+}
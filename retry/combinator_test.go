@@ -0,0 +1,171 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// intervals returns the intervals of s until it stops retrying.
+func intervals(s Strategy) []time.Duration {
+	var res []time.Duration
+	for next, ok := s.Next(); ok && len(res) < 100; next, ok = s.Next() {
+		res = append(res, next)
+	}
+	return res
+}
+
+// intervalsWithRetried returns the intervals of s by NextWithRetried until it stops retrying.
+func intervalsWithRetried(s Strategy) []time.Duration {
+	var res []time.Duration
+	for i := int32(1); i <= 100; i++ {
+		next, ok := s.NextWithRetried(i)
+		if !ok {
+			break
+		}
+		res = append(res, next)
+	}
+	return res
+}
+
+func TestCombinators(t *testing.T) {
+	t.Parallel()
+
+	newFixed := func(interval time.Duration, maxTimes int32) Strategy {
+		s, err := NewFixedIntervalStrategy(interval, maxTimes)
+		require.NoError(t, err)
+		return s
+	}
+	newBackoff := func(maxTimes int32) Strategy {
+		s, err := NewExponentialBackoffStrategy(time.Second, time.Minute, maxTimes)
+		require.NoError(t, err)
+		return s
+	}
+
+	tcs := []struct {
+		name     string
+		strategy func() Strategy
+		want     []time.Duration
+	}{
+		{
+			name:     "max attempts",
+			strategy: func() Strategy { return WithMaxAttempts(newBackoff(0), 3) },
+			want:     []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		}, {
+			name:     "max attempts of a shorter strategy",
+			strategy: func() Strategy { return WithMaxAttempts(newBackoff(2), 3) },
+			want:     []time.Duration{time.Second, 2 * time.Second},
+		}, {
+			name: "chain",
+			strategy: func() Strategy {
+				return Chain(newFixed(100*time.Millisecond, 0), 2, newBackoff(3))
+			},
+			want: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+		}, {
+			name:     "capped",
+			strategy: func() Strategy { return Capped(newBackoff(5), 5*time.Second) },
+			want:     []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		}, {
+			name: "nested",
+			strategy: func() Strategy {
+				return WithMaxAttempts(Capped(Chain(newFixed(time.Millisecond, 0), 1, newBackoff(0)), 3*time.Second), 4)
+			},
+			want: []time.Duration{time.Millisecond, time.Second, 2 * time.Second, 3 * time.Second},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := tc.strategy()
+			assert.Equal(t, tc.want, intervals(s))
+			// NextWithRetried is consistent with Next
+			assert.Equal(t, tc.want, intervalsWithRetried(tc.strategy()))
+
+			// reset to be reused
+			s.Reset()
+			assert.Equal(t, tc.want, intervals(s))
+			assert.Same(t, s, s.Report(nil))
+		})
+	}
+}
+
+func TestWithMaxElapsed(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	backoff, err := NewExponentialBackoffStrategy(time.Second, time.Minute, 0)
+	require.NoError(t, err)
+	s := WithMaxElapsed(backoff, 10*time.Second, WithClock(clk))
+
+	// 1s + 2s + 4s, the next retry at 15s exceeds 10s
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		next, ok := s.Next()
+		require.True(t, ok)
+		assert.Equal(t, want, next)
+		clk.Advance(next)
+	}
+	_, ok := s.Next()
+	assert.False(t, ok)
+	_, ok = s.NextWithRetried(4)
+	assert.False(t, ok)
+	// the elapsed time is also checked for the interval of a given retried times
+	next, ok := s.NextWithRetried(1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, next)
+
+	// the elapsed time starts over after reset
+	s.Reset()
+	next, ok = s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, next)
+	assert.Same(t, s, s.Report(nil))
+
+	// the time spent on the first attempt counts towards maxElapsed
+	s.Reset()
+	clk.Advance(11 * time.Second)
+	_, ok = s.Next()
+	assert.False(t, ok)
+	s.Reset()
+	clk.Advance(9 * time.Second)
+	next, ok = s.Next()
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, next)
+}
+
+func TestStrategy_Reset(t *testing.T) {
+	t.Parallel()
+
+	fixed, err := NewFixedIntervalStrategy(time.Second, 2)
+	require.NoError(t, err)
+	backoff, err := NewExponentialBackoffStrategy(time.Second, 2*time.Second, 3)
+	require.NoError(t, err)
+	jitter, err := NewJitterStrategy(Capped(fixed, time.Second), JitterEqual)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		strategy Strategy
+		want     int
+	}{
+		{name: "fixed interval", strategy: fixed, want: 2},
+		{name: "exponential backoff", strategy: backoff, want: 3},
+		{name: "adaptive timeout", strategy: NewAdaptiveTimeoutStrategy(backoff, 1, 10), want: 3},
+		{name: "jitter", strategy: jitter, want: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.strategy.Reset()
+			assert.Len(t, intervals(tc.strategy), tc.want)
+			assert.Empty(t, intervals(tc.strategy))
+
+			tc.strategy.Reset()
+			assert.Len(t, intervals(tc.strategy), tc.want)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// permanentError marks an error that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps err so that Retry and RetryValue stop retrying and return err immediately.
+// Permanent(nil) returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isPermanent reports whether err is wrapped by Permanent and returns the original error.
+func isPermanent(err error) (error, bool) {
+	var pe *permanentError
+	if errors.As(err, &pe) {
+		return pe.err, true
+	}
+	return err, false
+}
+
+// RetryAfterError is implemented by errors carrying a server-provided retry delay,
+// e.g. the Retry-After header of an HTTP 429 or 503 response.
+// Retry waits at least RetryAfter before the next attempt.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.delay
+}
+
+// RetryAfter wraps err with a delay that the next attempt should wait at least.
+// RetryAfter(nil, d) returns nil.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// retryAfter returns the delay carried in err, 0 if there is none.
+func retryAfter(err error) time.Duration {
+	var rae RetryAfterError
+	if errors.As(err, &rae) {
+		return max(rae.RetryAfter(), 0)
+	}
+	return 0
+}
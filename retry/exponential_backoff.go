@@ -14,8 +14,6 @@ type ExponentialBackoffStrategy struct {
 	initInterval time.Duration
 	maxInterval  time.Duration
 
-	reachMaxInterval atomic.Bool
-
 	maxTimes     int32
 	retriedTimes int32
 }
@@ -31,20 +29,16 @@ func (e *ExponentialBackoffStrategy) NextWithRetried(retriedTimes int32) (time.D
 
 func (e *ExponentialBackoffStrategy) nextRetry(retriedTimes int32) (time.Duration, bool) {
 	if e.maxTimes <= 0 || retriedTimes <= e.maxTimes {
-		if e.reachMaxInterval.Load() {
-			return e.maxInterval, true
-		}
-
+		// the interval depends on retriedTimes only, so that Next and NextWithRetried are consistent.
 		const two = 2
-		interval := e.initInterval * time.Duration(math.Pow(two, float64(retriedTimes-1)))
+		interval := float64(e.initInterval) * math.Pow(two, float64(max(retriedTimes-1, 0)))
 
 		// interval = 0 prevents an input interval = 0 when create strategy.
-		// interval < 0 means the interval is over max int32 value after math.Pow.
-		if interval <= 0 || interval > e.maxInterval {
-			e.reachMaxInterval.Store(true)
+		// the interval is compared as float64 to prevent overflow after math.Pow.
+		if interval <= 0 || interval > float64(e.maxInterval) {
 			return e.maxInterval, true
 		}
-		return interval, true
+		return time.Duration(interval), true
 	}
 
 	return 0, false
@@ -54,6 +48,10 @@ func (e *ExponentialBackoffStrategy) Report(_ error) Strategy {
 	return e
 }
 
+func (e *ExponentialBackoffStrategy) Reset() {
+	atomic.StoreInt32(&e.retriedTimes, 0)
+}
+
 func NewExponentialBackoffStrategy(initialInterval, maxInterval time.Duration, maxRetryTime int32) (*ExponentialBackoffStrategy, error) {
 	if initialInterval <= 0 {
 		return nil, errs.ErrInvalidInterval(initialInterval)
@@ -35,6 +35,10 @@ func (f *FixedIntervalStrategy) Report(_ error) Strategy {
 	return f
 }
 
+func (f *FixedIntervalStrategy) Reset() {
+	atomic.StoreInt32(&f.retriedTimes, 0)
+}
+
 func NewFixedIntervalStrategy(interval time.Duration, maxTimes int32) (*FixedIntervalStrategy, error) {
 	if interval <= 0 {
 		return nil, errs.ErrInvalidInterval(interval)
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+)
+
+// Hedge calls fn and, if it has not finished after delay, calls fn again concurrently,
+// then returns the first success and cancels the other call through ctx.
+// If the first call fails before delay, the second call starts immediately.
+//
+// A permanent error returned by Permanent is returned at once without waiting for the other call.
+// If both calls fail their errors are joined. fn must be safe to call concurrently.
+// Only WithClock and WithBudget take effect, the second call takes a retry from the budget.
+func Hedge[T any](
+	ctx context.Context, delay time.Duration, fn func(ctx context.Context) (T, error), opts ...option.Opt[Options],
+) (T, error) {
+	o := newOptions(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	// cancel the call still running when Hedge returns
+	defer cancel()
+
+	type result struct {
+		val T
+		err error
+	}
+	results := make(chan result, 2)
+	call := func() {
+		val, err := fn(ctx)
+		results <- result{val: val, err: err}
+	}
+
+	timer := o.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	go call()
+	launched, running := 1, 1
+	hedge := func() {
+		if launched < 2 && (o.budget == nil || o.budget.TryRetry()) {
+			launched++
+			running++
+			go call()
+		}
+	}
+
+	var zero T
+	var errs []error
+	for {
+		select {
+		case <-timer.C():
+			hedge()
+		case r := <-results:
+			running--
+			if r.err == nil {
+				if o.budget != nil {
+					o.budget.Success()
+				}
+				return r.val, nil
+			}
+			if origin, ok := isPermanent(r.err); ok {
+				return zero, origin
+			}
+
+			errs = append(errs, r.err)
+			hedge()
+			if running == 0 {
+				return zero, errors.Join(errs...)
+			}
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedge(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	t.Run("first call succeeds before delay", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		val, err := Hedge(context.Background(), time.Hour, func(ctx context.Context) (int, error) {
+			return int(calls.Add(1)), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, val)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("hedged call wins", func(t *testing.T) {
+		t.Parallel()
+
+		clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+		var calls atomic.Int32
+		loserErr := make(chan error, 1)
+		res := make(chan int, 1)
+		go func() {
+			val, err := Hedge(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+				if calls.Add(1) == 1 {
+					// the slow call is canceled once the hedged call succeeds
+					<-ctx.Done()
+					loserErr <- ctx.Err()
+					return 0, ctx.Err()
+				}
+				return 2, nil
+			}, WithClock(clk))
+			assert.NoError(t, err)
+			res <- val
+		}()
+
+		require.NoError(t, clk.BlockUntil(context.Background(), 1))
+		clk.Advance(time.Second)
+		assert.Equal(t, 2, <-res)
+		assert.ErrorIs(t, <-loserErr, context.Canceled)
+	})
+
+	t.Run("first call fails before delay", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		val, err := Hedge(context.Background(), time.Hour, func(ctx context.Context) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, bizErr
+			}
+			return 2, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, val)
+	})
+
+	t.Run("both fail", func(t *testing.T) {
+		t.Parallel()
+
+		otherErr := errors.New("other error")
+		var calls atomic.Int32
+		_, err := Hedge(context.Background(), time.Hour, func(ctx context.Context) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, bizErr
+			}
+			return 0, otherErr
+		})
+		assert.ErrorIs(t, err, bizErr)
+		assert.ErrorIs(t, err, otherErr)
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		_, err := Hedge(context.Background(), time.Hour, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, Permanent(bizErr)
+		})
+		assert.Equal(t, bizErr, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("budget exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := NewRetryBudget(0, 0, time.Minute)
+		require.NoError(t, err)
+
+		var calls atomic.Int32
+		_, err = Hedge(context.Background(), time.Hour, func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			return 0, bizErr
+		}, WithBudget(b))
+		assert.ErrorIs(t, err, bizErr)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("ctx canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := Hedge(ctx, time.Hour, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
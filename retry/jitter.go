@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/internal/errs"
+)
+
+var _ Strategy = (*JitterStrategy)(nil)
+
+// JitterMode decides how JitterStrategy randomizes the interval of the wrapped strategy.
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+type JitterMode int8
+
+const (
+	// JitterFull sleeps a random duration in [0, d].
+	JitterFull JitterMode = iota
+	// JitterEqual sleeps d/2 plus a random duration in [0, d/2].
+	JitterEqual
+	// JitterDecorrelated sleeps min(d, random_between(base, prev*3)),
+	// base is the first interval of the wrapped strategy and prev is the previous sleep.
+	// It should wrap a strategy whose interval grows, e.g. ExponentialBackoffStrategy.
+	JitterDecorrelated
+)
+
+func (m JitterMode) String() string {
+	switch m {
+	case JitterFull:
+		return "full"
+	case JitterEqual:
+		return "equal"
+	case JitterDecorrelated:
+		return "decorrelated"
+	default:
+		return "unknown"
+	}
+}
+
+// JitterStrategy wraps a Strategy and randomizes its intervals,
+// so that clients retrying together do not hit the server at the same time.
+// Whether to retry is still decided by the wrapped strategy.
+type JitterStrategy struct {
+	strategy Strategy
+	mode     JitterMode
+
+	mu   sync.Mutex // guards rand and the decorrelated state
+	rand *rand.Rand
+	base time.Duration // first interval of the wrapped strategy, used by JitterDecorrelated
+	prev time.Duration // previous interval, used by JitterDecorrelated
+}
+
+func (j *JitterStrategy) Next() (time.Duration, bool) {
+	interval, ok := j.strategy.Next()
+	if !ok {
+		return 0, false
+	}
+	return j.jitter(interval), true
+}
+
+func (j *JitterStrategy) NextWithRetried(retriedTimes int32) (time.Duration, bool) {
+	interval, ok := j.strategy.NextWithRetried(retriedTimes)
+	if !ok {
+		return 0, false
+	}
+	return j.jitter(interval), true
+}
+
+func (j *JitterStrategy) Report(err error) Strategy {
+	j.strategy.Report(err)
+	return j
+}
+
+func (j *JitterStrategy) Reset() {
+	j.strategy.Reset()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.base, j.prev = 0, 0
+}
+
+func (j *JitterStrategy) jitter(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return interval
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch j.mode {
+	case JitterEqual:
+		half := interval / 2
+		return interval - half + j.between(0, half)
+	case JitterDecorrelated:
+		if j.base == 0 {
+			j.base, j.prev = interval, interval
+		}
+
+		upper := j.prev * 3
+		// prev * 3 overflows, the result is capped by interval anyway
+		if upper < j.prev {
+			upper = interval
+		}
+		j.prev = min(interval, j.between(j.base, upper))
+		return j.prev
+	default:
+		return j.between(0, interval)
+	}
+}
+
+// between returns a random duration in [lower, upper].
+func (j *JitterStrategy) between(lower, upper time.Duration) time.Duration {
+	if upper <= lower {
+		return lower
+	}
+	return lower + time.Duration(j.rand.Int64N(int64(upper-lower)+1))
+}
+
+// WithJitterSource sets the random source of JitterStrategy,
+// a fixed seed source such as rand.NewPCG makes the intervals deterministic.
+func WithJitterSource(src rand.Source) option.Opt[JitterStrategy] {
+	return func(j *JitterStrategy) {
+		if src != nil {
+			j.rand = rand.New(src)
+		}
+	}
+}
+
+func NewJitterStrategy(strategy Strategy, mode JitterMode, opts ...option.Opt[JitterStrategy]) (*JitterStrategy, error) {
+	if strategy == nil {
+		return nil, errs.NilErr("strategy")
+	}
+	if mode < JitterFull || mode > JitterDecorrelated {
+		return nil, errs.ErrInvalidJitterMode(int8(mode))
+	}
+
+	j := &JitterStrategy{
+		strategy: strategy,
+		mode:     mode,
+		rand:     rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	option.Apply(j, opts...)
+	return j, nil
+}
@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJitterStrategy(t *testing.T) {
+	t.Parallel()
+
+	fixed, err := NewFixedIntervalStrategy(time.Second, 3)
+	require.NoError(t, err)
+
+	_, err = NewJitterStrategy(nil, JitterFull)
+	assert.Equal(t, errs.NilErr("strategy"), err)
+
+	_, err = NewJitterStrategy(fixed, JitterMode(-1))
+	assert.Equal(t, errs.ErrInvalidJitterMode(-1), err)
+
+	s, err := NewJitterStrategy(fixed, JitterEqual)
+	require.NoError(t, err)
+	assert.Same(t, s, s.Report(nil))
+}
+
+func TestJitterStrategy_Next(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		mode JitterMode
+		// the bounds of the n-th interval, the wrapped strategy is
+		// an exponential backoff from 1s to 10s
+		wantBounds func(n int, prev time.Duration) (time.Duration, time.Duration)
+	}{
+		{
+			name: "full",
+			mode: JitterFull,
+			wantBounds: func(n int, _ time.Duration) (time.Duration, time.Duration) {
+				return 0, min(time.Second<<(n-1), 10*time.Second)
+			},
+		}, {
+			name: "equal",
+			mode: JitterEqual,
+			wantBounds: func(n int, _ time.Duration) (time.Duration, time.Duration) {
+				d := min(time.Second<<(n-1), 10*time.Second)
+				return d / 2, d
+			},
+		}, {
+			name: "decorrelated",
+			mode: JitterDecorrelated,
+			wantBounds: func(n int, prev time.Duration) (time.Duration, time.Duration) {
+				if n == 1 {
+					return time.Second, time.Second
+				}
+				return time.Second, min(3*prev, time.Second<<(n-1), 10*time.Second)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			newStrategy := func(seed uint64) *JitterStrategy {
+				backoff, err := NewExponentialBackoffStrategy(time.Second, 10*time.Second, 8)
+				require.NoError(t, err)
+				s, err := NewJitterStrategy(backoff, tc.mode, WithJitterSource(rand.NewPCG(seed, seed)))
+				require.NoError(t, err)
+				return s
+			}
+
+			s := newStrategy(1)
+			var intervals []time.Duration
+			var prev time.Duration
+			for n := 1; ; n++ {
+				interval, ok := s.Next()
+				if !ok {
+					assert.Equal(t, 9, n)
+					break
+				}
+
+				lower, upper := tc.wantBounds(n, prev)
+				assert.GreaterOrEqual(t, interval, lower)
+				assert.LessOrEqual(t, interval, upper)
+				intervals = append(intervals, interval)
+				prev = interval
+			}
+
+			// the same seed produces the same intervals
+			same := newStrategy(1)
+			for _, want := range intervals {
+				interval, ok := same.Next()
+				assert.True(t, ok)
+				assert.Equal(t, want, interval)
+			}
+		})
+	}
+}
+
+func TestJitterStrategy_NextWithRetried(t *testing.T) {
+	t.Parallel()
+
+	fixed, err := NewFixedIntervalStrategy(time.Second, 3)
+	require.NoError(t, err)
+	s, err := NewJitterStrategy(fixed, JitterFull, WithJitterSource(rand.NewPCG(1, 2)))
+	require.NoError(t, err)
+
+	interval, ok := s.NextWithRetried(3)
+	assert.True(t, ok)
+	assert.LessOrEqual(t, interval, time.Second)
+
+	_, ok = s.NextWithRetried(4)
+	assert.False(t, ok)
+}
+
+func TestJitterMode_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "full", JitterFull.String())
+	assert.Equal(t, "equal", JitterEqual.String())
+	assert.Equal(t, "decorrelated", JitterDecorrelated.String())
+	assert.Equal(t, "unknown", JitterMode(-1).String())
+}
@@ -2,39 +2,128 @@ package retry
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
 	"github.com/JrMarcco/jit/internal/errs"
 )
 
-func Retry(ctx context.Context, strategy Strategy, bizFunc func() error) error {
-	var ticker *time.Ticker
+// Options configures Retry, RetryValue and Hedge.
+type Options struct {
+	clock     clock.Clock
+	retryable func(err error) bool
+	onRetry   func(attempt int32, err error, next time.Duration)
+	budget    *RetryBudget
+}
+
+// WithClock sets the clock used to wait between retries, clock.Real() by default.
+func WithClock(c clock.Clock) option.Opt[Options] {
+	return func(o *Options) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
+
+// WithRetryable sets the predicate deciding whether an error should be retried,
+// errors it rejects are returned immediately. By default every error except Permanent ones is retried.
+func WithRetryable(retryable func(err error) bool) option.Opt[Options] {
+	return func(o *Options) {
+		o.retryable = retryable
+	}
+}
+
+// WithOnRetry sets a callback invoked before waiting for the next attempt,
+// attempt is the number of the failed attempt starting from 1 and next is the time to wait.
+func WithOnRetry(onRetry func(attempt int32, err error, next time.Duration)) option.Opt[Options] {
+	return func(o *Options) {
+		o.onRetry = onRetry
+	}
+}
+
+// WithBudget sets the RetryBudget consulted before every retry, successful calls are recorded to it.
+// Retry stops with ErrBudgetExhausted once the budget does not allow more retries.
+func WithBudget(budget *RetryBudget) option.Opt[Options] {
+	return func(o *Options) {
+		o.budget = budget
+	}
+}
+
+func newOptions(opts []option.Opt[Options]) *Options {
+	o := &Options{clock: clock.Real()}
+	option.Apply(o, opts...)
+	return o
+}
+
+func Retry(ctx context.Context, strategy Strategy, bizFunc func() error, opts ...option.Opt[Options]) error {
+	_, err := RetryValue(ctx, strategy, func(context.Context) (struct{}, error) {
+		return struct{}{}, bizFunc()
+	}, opts...)
+	return err
+}
+
+// RetryValue calls fn until it succeeds and returns its result.
+// The result of every attempt is reported to the strategy by Report.
+//
+// It stops retrying when the error is wrapped by Permanent or rejected by WithRetryable,
+// when the strategy or the budget set by WithBudget is exhausted, or when ctx is done.
+// An error carrying a delay by RetryAfter waits at least that delay before the next attempt.
+func RetryValue[T any](
+	ctx context.Context, strategy Strategy, fn func(ctx context.Context) (T, error), opts ...option.Opt[Options],
+) (T, error) {
+	o := newOptions(opts)
+
+	var timer clock.Timer
 	defer func() {
-		if ticker != nil {
-			ticker.Stop()
+		if timer != nil {
+			timer.Stop()
 		}
 	}()
 
-	for {
-		err := bizFunc()
+	for attempt := int32(1); ; attempt++ {
+		val, err := fn(ctx)
+		strategy.Report(err)
 		if err == nil {
-			return nil
+			if o.budget != nil {
+				o.budget.Success()
+			}
+			return val, nil
+		}
+
+		var zero T
+		if origin, ok := isPermanent(err); ok {
+			return zero, origin
+		}
+		if o.retryable != nil && !o.retryable(err) {
+			return zero, err
 		}
 
 		next, ok := strategy.Next()
 		if !ok {
-			return errs.ErrRetryTimeExhausted(err)
+			return zero, errs.ErrRetryTimeExhausted(err)
+		}
+		next = max(next, retryAfter(err))
+
+		if o.budget != nil && !o.budget.TryRetry() {
+			return zero, fmt.Errorf("%w, the latest error: %w", ErrBudgetExhausted, err)
+		}
+
+		if o.onRetry != nil {
+			o.onRetry(attempt, err, next)
 		}
 
-		if ticker == nil {
-			ticker = time.NewTicker(next)
+		if timer == nil {
+			timer = o.clock.NewTimer(next)
+		} else {
+			timer.Reset(next)
 		}
-		ticker.Reset(next)
 
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
+			return zero, ctx.Err()
+		case <-timer.C():
 		}
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
 	"github.com/JrMarcco/jit/internal/errs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,6 +64,196 @@ func TestRetry(t *testing.T) {
 	}
 }
 
+func TestRetry_Clock(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+	clk := clock.NewManual(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	s, err := NewExponentialBackoffStrategy(time.Minute, time.Hour, 3)
+	require.NoError(t, err)
+
+	var calls []time.Time
+	res := make(chan error, 1)
+	go func() {
+		res <- Retry(context.Background(), s, func() error {
+			calls = append(calls, clk.Now())
+			return bizErr
+		}, WithClock(clk))
+	}()
+
+	for _, d := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		require.NoError(t, clk.BlockUntil(context.Background(), 1))
+		clk.Advance(d)
+	}
+	assert.Equal(t, errs.ErrRetryTimeExhausted(bizErr), <-res)
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{
+		start,
+		start.Add(time.Minute),
+		start.Add(3 * time.Minute),
+		start.Add(7 * time.Minute),
+	}, calls)
+
+	// stop waiting when ctx is done
+	s, err = NewExponentialBackoffStrategy(time.Minute, time.Hour, 3)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		res <- Retry(ctx, s, func() error {
+			return bizErr
+		}, WithClock(clk))
+	}()
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	cancel()
+	assert.ErrorIs(t, <-res, context.Canceled)
+}
+
+func TestRetryValue(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+	stopErr := errors.New("stop error")
+
+	tcs := []struct {
+		name      string
+		results   []error
+		opts      []option.Opt[Options]
+		wantVal   int
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "succeed after retries",
+			results:   []error{bizErr, bizErr, nil},
+			wantVal:   3,
+			wantCalls: 3,
+		}, {
+			name:      "permanent error",
+			results:   []error{bizErr, Permanent(stopErr), nil},
+			wantErr:   stopErr,
+			wantCalls: 2,
+		}, {
+			name:    "not retryable",
+			results: []error{bizErr, stopErr, nil},
+			opts: []option.Opt[Options]{WithRetryable(func(err error) bool {
+				return !errors.Is(err, stopErr)
+			})},
+			wantErr:   stopErr,
+			wantCalls: 2,
+		}, {
+			name:      "exhausted",
+			results:   []error{bizErr, bizErr, bizErr, bizErr, nil},
+			wantErr:   errs.ErrRetryTimeExhausted(bizErr),
+			wantCalls: 4,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewFixedIntervalStrategy(time.Millisecond, 3)
+			require.NoError(t, err)
+
+			calls := 0
+			val, err := RetryValue(context.Background(), s, func(ctx context.Context) (int, error) {
+				err := tc.results[calls]
+				calls++
+				return calls, err
+			}, tc.opts...)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			if err == nil {
+				assert.Equal(t, tc.wantVal, val)
+			} else {
+				assert.Zero(t, val)
+			}
+		})
+	}
+}
+
+func TestRetryValue_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(start)
+
+	s, err := NewFixedIntervalStrategy(time.Minute, 3)
+	require.NoError(t, err)
+
+	type retried struct {
+		attempt int32
+		err     error
+		next    time.Duration
+	}
+	var retries []retried
+	var calls []time.Time
+
+	res := make(chan error, 1)
+	go func() {
+		_, err := RetryValue(context.Background(), s, func(ctx context.Context) (string, error) {
+			calls = append(calls, clk.Now())
+			switch len(calls) {
+			case 1:
+				// the server asks to wait longer than the strategy
+				return "", RetryAfter(bizErr, time.Hour)
+			case 2:
+				// the strategy interval wins when it is longer
+				return "", RetryAfter(bizErr, time.Second)
+			default:
+				return "ok", nil
+			}
+		}, WithClock(clk), WithOnRetry(func(attempt int32, err error, next time.Duration) {
+			retries = append(retries, retried{attempt: attempt, err: err, next: next})
+		}))
+		res <- err
+	}()
+
+	for _, d := range []time.Duration{time.Hour, time.Minute} {
+		require.NoError(t, clk.BlockUntil(context.Background(), 1))
+		clk.Advance(d)
+	}
+	require.NoError(t, <-res)
+
+	assert.Equal(t, []time.Time{start, start.Add(time.Hour), start.Add(time.Hour + time.Minute)}, calls)
+	require.Len(t, retries, 2)
+	assert.Equal(t, int32(1), retries[0].attempt)
+	assert.Equal(t, time.Hour, retries[0].next)
+	assert.Equal(t, int32(2), retries[1].attempt)
+	assert.Equal(t, time.Minute, retries[1].next)
+	assert.ErrorIs(t, retries[1].err, bizErr)
+
+	var rae RetryAfterError
+	require.ErrorAs(t, retries[1].err, &rae)
+	assert.Equal(t, time.Second, rae.RetryAfter())
+}
+
+func TestPermanent(t *testing.T) {
+	t.Parallel()
+
+	bizErr := errors.New("biz error")
+
+	assert.NoError(t, Permanent(nil))
+	assert.NoError(t, RetryAfter(nil, time.Second))
+
+	err := fmt.Errorf("wrapped: %w", Permanent(bizErr))
+	assert.ErrorIs(t, err, bizErr)
+	assert.Equal(t, "wrapped: biz error", err.Error())
+
+	origin, ok := isPermanent(err)
+	assert.True(t, ok)
+	assert.Equal(t, bizErr, origin)
+
+	_, ok = isPermanent(bizErr)
+	assert.False(t, ok)
+	assert.Zero(t, retryAfter(bizErr))
+	assert.Zero(t, retryAfter(RetryAfter(bizErr, -time.Second)))
+}
+
 func ExampleRetry() {
 	bizErr := errors.New("biz error")
 	bizFunc := func() error {
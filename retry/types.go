@@ -6,4 +6,6 @@ type Strategy interface {
 	Next() (time.Duration, bool)
 	NextWithRetried(retriedTimes int32) (time.Duration, bool)
 	Report(err error) Strategy
+	// Reset clears the retried times so that the strategy can be reused by another call.
+	Reset()
 }
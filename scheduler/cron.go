@@ -0,0 +1,299 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronBounds cron 表达式中一个字段的取值范围。
+type cronBounds struct {
+	name  string
+	min   uint
+	max   uint
+	names map[string]uint // 字段值的别名，例如月份和星期的英文缩写
+}
+
+var (
+	secondBounds = cronBounds{name: "second", min: 0, max: 59}
+	minuteBounds = cronBounds{name: "minute", min: 0, max: 59}
+	hourBounds   = cronBounds{name: "hour", min: 0, max: 23}
+	domBounds    = cronBounds{name: "day of month", min: 1, max: 31}
+	monthBounds  = cronBounds{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 星期的 7 与 0 一样表示星期日。
+	dowBounds = cronBounds{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronDescriptors 预定义的 cron 表达式。
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// maxCronSearchYears 计算下一次执行时间时最多向后查找的年数，超过后认为表达式永远不会触发（例如 2 月 30 日）。
+const maxCronSearchYears = 5
+
+var _ Schedule = (*CronSchedule)(nil)
+
+// CronSchedule 由 cron 表达式定义的执行计划，每个字段用位图表示允许的取值。
+type CronSchedule struct {
+	second uint64
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// 日和星期都被限制时（都不是 * 或 ?），满足其中一个即可，与标准 cron 一致。
+	domAny bool
+	dowAny bool
+}
+
+// ParseCron 解析 cron 表达式。
+//
+// 支持 5 个字段（分 时 日 月 星期）和 6 个字段（秒 分 时 日 月 星期）的表达式，
+// 5 个字段的表达式在每分钟的第 0 秒执行。每个字段支持：
+//
+//   - 任意值，日和星期字段也可以使用 ?
+//     a        单个值，月份和星期可以使用英文缩写（JAN-DEC、SUN-SAT，不区分大小写）
+//     a-b      范围
+//     */n      从最小值开始每隔 n 个值
+//     a/n      从 a 开始到最大值每隔 n 个值
+//     a-b/n    范围内每隔 n 个值
+//     x,y,z    以上形式的组合
+//
+// 同时支持预定义的表达式：@yearly（@annually）、@monthly、@weekly、@daily（@midnight）和 @hourly。
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		expr, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown cron descriptor %q", errInvalidParam, spec)
+		}
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		// 秒固定为 0
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: cron spec %q should have 5 or 6 fields", errInvalidParam, spec)
+	}
+
+	s := &CronSchedule{}
+	var err error
+	if s.second, _, err = parseCronField(fields[0], secondBounds); err != nil {
+		return nil, err
+	}
+	if s.minute, _, err = parseCronField(fields[1], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseCronField(fields[2], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, s.domAny, err = parseCronField(fields[3], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseCronField(fields[4], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, s.dowAny, err = parseCronField(fields[5], dowBounds); err != nil {
+		return nil, err
+	}
+
+	// 星期的 7 转换为 0
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// MustParseCron 与 ParseCron 一致，解析失败时 panic，用于初始化固定的表达式。
+func MustParseCron(spec string) *CronSchedule {
+	s, err := ParseCron(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parseCronField 解析一个字段，返回字段的位图以及字段是否为 * 或 ?。
+func parseCronField(field string, bounds cronBounds) (uint64, bool, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		b, err := parseCronPart(part, bounds)
+		if err != nil {
+			return 0, false, err
+		}
+		bits |= b
+	}
+	return bits, field == "*" || field == "?", nil
+}
+
+func parseCronPart(part string, bounds cronBounds) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+	var start, end uint
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+		if rangeExpr == "?" && bounds.name != domBounds.name && bounds.name != dowBounds.name {
+			return 0, fmt.Errorf("%w: ? is only allowed in day of month and day of week", errInvalidParam)
+		}
+		start, end = bounds.min, bounds.max
+		if bounds.name == dowBounds.name {
+			// 7 与 0 重复
+			end = 6
+		}
+	default:
+		lo, hi, isRange := strings.Cut(rangeExpr, "-")
+
+		var err error
+		if start, err = parseCronValue(lo, bounds); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = parseCronValue(hi, bounds); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// a/n 表示从 a 到最大值
+			end = bounds.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("%w: invalid %s range %q", errInvalidParam, bounds.name, part)
+	}
+
+	step := uint(1)
+	if hasStep {
+		n, err := strconv.ParseUint(stepExpr, 10, 8)
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("%w: invalid %s step %q", errInvalidParam, bounds.name, part)
+		}
+		step = uint(n)
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << v
+	}
+	return bits, nil
+}
+
+func parseCronValue(expr string, bounds cronBounds) (uint, error) {
+	if v, ok := bounds.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.ParseUint(expr, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s value %q", errInvalidParam, bounds.name, expr)
+	}
+	if uint(v) < bounds.min || uint(v) > bounds.max {
+		return 0, fmt.Errorf(
+			"%w: %s value %d out of range [%d, %d]", errInvalidParam, bounds.name, v, bounds.min, bounds.max,
+		)
+	}
+	return uint(v), nil
+}
+
+// Next 返回 t 之后（不包含 t）第一个满足表达式的时间，按 t 所在的时区计算。
+// 表达式在 5 年内都不会触发时返回零值。
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+
+	// 从下一秒开始查找
+	t = t.Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + maxCronSearchYears
+
+	// 某个字段不匹配时，比它小的字段需要从最小值开始重新匹配。
+	// truncated 表示比当前字段小的字段是否已经被重置。
+	truncated := false
+
+wrap:
+	for t.Year() <= yearLimit {
+		for !has(s.month, uint(t.Month())) {
+			if !truncated {
+				truncated = true
+				t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+			}
+			t = t.AddDate(0, 1, 0)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+
+		for !s.dayMatches(t) {
+			if !truncated {
+				truncated = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			}
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+
+		for !has(s.hour, uint(t.Hour())) {
+			if !truncated {
+				truncated = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+			}
+			t = t.Add(time.Hour)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+
+		for !has(s.minute, uint(t.Minute())) {
+			if !truncated {
+				truncated = true
+				t = t.Truncate(time.Minute)
+			}
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+
+		for !has(s.second, uint(t.Second())) {
+			t = t.Add(time.Second)
+			if t.Second() == 0 {
+				continue wrap
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日和星期是否匹配。
+// 日和星期都被限制时满足其中一个即可，否则需要同时满足。
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, uint(t.Day()))
+	dowMatch := has(s.dow, uint(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, v uint) bool {
+	return bits&(1<<v) != 0
+}
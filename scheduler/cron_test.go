@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		spec    string
+		wantErr error
+	}{
+		{name: "5 fields", spec: "*/5 * * * *"},
+		{name: "6 fields", spec: "0 */5 * * * *"},
+		{name: "names", spec: "0 0 9 ? jan-mar,DEC mon-fri"},
+		{name: "descriptor", spec: "@Daily"},
+		{name: "unknown descriptor", spec: "@every 1s", wantErr: errInvalidParam},
+		{name: "too few fields", spec: "* * * *", wantErr: errInvalidParam},
+		{name: "too many fields", spec: "* * * * * * *", wantErr: errInvalidParam},
+		{name: "out of range", spec: "60 * * * * *", wantErr: errInvalidParam},
+		{name: "day of month is 0", spec: "* * 0 * *", wantErr: errInvalidParam},
+		{name: "invalid range", spec: "* 10-5 * * *", wantErr: errInvalidParam},
+		{name: "invalid step", spec: "*/0 * * * *", wantErr: errInvalidParam},
+		{name: "invalid value", spec: "a * * * *", wantErr: errInvalidParam},
+		{name: "question mark in minute", spec: "? * * * *", wantErr: errInvalidParam},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := ParseCron(tc.spec)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.NotNil(t, s)
+			}
+		})
+	}
+
+	assert.Panics(t, func() { MustParseCron("* * *") })
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	// 2025-01-01 是星期三
+	from := time.Date(2025, time.January, 1, 10, 20, 30, 500, time.UTC)
+
+	tcs := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			spec: "* * * * *",
+			from: from,
+			want: time.Date(2025, time.January, 1, 10, 21, 0, 0, time.UTC),
+		}, {
+			name: "every second",
+			spec: "* * * * * *",
+			from: from,
+			want: time.Date(2025, time.January, 1, 10, 20, 31, 0, time.UTC),
+		}, {
+			name: "exclusive",
+			spec: "0 * * * * *",
+			from: time.Date(2025, time.January, 1, 10, 20, 0, 0, time.UTC),
+			want: time.Date(2025, time.January, 1, 10, 21, 0, 0, time.UTC),
+		}, {
+			name: "step",
+			spec: "*/15 * * * *",
+			from: from,
+			want: time.Date(2025, time.January, 1, 10, 30, 0, 0, time.UTC),
+		}, {
+			name: "start with step",
+			spec: "10/20 * * * * *",
+			from: from,
+			want: time.Date(2025, time.January, 1, 10, 20, 50, 0, time.UTC),
+		}, {
+			name: "range with step",
+			spec: "0 0 8-18/4 * * *",
+			from: from,
+			want: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC),
+		}, {
+			name: "next day",
+			spec: "0 9 * * *",
+			from: from,
+			want: time.Date(2025, time.January, 2, 9, 0, 0, 0, time.UTC),
+		}, {
+			name: "day of week",
+			spec: "0 9 * * mon",
+			from: from,
+			want: time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC),
+		}, {
+			name: "sunday is 7",
+			spec: "0 9 * * 7",
+			from: from,
+			want: time.Date(2025, time.January, 5, 9, 0, 0, 0, time.UTC),
+		}, {
+			name: "day of month or day of week",
+			spec: "0 0 15 * fri",
+			from: from,
+			want: time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC),
+		}, {
+			name: "day of month and any day of week",
+			spec: "0 0 15 * ?",
+			from: from,
+			want: time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+		}, {
+			name: "last day of february",
+			spec: "0 0 29 2 *",
+			from: from,
+			want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		}, {
+			name: "next year",
+			spec: "@yearly",
+			from: from,
+			want: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			name: "month wrap",
+			spec: "0 0 0 31 * *",
+			from: time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		}, {
+			name: "time zone",
+			spec: "0 9 * * *",
+			from: from.In(shanghai),
+			want: time.Date(2025, time.January, 2, 9, 0, 0, 0, shanghai),
+		}, {
+			name: "never",
+			spec: "0 0 30 2 *",
+			from: from,
+			want: time.Time{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := ParseCron(tc.spec)
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(s.Next(tc.from)), "want %s, got %s", tc.want, s.Next(tc.from))
+		})
+	}
+}
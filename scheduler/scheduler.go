@@ -0,0 +1,442 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/pool"
+)
+
+var (
+	errInvalidParam = errors.New("[jit] invalid param")
+
+	ErrSchedulerIsStopped = errors.New("[jit] scheduler is stopped")
+)
+
+// Schedule 任务的执行计划。
+type Schedule interface {
+	// Next 返回 t 之后的下一次执行时间，返回零值表示不再执行。
+	Next(t time.Time) time.Time
+}
+
+// fixedRate 固定频率的执行计划。
+type fixedRate struct {
+	period time.Duration
+}
+
+func (s fixedRate) Next(t time.Time) time.Time {
+	return t.Add(s.period)
+}
+
+// OverlapPolicy 到达执行时间时上一次执行还未结束的处理策略。
+type OverlapPolicy int8
+
+const (
+	// OverlapSkip 跳过本次执行，默认策略。
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue 等待上一次执行结束后再执行，多次错过的执行会依次执行。
+	OverlapQueue
+	// OverlapConcurrent 与上一次执行并发执行。
+	OverlapConcurrent
+)
+
+func (op OverlapPolicy) String() string {
+	switch op {
+	case OverlapSkip:
+		return "skip"
+	case OverlapQueue:
+		return "queue"
+	case OverlapConcurrent:
+		return "concurrent"
+	default:
+		return "unknown"
+	}
+}
+
+// Scheduler 定时任务调度器，到达执行时间时把任务提交到 TaskPool 执行。
+//
+// 每个定时任务由一个独立的 goroutine 等待执行时间，任务本身在 TaskPool 中执行，
+// 所以 TaskPool 的容量和拒绝策略同样作用于定时任务。
+// 执行时间已经错过（例如提交任务被阻塞）时立即执行一次，并跳过其他错过的执行时间。
+// 任务被 TaskPool 接受后没有执行（例如被拒绝策略丢弃或者通过 ShutdownNow 返回）时视为本次执行结束。
+type Scheduler struct {
+	mu sync.Mutex
+
+	pool  pool.TaskPool
+	clock clock.Clock
+
+	errHandler func(err error) // 处理提交任务失败的错误，任务执行的错误由 TaskPool 处理
+
+	jobs    map[*Job]struct{}
+	stopped bool
+}
+
+// ScheduleAtFixedRate 按固定频率执行任务，第一次在 initialDelay 后执行，之后每隔 period 执行一次。
+func (s *Scheduler) ScheduleAtFixedRate(
+	task pool.Task, initialDelay time.Duration, period time.Duration, opts ...option.Opt[Job],
+) (*Job, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("%w: period should be greater than 0", errInvalidParam)
+	}
+	return s.schedule(task, fixedRate{period: period}, 0, s.clock.Now().Add(initialDelay), opts)
+}
+
+// ScheduleWithFixedDelay 按固定间隔执行任务，第一次在 initialDelay 后执行，之后在上一次执行结束 delay 后执行。
+// 固定间隔的任务不会重叠执行，OverlapPolicy 不生效。
+func (s *Scheduler) ScheduleWithFixedDelay(
+	task pool.Task, initialDelay time.Duration, delay time.Duration, opts ...option.Opt[Job],
+) (*Job, error) {
+	if delay <= 0 {
+		return nil, fmt.Errorf("%w: delay should be greater than 0", errInvalidParam)
+	}
+	return s.schedule(task, nil, delay, s.clock.Now().Add(initialDelay), opts)
+}
+
+// ScheduleCron 按 cron 表达式执行任务，表达式的格式见 ParseCron。
+func (s *Scheduler) ScheduleCron(spec string, task pool.Task, opts ...option.Opt[Job]) (*Job, error) {
+	cron, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return s.Schedule(cron, task, opts...)
+}
+
+// Schedule 按自定义的执行计划执行任务。
+func (s *Scheduler) Schedule(schedule Schedule, task pool.Task, opts ...option.Opt[Job]) (*Job, error) {
+	if schedule == nil {
+		return nil, fmt.Errorf("%w: schedule should not be nil", errInvalidParam)
+	}
+	return s.schedule(task, schedule, 0, schedule.Next(s.clock.Now()), opts)
+}
+
+func (s *Scheduler) schedule(
+	task pool.Task, schedule Schedule, delay time.Duration, first time.Time, opts []option.Opt[Job],
+) (*Job, error) {
+	if task == nil {
+		return nil, fmt.Errorf("%w: task should not be nil", errInvalidParam)
+	}
+
+	j := &Job{
+		s:        s,
+		task:     task,
+		schedule: schedule,
+		delay:    delay,
+		next:     first,
+		finished: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	option.Apply(j, opts...)
+	if j.overlap < OverlapSkip || j.overlap > OverlapConcurrent {
+		return nil, fmt.Errorf("%w: unknown overlap policy %d", errInvalidParam, j.overlap)
+	}
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return nil, ErrSchedulerIsStopped
+	}
+	s.jobs[j] = struct{}{}
+
+	go j.loop()
+	return j, nil
+}
+
+// Stop 停止所有定时任务，并拒绝新的定时任务，已经提交到 TaskPool 的任务不受影响。
+// Stop 不会关闭 TaskPool。
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	jobs := make([]*Job, 0, len(s.jobs))
+	for j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		j.Stop()
+	}
+}
+
+func (s *Scheduler) removeJob(j *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, j)
+}
+
+func (s *Scheduler) handleErr(err error) {
+	if s.errHandler != nil {
+		s.errHandler(err)
+	}
+}
+
+// WithClock 设置调度器使用的时钟，默认为 clock.Real()，测试时可以使用 clock.NewManual。
+func WithClock(c clock.Clock) option.Opt[Scheduler] {
+	return func(s *Scheduler) {
+		s.clock = c
+	}
+}
+
+// WithErrorHandler 设置提交任务失败时的错误处理器，错误处理器在定时任务的 goroutine 中同步调用。
+func WithErrorHandler(errHandler func(err error)) option.Opt[Scheduler] {
+	return func(s *Scheduler) {
+		s.errHandler = errHandler
+	}
+}
+
+// NewScheduler 创建在 p 中执行任务的调度器，p 的生命周期由调用者管理。
+func NewScheduler(p pool.TaskPool, opts ...option.Opt[Scheduler]) (*Scheduler, error) {
+	if p == nil {
+		return nil, fmt.Errorf("%w: task pool should not be nil", errInvalidParam)
+	}
+
+	s := &Scheduler{
+		pool:  p,
+		clock: clock.Real(),
+		jobs:  make(map[*Job]struct{}),
+	}
+	option.Apply(s, opts...)
+
+	if s.clock == nil {
+		return nil, fmt.Errorf("%w: clock should not be nil", errInvalidParam)
+	}
+	return s, nil
+}
+
+// Job 调度器中的定时任务。
+type Job struct {
+	mu sync.Mutex
+
+	s        *Scheduler
+	task     pool.Task
+	schedule Schedule      // 固定间隔的任务为 nil
+	delay    time.Duration // 固定间隔任务的执行间隔
+	overlap  OverlapPolicy
+
+	next    time.Time // 下一次执行时间
+	running int       // 已提交还未结束的执行次数
+	pending int       // OverlapQueue 策略下等待执行的次数
+
+	finished chan struct{} // 执行结束时通知固定间隔的任务
+	done     chan struct{} // 定时任务停止时关闭
+
+	ctx    context.Context // 提交任务使用的 ctx，停止时取消
+	cancel context.CancelFunc
+}
+
+// Next 返回下一次执行时间，定时任务已经停止时返回零值。
+func (j *Job) Next() time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.next
+}
+
+// Stop 停止定时任务，已经提交到 TaskPool 的执行不受影响，OverlapQueue 策略下等待执行的次数被丢弃。
+func (j *Job) Stop() {
+	j.cancel()
+
+	j.mu.Lock()
+	j.pending = 0
+	j.mu.Unlock()
+}
+
+// Done 返回定时任务停止（调用 Stop 或者执行计划不再有执行时间）时关闭的 chan。
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// loop 等待执行时间并提交任务，直到定时任务停止。
+func (j *Job) loop() {
+	defer func() {
+		j.mu.Lock()
+		j.next = time.Time{}
+		j.mu.Unlock()
+
+		j.cancel()
+		j.s.removeJob(j)
+		close(j.done)
+	}()
+
+	c := j.s.clock
+	for {
+		next := j.Next()
+		if next.IsZero() {
+			return
+		}
+
+		timer := c.NewTimer(next.Sub(c.Now()))
+		select {
+		case <-timer.C():
+		case <-j.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		submitted := j.fire()
+
+		if j.schedule == nil {
+			// 固定间隔的任务等待本次执行结束后再计算下一次执行时间
+			if submitted {
+				select {
+				case <-j.finished:
+				case <-j.ctx.Done():
+					return
+				}
+			}
+			j.setNext(c.Now().Add(j.delay))
+			continue
+		}
+
+		// 跳过已经错过的执行时间
+		now := c.Now()
+		next = j.schedule.Next(next)
+		for !next.IsZero() && next.Before(now) {
+			next = j.schedule.Next(next)
+		}
+		j.setNext(next)
+	}
+}
+
+func (j *Job) setNext(next time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.next = next
+}
+
+// fire 到达执行时间时按 OverlapPolicy 提交任务，返回是否提交了任务。
+func (j *Job) fire() bool {
+	j.mu.Lock()
+	if j.running > 0 {
+		switch j.overlap {
+		case OverlapSkip:
+			j.mu.Unlock()
+			return false
+		case OverlapQueue:
+			j.pending++
+			j.mu.Unlock()
+			return false
+		default:
+		}
+	} else {
+		// 丢弃之前的执行结束通知
+		select {
+		case <-j.finished:
+		default:
+		}
+	}
+	j.running++
+	j.mu.Unlock()
+
+	t := &jobTask{j: j}
+	f, err := pool.SubmitFunc(j.ctx, j.s.pool, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, t.Run(ctx)
+	})
+	if err != nil {
+		j.release()
+		if j.ctx.Err() == nil {
+			j.s.handleErr(err)
+		}
+		return false
+	}
+
+	// TaskPool 接受任务后仍然可能不执行（例如被拒绝策略丢弃或者通过 ShutdownNow 返回），
+	// 通过 Future 感知任务被丢弃并结束本次执行。
+	go t.watch(f)
+	return true
+}
+
+// finish 一次执行结束，OverlapQueue 策略下有等待执行的次数时返回 true，由调用者继续执行。
+func (j *Job) finish() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.pending > 0 {
+		j.pending--
+		return true
+	}
+	j.releaseLocked()
+	return false
+}
+
+// release 结束一次执行，不处理等待执行的次数。
+func (j *Job) release() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.releaseLocked()
+}
+
+// abort 执行被丢弃或者 panic 时结束一次执行，并丢弃等待执行的次数。
+func (j *Job) abort() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.pending = 0
+	j.releaseLocked()
+}
+
+func (j *Job) releaseLocked() {
+	j.running--
+	if j.running == 0 {
+		select {
+		case j.finished <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// jobTask 定时任务的一次执行。
+type jobTask struct {
+	j       *Job
+	claimed atomic.Bool // 开始执行或者被丢弃时设置，保证一次执行只结束一次
+}
+
+func (t *jobTask) Run(ctx context.Context) error {
+	if !t.claimed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	var errs []error
+	for {
+		if err := t.run(ctx); err != nil {
+			errs = append(errs, err)
+		}
+
+		// OverlapQueue 策略下等待执行的次数在当前 goroutine 中依次执行，
+		// 避免在 TaskPool 的工作 goroutine 中重新提交任务被阻塞。
+		if !t.j.finish() {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func (t *jobTask) run(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// 任务 panic 时结束本次执行，继续交给 TaskPool 处理
+			t.j.abort()
+			panic(r)
+		}
+	}()
+	return t.j.task.Run(ctx)
+}
+
+// watch 等待 Future 完成，任务没有开始执行就完成说明任务被 TaskPool 丢弃。
+func (t *jobTask) watch(f *pool.Future[struct{}]) {
+	<-f.Done()
+	if t.claimed.CompareAndSwap(false, true) {
+		t.j.abort()
+	}
+}
+
+// WithOverlapPolicy 设置到达执行时间时上一次执行还未结束的处理策略，默认为 OverlapSkip。
+func WithOverlapPolicy(policy OverlapPolicy) option.Opt[Job] {
+	return func(j *Job) {
+		j.overlap = policy
+	}
+}
@@ -0,0 +1,430 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
+	"github.com/JrMarcco/jit/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestScheduler(t *testing.T, initG int32, opts ...option.Opt[Scheduler]) (*Scheduler, *clock.Manual) {
+	p, err := pool.NewBlockTaskPool(initG, 8, pool.WithObserver(pool.NopObserver{}))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+	t.Cleanup(func() {
+		_, _ = p.ShutdownNow()
+	})
+
+	clk := clock.NewManual(epoch)
+	s, err := NewScheduler(p, append([]option.Opt[Scheduler]{WithClock(clk)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+	return s, clk
+}
+
+// advance 等待定时任务开始等待执行时间后推进时钟。
+func advance(t *testing.T, clk *clock.Manual, d time.Duration) {
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	clk.Advance(d)
+}
+
+// blockingTask 执行时通知 started，并阻塞直到 release 收到值。
+type blockingTask struct {
+	runs    atomic.Int32
+	running atomic.Int32
+	maxRun  atomic.Int32
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingTask() *blockingTask {
+	return &blockingTask{
+		started: make(chan struct{}, 8),
+		release: make(chan struct{}, 8),
+	}
+}
+
+func (b *blockingTask) Run(ctx context.Context) error {
+	b.runs.Add(1)
+	cur := b.running.Add(1)
+	defer b.running.Add(-1)
+	for {
+		old := b.maxRun.Load()
+		if cur <= old || b.maxRun.CompareAndSwap(old, cur) {
+			break
+		}
+	}
+
+	b.started <- struct{}{}
+	<-b.release
+	return nil
+}
+
+func TestNewScheduler(t *testing.T) {
+	t.Parallel()
+
+	p, err := pool.NewBlockTaskPool(1, 1)
+	require.NoError(t, err)
+
+	_, err = NewScheduler(nil)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = NewScheduler(p, WithClock(nil))
+	assert.ErrorIs(t, err, errInvalidParam)
+
+	s, err := NewScheduler(p)
+	require.NoError(t, err)
+
+	task := pool.TaskFunc(func(ctx context.Context) error { return nil })
+	_, err = s.ScheduleAtFixedRate(task, 0, 0)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = s.ScheduleWithFixedDelay(task, 0, -1)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = s.ScheduleCron("* * *", task)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = s.Schedule(nil, task)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = s.ScheduleAtFixedRate(nil, 0, time.Second)
+	assert.ErrorIs(t, err, errInvalidParam)
+	_, err = s.ScheduleAtFixedRate(task, 0, time.Second, WithOverlapPolicy(-1))
+	assert.ErrorIs(t, err, errInvalidParam)
+
+	s.Stop()
+	_, err = s.ScheduleAtFixedRate(task, 0, time.Second)
+	assert.ErrorIs(t, err, ErrSchedulerIsStopped)
+}
+
+func TestScheduler_FixedRate(t *testing.T) {
+	t.Parallel()
+
+	s, clk := newTestScheduler(t, 1)
+
+	runs := make(chan time.Time, 8)
+	j, err := s.ScheduleAtFixedRate(pool.TaskFunc(func(ctx context.Context) error {
+		runs <- clk.Now()
+		return nil
+	}), time.Second, 2*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, epoch.Add(time.Second), j.Next())
+
+	advance(t, clk, time.Second)
+	assert.Equal(t, epoch.Add(time.Second), <-runs)
+
+	advance(t, clk, 2*time.Second)
+	assert.Equal(t, epoch.Add(3*time.Second), <-runs)
+
+	// 错过的执行时间只执行一次
+	advance(t, clk, 7*time.Second)
+	assert.Equal(t, epoch.Add(10*time.Second), <-runs)
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	assert.Equal(t, epoch.Add(11*time.Second), j.Next())
+
+	j.Stop()
+	<-j.Done()
+	assert.True(t, j.Next().IsZero())
+	assert.Empty(t, runs)
+}
+
+func TestScheduler_FixedDelay(t *testing.T) {
+	t.Parallel()
+
+	s, clk := newTestScheduler(t, 1)
+
+	task := newBlockingTask()
+	j, err := s.ScheduleWithFixedDelay(task, time.Second, 2*time.Second)
+	require.NoError(t, err)
+
+	advance(t, clk, time.Second)
+	<-task.started
+
+	// 执行中推进时钟，下一次执行时间从执行结束时开始计算
+	clk.Advance(5 * time.Second)
+	task.release <- struct{}{}
+
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	assert.Equal(t, epoch.Add(8*time.Second), j.Next())
+
+	advance(t, clk, 2*time.Second)
+	<-task.started
+	task.release <- struct{}{}
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+	assert.Equal(t, epoch.Add(10*time.Second), j.Next())
+	assert.Equal(t, int32(2), task.runs.Load())
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	t.Parallel()
+
+	s, clk := newTestScheduler(t, 1)
+
+	runs := make(chan time.Time, 8)
+	j, err := s.ScheduleCron("*/15 * * * * *", pool.TaskFunc(func(ctx context.Context) error {
+		runs <- clk.Now()
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, epoch.Add(15*time.Second), j.Next())
+
+	advance(t, clk, 15*time.Second)
+	assert.Equal(t, epoch.Add(15*time.Second), <-runs)
+	advance(t, clk, 15*time.Second)
+	assert.Equal(t, epoch.Add(30*time.Second), <-runs)
+}
+
+func TestScheduler_OverlapPolicy(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		policy OverlapPolicy
+		// 第一次执行结束前错过的两次执行时间，最终执行的总次数
+		wantRuns int32
+		wantMax  int32
+	}{
+		{name: "skip", policy: OverlapSkip, wantRuns: 1, wantMax: 1},
+		{name: "queue", policy: OverlapQueue, wantRuns: 3, wantMax: 1},
+		{name: "concurrent", policy: OverlapConcurrent, wantRuns: 3, wantMax: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, clk := newTestScheduler(t, 4)
+
+			task := newBlockingTask()
+			j, err := s.ScheduleAtFixedRate(task, time.Second, time.Second, WithOverlapPolicy(tc.policy))
+			require.NoError(t, err)
+
+			advance(t, clk, time.Second)
+			<-task.started
+			advance(t, clk, time.Second)
+			advance(t, clk, time.Second)
+			// 等待第三次到达执行时间处理完成
+			require.NoError(t, clk.BlockUntil(context.Background(), 1))
+
+			if tc.policy == OverlapConcurrent {
+				<-task.started
+				<-task.started
+			}
+
+			for range tc.wantRuns {
+				task.release <- struct{}{}
+			}
+			if tc.policy == OverlapQueue {
+				<-task.started
+				<-task.started
+			}
+
+			assert.Eventually(t, func() bool {
+				return task.running.Load() == 0
+			}, time.Second, time.Millisecond)
+			j.Stop()
+			<-j.Done()
+
+			assert.Equal(t, tc.wantRuns, task.runs.Load())
+			assert.Equal(t, tc.wantMax, task.maxRun.Load())
+		})
+	}
+}
+
+func TestScheduler_SubmitError(t *testing.T) {
+	t.Parallel()
+
+	p, err := pool.NewBlockTaskPool(1, 1, pool.WithObserver(pool.NopObserver{}))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+	_, err = p.ShutdownNow()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var errs []error
+	clk := clock.NewManual(epoch)
+	s, err := NewScheduler(p, WithClock(clk), WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}))
+	require.NoError(t, err)
+	defer s.Stop()
+
+	_, err = s.ScheduleAtFixedRate(pool.TaskFunc(func(ctx context.Context) error {
+		return nil
+	}), time.Second, time.Second)
+	require.NoError(t, err)
+
+	advance(t, clk, time.Second)
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], pool.ErrPoolIsClosed)
+}
+
+// jobIdle 等待定时任务没有未结束的执行和等待执行的次数。
+func jobIdle(t *testing.T, j *Job) {
+	assert.Eventually(t, func() bool {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		return j.running == 0 && j.pending == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestScheduler_Discarded(t *testing.T) {
+	t.Parallel()
+
+	p, err := pool.NewBlockTaskPool(1, 1,
+		pool.WithObserver(pool.NopObserver{}), pool.WithRejectPolicy(pool.RejectPolicyDiscard))
+	require.NoError(t, err)
+	require.NoError(t, p.Start())
+	t.Cleanup(func() {
+		_, _ = p.ShutdownNow()
+	})
+
+	// 占满 goroutine 和任务队列，定时任务的执行被丢弃
+	blocker := newBlockingTask()
+	require.NoError(t, p.Submit(context.Background(), blocker))
+	<-blocker.started
+	require.NoError(t, p.Submit(context.Background(), blocker))
+
+	clk := clock.NewManual(epoch)
+	s, err := NewScheduler(p, WithClock(clk))
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+
+	tcs := []struct {
+		name     string
+		schedule func(task pool.Task) (*Job, error)
+	}{
+		{
+			name: "fixed rate",
+			schedule: func(task pool.Task) (*Job, error) {
+				return s.ScheduleAtFixedRate(task, time.Second, time.Second)
+			},
+		}, {
+			name: "fixed delay",
+			schedule: func(task pool.Task) (*Job, error) {
+				return s.ScheduleWithFixedDelay(task, time.Second, time.Second)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var runs atomic.Int32
+			j, err := tc.schedule(pool.TaskFunc(func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			}))
+			require.NoError(t, err)
+
+			advance(t, clk, time.Second)
+			// 被丢弃的执行视为结束，不会阻塞之后的执行
+			jobIdle(t, j)
+			require.NoError(t, clk.BlockUntil(context.Background(), 1))
+
+			j.Stop()
+			<-j.Done()
+			assert.Zero(t, runs.Load())
+		})
+	}
+
+	blocker.release <- struct{}{}
+	blocker.release <- struct{}{}
+}
+
+func TestScheduler_Panic(t *testing.T) {
+	t.Parallel()
+
+	s, clk := newTestScheduler(t, 1)
+
+	var runs atomic.Int32
+	started := make(chan struct{}, 8)
+	release := make(chan struct{})
+	j, err := s.ScheduleAtFixedRate(pool.TaskFunc(func(ctx context.Context) error {
+		started <- struct{}{}
+		if runs.Add(1) == 1 {
+			<-release
+			panic("boom")
+		}
+		return nil
+	}), time.Second, time.Second, WithOverlapPolicy(OverlapQueue))
+	require.NoError(t, err)
+
+	advance(t, clk, time.Second)
+	<-started
+	// 第一次执行结束前错过两次执行时间
+	advance(t, clk, time.Second)
+	advance(t, clk, time.Second)
+	require.NoError(t, clk.BlockUntil(context.Background(), 1))
+
+	// panic 时丢弃等待执行的次数
+	close(release)
+	jobIdle(t, j)
+	assert.Equal(t, int32(1), runs.Load())
+
+	// 之后的执行不受影响
+	advance(t, clk, time.Second)
+	<-started
+	jobIdle(t, j)
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+// onceSchedule 只执行一次的执行计划。
+type onceSchedule struct {
+	at time.Time
+}
+
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	t.Parallel()
+
+	s, clk := newTestScheduler(t, 1)
+
+	var runs atomic.Int32
+	j, err := s.Schedule(onceSchedule{at: epoch.Add(time.Minute)}, pool.TaskFunc(func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	advance(t, clk, time.Minute)
+	// 执行计划不再有执行时间后定时任务停止
+	<-j.Done()
+	assert.Eventually(t, func() bool {
+		return runs.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	j2, err := s.ScheduleAtFixedRate(pool.TaskFunc(func(ctx context.Context) error {
+		return nil
+	}), time.Second, time.Second)
+	require.NoError(t, err)
+	s.Stop()
+	<-j2.Done()
+	assert.Equal(t, 0, clk.Waiters())
+}
+
+func TestOverlapPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "skip", OverlapSkip.String())
+	assert.Equal(t, "queue", OverlapQueue.String())
+	assert.Equal(t, "concurrent", OverlapConcurrent.String())
+	assert.Equal(t, "unknown", OverlapPolicy(-1).String())
+}
@@ -57,7 +57,7 @@ type DefaultManager[T any] struct {
 }
 
 func (m *DefaultManager[T]) Encrypt(data T) (string, error) {
-	now := time.Now()
+	now := m.config.now()
 	cc := &CustomClaims[T]{
 		Data: data,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -79,7 +79,7 @@ func (m *DefaultManager[T]) Decrypt(token string, opts ...jwt.ParserOption) (Cus
 		func(token *jwt.Token) (any, error) {
 			return []byte(m.decryptKey), nil
 		},
-		opts...,
+		m.config.parserOptions(opts)...,
 	)
 	if err != nil || !jwtToken.Valid {
 		return CustomClaims[T]{}, fmt.Errorf("[jit] failed to verify jwt token: %w", err)
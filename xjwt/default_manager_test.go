@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/JrMarcco/jit/clock"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -93,3 +94,30 @@ func TestDefaultManager_InvalidToken(t *testing.T) {
 	wantErr := jwt.ErrTokenMalformed
 	assert.Truef(t, errors.Is(err, wantErr), "want: %v, got: %v", wantErr, err)
 }
+
+func TestDefaultManager_Clock(t *testing.T) {
+	key := "test-key"
+
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	manager := NewDefaultManagerBuilder[defaultUser](key, key).
+		ClaimsConfig(NewClaimsConfig(WithExpiration(time.Hour), WithClock(clk))).
+		Build()
+
+	token, err := manager.Encrypt(defaultUser{Id: 1})
+	require.NoError(t, err)
+
+	clk.Advance(59 * time.Minute)
+	decrypted, err := manager.Decrypt(token)
+	require.NoError(t, err)
+	assert.Equal(t, now, decrypted.IssuedAt.Time.UTC())
+	assert.Equal(t, now.Add(time.Hour), decrypted.ExpiresAt.Time.UTC())
+
+	clk.Advance(time.Minute)
+	_, err = manager.Decrypt(token)
+	assert.Truef(t, errors.Is(err, jwt.ErrTokenExpired), "want: %v, got: %v", jwt.ErrTokenExpired, err)
+
+	// 调用者传入的 jwt.ParserOption 优先
+	_, err = manager.Decrypt(token, jwt.WithTimeFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+}
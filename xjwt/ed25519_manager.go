@@ -87,7 +87,7 @@ type Ed25519Manager[T any] struct {
 }
 
 func (m *Ed25519Manager[T]) Encrypt(data T) (string, error) {
-	now := time.Now()
+	now := m.config.now()
 	cc := &CustomClaims[T]{
 		Data: data,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -112,7 +112,7 @@ func (m *Ed25519Manager[T]) Decrypt(token string, opts ...jwt.ParserOption) (Cus
 			}
 			return m.pubKey, nil
 		},
-		opts...,
+		m.config.parserOptions(opts)...,
 	)
 	if err != nil || !jwtToken.Valid {
 		return CustomClaims[T]{}, fmt.Errorf("[jit] failed to verify jwt token: %w", err)
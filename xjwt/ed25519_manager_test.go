@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/JrMarcco/jit/clock"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -104,3 +105,24 @@ func TestEd25519Manager_InvalidToken(t *testing.T) {
 	wantErr := jwt.ErrTokenMalformed
 	assert.Truef(t, errors.Is(err, wantErr), "want: %v, got: %v", wantErr, err)
 }
+
+func TestEd25519Manager_Clock(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	manager, err := NewEd25519ManagerBuilder[ed25519User](priPem, pubPem).
+		ClaimsConfig(NewClaimsConfig(WithExpiration(time.Hour), WithClock(clk))).
+		Build()
+	require.NoError(t, err)
+
+	token, err := manager.Encrypt(ed25519User{Id: 1})
+	require.NoError(t, err)
+
+	clk.Advance(59 * time.Minute)
+	decrypted, err := manager.Decrypt(token)
+	require.NoError(t, err)
+	assert.Equal(t, ed25519User{Id: 1}, decrypted.Data)
+
+	clk.Advance(time.Minute)
+	_, err = manager.Decrypt(token)
+	assert.Truef(t, errors.Is(err, jwt.ErrTokenExpired), "want: %v, got: %v", jwt.ErrTokenExpired, err)
+}
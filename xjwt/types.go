@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/JrMarcco/jit/bean/option"
+	"github.com/JrMarcco/jit/clock"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -25,6 +26,20 @@ type ClaimsConfig struct {
 	Issuer       string        // 签发人
 	Expiration   time.Duration // 有效期
 	JtiGenerator func() string // jwt id 生成方法
+	Clock        clock.Clock   // 签发和校验 token 使用的时钟
+}
+
+// now 返回 Clock 的当前时间，未设置 Clock 时使用系统时间。
+func (cfg ClaimsConfig) now() time.Time {
+	if cfg.Clock == nil {
+		return time.Now()
+	}
+	return cfg.Clock.Now()
+}
+
+// parserOptions 在 opts 前加上使用 Clock 校验 token 有效期的选项，调用者传入的 opts 优先。
+func (cfg ClaimsConfig) parserOptions(opts []jwt.ParserOption) []jwt.ParserOption {
+	return append([]jwt.ParserOption{jwt.WithTimeFunc(cfg.now)}, opts...)
 }
 
 func WithExpiration(expiration time.Duration) option.Opt[ClaimsConfig] {
@@ -39,6 +54,13 @@ func WithIssuer(issuer string) option.Opt[ClaimsConfig] {
 	}
 }
 
+// WithClock 设置签发和校验 token 使用的时钟，默认为 clock.Real()。
+func WithClock(c clock.Clock) option.Opt[ClaimsConfig] {
+	return func(cfg *ClaimsConfig) {
+		cfg.Clock = c
+	}
+}
+
 func WithJtiGenerator(jtiGenerator func() string) option.Opt[ClaimsConfig] {
 	return func(cfg *ClaimsConfig) {
 		cfg.JtiGenerator = jtiGenerator
@@ -52,6 +74,7 @@ func NewClaimsConfig(opts ...option.Opt[ClaimsConfig]) ClaimsConfig {
 		Issuer:       "jit", // 默认签发人
 		Expiration:   defaultExpiration,
 		JtiGenerator: func() string { return "" },
+		Clock:        clock.Real(),
 	}
 
 	option.Apply(&cfg, opts...)
@@ -1,11 +1,16 @@
 package xlist
 
 import (
+	"iter"
+
 	"github.com/JrMarcco/jit/internal/errs"
 	"github.com/JrMarcco/jit/internal/slice"
 )
 
-var _ List[any] = (*ArrayList[any])(nil)
+var (
+	_ List[any]     = (*ArrayList[any])(nil)
+	_ Iterable[any] = (*ArrayList[any])(nil)
+)
 
 type ArrayList[T any] struct {
 	vals []T
@@ -60,6 +65,36 @@ func (al *ArrayList[T]) Iter(visitFunc func(idx int, val T) error) error {
 	return nil
 }
 
+func (al *ArrayList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, val := range al.vals {
+			if !yield(i, val) {
+				return
+			}
+		}
+	}
+}
+
+func (al *ArrayList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range al.vals {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+func (al *ArrayList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := len(al.vals) - 1; i >= 0; i-- {
+			if !yield(i, al.vals[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (al *ArrayList[T]) ToSlice() []T {
 	res := make([]T, len(al.vals))
 	copy(res, al.vals)
@@ -2,6 +2,7 @@ package xlist
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/JrMarcco/jit/internal/errs"
@@ -274,3 +275,54 @@ func ExampleArrayList_Iter() {
 	// 3: 8
 	// 4: 10
 }
+
+// assertListSeq checks the iterators of the list against the expected values.
+func assertListSeq(t *testing.T, l Iterable[int], want []int) {
+	t.Helper()
+
+	idxs, vals := make([]int, 0), make([]int, 0)
+	for idx, val := range l.All() {
+		idxs = append(idxs, idx)
+		vals = append(vals, val)
+	}
+	assert.Equal(t, want, vals)
+	for i, idx := range idxs {
+		assert.Equal(t, i, idx)
+	}
+
+	assert.Equal(t, want, slices.AppendSeq([]int{}, l.Values()))
+
+	backward := make([]int, 0)
+	for idx, val := range l.Backward() {
+		assert.Equal(t, len(want)-1-len(backward), idx)
+		backward = append(backward, val)
+	}
+	reversed := slices.Clone(want)
+	slices.Reverse(reversed)
+	assert.Equal(t, reversed, backward)
+
+	// break in the loop body
+	for _, val := range l.All() {
+		if len(want) > 0 {
+			assert.Equal(t, want[0], val)
+		}
+		break
+	}
+}
+
+func TestArrayList_Seq(t *testing.T) {
+	tcs := []struct {
+		name string
+		vals []int
+	}{
+		{name: "empty", vals: []int{}},
+		{name: "single", vals: []int{1}},
+		{name: "multiple", vals: []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assertListSeq(t, ArrayListOf(tc.vals), tc.vals)
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package xlist
 
-import "sync"
+import (
+	"iter"
+	"sync"
+)
 
-var _ List[any] = (*ConcurrentList[any])(nil)
+var (
+	_ List[any]     = (*ConcurrentList[any])(nil)
+	_ Iterable[any] = (*ConcurrentList[any])(nil)
+)
 
 type ConcurrentList[T any] struct {
 	List[T]
@@ -51,6 +57,48 @@ func (cl *ConcurrentList[T]) Iter(visitFunc func(idx int, val T) error) error {
 	return cl.List.Iter(visitFunc)
 }
 
+// iterable returns the wrapped list if it is Iterable,
+// otherwise a snapshot of it taken by ToSlice. The caller must hold the read lock.
+func (cl *ConcurrentList[T]) iterable() Iterable[T] {
+	if it, ok := cl.List.(Iterable[T]); ok {
+		return it
+	}
+	return &ArrayList[T]{vals: cl.List.ToSlice()}
+}
+
+// All holds the read lock during the whole iteration,
+// so the loop body must not modify the list.
+func (cl *ConcurrentList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		cl.mu.RLock()
+		defer cl.mu.RUnlock()
+
+		cl.iterable().All()(yield)
+	}
+}
+
+// Values holds the read lock during the whole iteration,
+// so the loop body must not modify the list.
+func (cl *ConcurrentList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		cl.mu.RLock()
+		defer cl.mu.RUnlock()
+
+		cl.iterable().Values()(yield)
+	}
+}
+
+// Backward holds the read lock during the whole iteration,
+// so the loop body must not modify the list.
+func (cl *ConcurrentList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		cl.mu.RLock()
+		defer cl.mu.RUnlock()
+
+		cl.iterable().Backward()(yield)
+	}
+}
+
 func (cl *ConcurrentList[T]) ToSlice() []T {
 	cl.mu.RLock()
 	defer cl.mu.RUnlock()
@@ -280,3 +280,27 @@ func ExampleConcurrentList_Append() {
 	// 3: 8
 	// 4: 10
 }
+
+func TestConcurrentList_Seq(t *testing.T) {
+	tcs := []struct {
+		name string
+		vals []int
+	}{
+		{name: "empty", vals: []int{}},
+		{name: "single", vals: []int{1}},
+		{name: "multiple", vals: []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assertListSeq(t, concurrentListOf(tc.vals), tc.vals)
+			// the wrapped list without iterators is iterated by a snapshot
+			assertListSeq(t, &ConcurrentList[int]{List: plainList[int]{ArrayListOf(tc.vals)}}, tc.vals)
+		})
+	}
+}
+
+// plainList hides the iterators of the wrapped list, like a List implemented outside this package.
+type plainList[T any] struct {
+	List[T]
+}
@@ -1,13 +1,17 @@
 package xlist
 
 import (
+	"iter"
 	"sync"
 
 	"github.com/JrMarcco/jit/internal/errs"
 	"github.com/JrMarcco/jit/internal/slice"
 )
 
-var _ List[any] = (*CowArrayList[any])(nil)
+var (
+	_ List[any]     = (*CowArrayList[any])(nil)
+	_ Iterable[any] = (*CowArrayList[any])(nil)
+)
 
 // CowArrayList a copy-on-write array list implementation base on slice.
 // Lock on writing and no lock on reading.
@@ -104,6 +108,40 @@ func (cal *CowArrayList[T]) Iter(visitFunc func(idx int, val T) error) error {
 	return nil
 }
 
+// All iterates over the snapshot of the list at the time the iteration starts.
+func (cal *CowArrayList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, val := range cal.vals {
+			if !yield(i, val) {
+				return
+			}
+		}
+	}
+}
+
+// Values iterates over the snapshot of the list at the time the iteration starts.
+func (cal *CowArrayList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range cal.vals {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward iterates over the snapshot of the list at the time the iteration starts.
+func (cal *CowArrayList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		vals := cal.vals
+		for i := len(vals) - 1; i >= 0; i-- {
+			if !yield(i, vals[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (cal *CowArrayList[T]) ToSlice() []T {
 	res := make([]T, len(cal.vals))
 	copy(res, cal.vals)
@@ -274,3 +274,32 @@ func ExampleCowArrayList_Iter() {
 	// 3: 8
 	// 4: 10
 }
+
+func TestCowArrayList_Seq(t *testing.T) {
+	tcs := []struct {
+		name string
+		vals []int
+	}{
+		{name: "empty", vals: []int{}},
+		{name: "single", vals: []int{1}},
+		{name: "multiple", vals: []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assertListSeq(t, CowArrayListOf(tc.vals), tc.vals)
+		})
+	}
+
+	t.Run("iterate over snapshot", func(t *testing.T) {
+		cal := CowArrayListOf([]int{1, 2, 3})
+
+		vals := make([]int, 0)
+		for _, val := range cal.All() {
+			vals = append(vals, val)
+			_ = cal.Append(val)
+		}
+		assert.Equal(t, []int{1, 2, 3}, vals)
+		assert.Equal(t, []int{1, 2, 3, 1, 2, 3}, cal.ToSlice())
+	})
+}
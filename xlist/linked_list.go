@@ -1,8 +1,15 @@
 package xlist
 
-import "github.com/JrMarcco/jit/internal/errs"
+import (
+	"iter"
 
-var _ List[any] = (*LinkedList[any])(nil)
+	"github.com/JrMarcco/jit/internal/errs"
+)
+
+var (
+	_ List[any]     = (*LinkedList[any])(nil)
+	_ Iterable[any] = (*LinkedList[any])(nil)
+)
 
 type LinkedList[T any] struct {
 	head *linkedListNode[T]
@@ -105,6 +112,36 @@ func (ll *LinkedList[T]) Iter(visitFunc func(idx int, val T) error) error {
 	return nil
 }
 
+func (ll *LinkedList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for currN, index := ll.head.next, 0; currN != ll.tail; currN, index = currN.next, index+1 {
+			if !yield(index, currN.val) {
+				return
+			}
+		}
+	}
+}
+
+func (ll *LinkedList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for currN := ll.head.next; currN != ll.tail; currN = currN.next {
+			if !yield(currN.val) {
+				return
+			}
+		}
+	}
+}
+
+func (ll *LinkedList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for currN, index := ll.tail.prev, ll.size-1; currN != ll.head; currN, index = currN.prev, index-1 {
+			if !yield(index, currN.val) {
+				return
+			}
+		}
+	}
+}
+
 func (ll *LinkedList[T]) ToSlice() []T {
 	res := make([]T, ll.size)
 	for currN, index := ll.head.next, 0; currN != ll.tail; currN, index = currN.next, index+1 {
@@ -254,3 +254,20 @@ func ExampleLinkedList_Iter() {
 	// 4: 16
 	// 5: 25
 }
+
+func TestLinkedList_Seq(t *testing.T) {
+	tcs := []struct {
+		name string
+		vals []int
+	}{
+		{name: "empty", vals: []int{}},
+		{name: "single", vals: []int{1}},
+		{name: "multiple", vals: []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assertListSeq(t, LinkedListOf(tc.vals), tc.vals)
+		})
+	}
+}
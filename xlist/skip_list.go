@@ -1,10 +1,14 @@
 package xlist
 
 import (
+	"iter"
+
 	"github.com/JrMarcco/jit"
 	"github.com/JrMarcco/jit/internal/list"
 )
 
+var _ Iterable[any] = (*SkipList[any])(nil)
+
 type SkipList[T any] struct {
 	skipList *list.SkipList[T]
 }
@@ -33,6 +37,35 @@ func (sl *SkipList[T]) Len() int {
 	return sl.skipList.Len()
 }
 
+// All returns an iterator over index-value pairs in ascending order.
+func (sl *SkipList[T]) All() iter.Seq2[int, T] {
+	return sl.skipList.All()
+}
+
+// Values returns an iterator over values in ascending order.
+func (sl *SkipList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, val := range sl.skipList.All() {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over index-value pairs in descending order.
+// The skip list only links forward, so it iterates over a snapshot of the list.
+func (sl *SkipList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		vals := sl.skipList.ToSlice()
+		for i := len(vals) - 1; i >= 0; i-- {
+			if !yield(i, vals[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (sl *SkipList[T]) ToSlice() []T {
 	return sl.skipList.ToSlice()
 }
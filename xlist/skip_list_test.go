@@ -1,6 +1,7 @@
 package xlist
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/JrMarcco/jit"
@@ -339,3 +340,41 @@ func TestSkipList_Peek(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipList_Seq(t *testing.T) {
+	tcs := []struct {
+		name string
+		vals []int
+		want []int
+	}{
+		{name: "empty", vals: []int{}, want: []int{}},
+		{name: "single", vals: []int{1}, want: []int{1}},
+		{name: "disorder", vals: []int{3, 1, 4, 2}, want: []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sl := NewSkipList[int](testCmp)
+			for _, val := range tc.vals {
+				sl.Insert(val)
+			}
+
+			vals := make([]int, 0)
+			for idx, val := range sl.All() {
+				assert.Equal(t, len(vals), idx)
+				vals = append(vals, val)
+			}
+			assert.Equal(t, tc.want, vals)
+			assert.Equal(t, tc.want, slices.AppendSeq([]int{}, sl.Values()))
+
+			backward := make([]int, 0)
+			for idx, val := range sl.Backward() {
+				assert.Equal(t, len(tc.want)-1-len(backward), idx)
+				backward = append(backward, val)
+			}
+			reversed := slices.Clone(tc.want)
+			slices.Reverse(reversed)
+			assert.Equal(t, reversed, backward)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package xlist
 
+import "iter"
+
 type List[T any] interface {
 	Insert(index int, val T) error
 	Append(vals ...T) error
@@ -11,3 +13,14 @@ type List[T any] interface {
 	Cap() int
 	Len() int
 }
+
+// Iterable provides range-over-func iterators, it is implemented by all the lists in this package.
+// It is separate from List so that the implementations of List outside this package are not broken.
+type Iterable[T any] interface {
+	// All returns an iterator over index-value pairs in index order.
+	All() iter.Seq2[int, T]
+	// Values returns an iterator over values in index order.
+	Values() iter.Seq[T]
+	// Backward returns an iterator over index-value pairs in reverse index order.
+	Backward() iter.Seq2[int, T]
+}
@@ -0,0 +1,258 @@
+package xmap
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"sync"
+)
+
+const defaultShardCnt = 32
+
+var _ imap[any, any] = (*ConcurrentMap[any, any])(nil)
+
+// ConcurrentMap is a lock-striped concurrent map.
+// Keys are distributed to shards by hash, each shard is guarded by its own lock,
+// so the operations on different shards do not block each other.
+type ConcurrentMap[K any, V any] struct {
+	shards []*shard[K, V]
+	shift  uint8 // 64 - log2(len(shards)), used to pick the shard by the high bits of the hash
+	hash   func(key K) uint64
+}
+
+type shard[K any, V any] struct {
+	mu sync.RWMutex
+	m  imap[K, V]
+	// number of key-value pairs in the shard.
+	// HashMap.Size() counts the hash buckets, so the shard counts the pairs by itself.
+	size int64
+}
+
+// put stores the key-value pair, the caller must hold the write lock.
+func (s *shard[K, V]) put(key K, val V) {
+	if _, ok := s.m.Get(key); !ok {
+		s.size++
+	}
+	// the underlying maps of shard never return error on Put
+	_ = s.m.Put(key, val)
+}
+
+// del deletes the key, the caller must hold the write lock.
+func (s *shard[K, V]) del(key K) (V, bool) {
+	val, ok := s.m.Del(key)
+	if ok {
+		s.size--
+	}
+	return val, ok
+}
+
+func (cm *ConcurrentMap[K, V]) shardOf(key K) *shard[K, V] {
+	if len(cm.shards) == 1 {
+		return cm.shards[0]
+	}
+
+	// fibonacci hashing, spread the hash which has low entropy ( e.g. Hashable.Hash() returns small ids ).
+	const golden = 0x9E3779B97F4A7C15
+	return cm.shards[(cm.hash(key)*golden)>>cm.shift]
+}
+
+func (cm *ConcurrentMap[K, V]) Size() int64 {
+	var size int64
+	for _, s := range cm.shards {
+		s.mu.RLock()
+		size += s.size
+		s.mu.RUnlock()
+	}
+	return size
+}
+
+func (cm *ConcurrentMap[K, V]) Keys() []K {
+	res := make([]K, 0)
+	for _, s := range cm.shards {
+		s.mu.RLock()
+		res = append(res, s.m.Keys()...)
+		s.mu.RUnlock()
+	}
+	return res
+}
+
+func (cm *ConcurrentMap[K, V]) Vals() []V {
+	res := make([]V, 0)
+	for _, s := range cm.shards {
+		s.mu.RLock()
+		res = append(res, s.m.Vals()...)
+		s.mu.RUnlock()
+	}
+	return res
+}
+
+func (cm *ConcurrentMap[K, V]) Put(key K, val V) error {
+	s := cm.shardOf(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(key, val)
+	return nil
+}
+
+func (cm *ConcurrentMap[K, V]) Del(key K) (V, bool) {
+	s := cm.shardOf(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.del(key)
+}
+
+func (cm *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	s := cm.shardOf(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Get(key)
+}
+
+// Iter traversal the key-value pairs shard by shard.
+//
+// Each shard is copied under its read lock before visiting,
+// so visitFunc is free to access the map, but the traversal is not a consistent snapshot of the whole map.
+func (cm *ConcurrentMap[K, V]) Iter(visitFunc func(key K, val V) bool) {
+	for _, s := range cm.shards {
+		s.mu.RLock()
+		keys := make([]K, 0, s.size)
+		vals := make([]V, 0, s.size)
+		s.m.Iter(func(key K, val V) bool {
+			keys = append(keys, key)
+			vals = append(vals, val)
+			return true
+		})
+		s.mu.RUnlock()
+
+		for i := range keys {
+			if !visitFunc(keys[i], vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over key-value pairs, see Iter for the consistency guarantee.
+func (cm *ConcurrentMap[K, V]) All() iter.Seq2[K, V] {
+	return cm.Iter
+}
+
+// Compute atomically computes a new value for the key.
+// computeFunc receives the current value and whether the key exists,
+// it returns the new value and whether to keep the key in the map ( false deletes the key ).
+//
+// Compute returns the new value and whether the key exists after computing.
+// computeFunc is called under the shard lock, it must not access the map.
+func (cm *ConcurrentMap[K, V]) Compute(key K, computeFunc func(oldVal V, exist bool) (newVal V, keep bool)) (V, bool) {
+	s := cm.shardOf(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldVal, exist := s.m.Get(key)
+	newVal, keep := computeFunc(oldVal, exist)
+	if !keep {
+		if exist {
+			_, _ = s.del(key)
+		}
+		var zero V
+		return zero, false
+	}
+
+	s.put(key, newVal)
+	return newVal, true
+}
+
+// ComputeIfAbsent returns the existing value of the key if present.
+// Otherwise, it atomically computes the value with computeFunc and stores it.
+// The loaded result is true if the value was loaded, false if computed.
+//
+// computeFunc is called under the shard lock, it must not access the map.
+func (cm *ConcurrentMap[K, V]) ComputeIfAbsent(key K, computeFunc func(key K) V) (actual V, loaded bool) {
+	s := cm.shardOf(key)
+
+	// fast path, most of the time the key is present
+	s.mu.RLock()
+	if val, ok := s.m.Get(key); ok {
+		s.mu.RUnlock()
+		return val, true
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// double-check, the key may be stored by other goroutine before locked
+	if val, ok := s.m.Get(key); ok {
+		return val, true
+	}
+
+	val := computeFunc(key)
+	s.put(key, val)
+	return val, false
+}
+
+// Merge atomically stores val if the key is absent,
+// otherwise stores the result of mergeFunc(oldVal, val).
+// It returns the value stored in the map.
+//
+// mergeFunc is called under the shard lock, it must not access the map.
+func (cm *ConcurrentMap[K, V]) Merge(key K, val V, mergeFunc func(oldVal, val V) V) V {
+	s := cm.shardOf(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldVal, ok := s.m.Get(key)
+	if !ok {
+		s.size++
+	} else {
+		val = mergeFunc(oldVal, val)
+	}
+
+	_ = s.m.Put(key, val)
+	return val
+}
+
+func newConcurrentMap[K any, V any](shardCnt int, hash func(key K) uint64, newShardMap func() imap[K, V]) *ConcurrentMap[K, V] {
+	if shardCnt <= 0 {
+		shardCnt = defaultShardCnt
+	}
+
+	// round up shard count to power of 2
+	shardBits := bits.Len(uint(shardCnt - 1))
+	shards := make([]*shard[K, V], 1<<shardBits)
+	for i := range shards {
+		shards[i] = &shard[K, V]{m: newShardMap()}
+	}
+
+	return &ConcurrentMap[K, V]{
+		shards: shards,
+		shift:  uint8(64 - shardBits),
+		hash:   hash,
+	}
+}
+
+// NewConcurrentMap creates a concurrent map for comparable keys.
+// shardCnt is rounded up to power of 2, default shard count is used if shardCnt <= 0.
+func NewConcurrentMap[K comparable, V any](shardCnt int) *ConcurrentMap[K, V] {
+	seed := maphash.MakeSeed()
+	return newConcurrentMap(
+		shardCnt,
+		func(key K) uint64 { return maphash.Comparable(seed, key) },
+		func() imap[K, V] { return newBuiltInMap(make(map[K]V)) },
+	)
+}
+
+// NewConcurrentHashMap creates a concurrent map for Hashable keys, each shard is a HashMap.
+// shardCnt is rounded up to power of 2, default shard count is used if shardCnt <= 0.
+func NewConcurrentHashMap[K Hashable, V any](shardCnt int) *ConcurrentMap[K, V] {
+	return newConcurrentMap(
+		shardCnt,
+		func(key K) uint64 { return key.Hash() },
+		func() imap[K, V] { return NewHashMap[K, V](0) },
+	)
+}
@@ -0,0 +1,210 @@
+package xmap
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConcurrentMap(t *testing.T) {
+	tcs := []struct {
+		name         string
+		shardCnt     int
+		wantShardCnt int
+	}{
+		{name: "default shard count", shardCnt: 0, wantShardCnt: defaultShardCnt},
+		{name: "negative shard count", shardCnt: -1, wantShardCnt: defaultShardCnt},
+		{name: "single shard", shardCnt: 1, wantShardCnt: 1},
+		{name: "power of 2", shardCnt: 16, wantShardCnt: 16},
+		{name: "round up to power of 2", shardCnt: 17, wantShardCnt: 32},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := NewConcurrentMap[int, int](tc.shardCnt)
+			assert.Equal(t, tc.wantShardCnt, len(cm.shards))
+
+			for i := range 100 {
+				require.NoError(t, cm.Put(i, i))
+			}
+			assert.Equal(t, int64(100), cm.Size())
+		})
+	}
+}
+
+func TestConcurrentMap_Basic(t *testing.T) {
+	tcs := []struct {
+		name string
+		cm   imap[testKey, string]
+	}{
+		{name: "comparable key", cm: NewConcurrentMap[testKey, string](4)},
+		{name: "hashable key", cm: NewConcurrentHashMap[testKey, string](4)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			want := map[testKey]string{}
+			for i := range uint64(20) {
+				key := testKey{id: i}
+				want[key] = "v"
+				require.NoError(t, tc.cm.Put(key, "v"))
+			}
+
+			// overwrite
+			require.NoError(t, tc.cm.Put(testKey{id: 1}, "v1"))
+			want[testKey{id: 1}] = "v1"
+
+			val, ok := tc.cm.Get(testKey{id: 1})
+			assert.True(t, ok)
+			assert.Equal(t, "v1", val)
+
+			val, ok = tc.cm.Del(testKey{id: 2})
+			assert.True(t, ok)
+			assert.Equal(t, "v", val)
+			delete(want, testKey{id: 2})
+
+			_, ok = tc.cm.Get(testKey{id: 2})
+			assert.False(t, ok)
+			_, ok = tc.cm.Del(testKey{id: 100})
+			assert.False(t, ok)
+
+			assert.Equal(t, int64(len(want)), tc.cm.Size())
+			assert.ElementsMatch(t, Keys(want), tc.cm.Keys())
+			assert.ElementsMatch(t, Vals(want), tc.cm.Vals())
+
+			got := map[testKey]string{}
+			tc.cm.Iter(func(key testKey, val string) bool {
+				got[key] = val
+				return true
+			})
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestConcurrentMap_Compute(t *testing.T) {
+	cm := NewConcurrentMap[string, int](4)
+
+	// compute absent key
+	val, ok := cm.Compute("a", func(oldVal int, exist bool) (int, bool) {
+		assert.False(t, exist)
+		return oldVal + 1, true
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+
+	// compute present key
+	val, ok = cm.Compute("a", func(oldVal int, exist bool) (int, bool) {
+		assert.True(t, exist)
+		return oldVal + 1, true
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	// delete present key
+	_, ok = cm.Compute("a", func(oldVal int, exist bool) (int, bool) {
+		return 0, false
+	})
+	assert.False(t, ok)
+	_, ok = cm.Get("a")
+	assert.False(t, ok)
+
+	// do not store absent key
+	_, ok = cm.Compute("b", func(oldVal int, exist bool) (int, bool) {
+		return 0, false
+	})
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), cm.Size())
+}
+
+func TestConcurrentMap_ComputeIfAbsent(t *testing.T) {
+	cm := NewConcurrentMap[string, int](4)
+
+	val, loaded := cm.ComputeIfAbsent("a", func(key string) int { return len(key) })
+	assert.False(t, loaded)
+	assert.Equal(t, 1, val)
+
+	val, loaded = cm.ComputeIfAbsent("a", func(key string) int {
+		t.Fatal("should not compute present key")
+		return 0
+	})
+	assert.True(t, loaded)
+	assert.Equal(t, 1, val)
+}
+
+func TestConcurrentMap_Merge(t *testing.T) {
+	cm := NewConcurrentHashMap[testKey, []string](4)
+	appendFunc := func(oldVal, val []string) []string { return append(oldVal, val...) }
+
+	assert.Equal(t, []string{"a"}, cm.Merge(testKey{id: 1}, []string{"a"}, appendFunc))
+	assert.Equal(t, []string{"a", "b"}, cm.Merge(testKey{id: 1}, []string{"b"}, appendFunc))
+	assert.Equal(t, []string{"c"}, cm.Merge(testKey{id: 11}, []string{"c"}, appendFunc))
+
+	assert.Equal(t, map[testKey][]string{
+		{id: 1}:  {"a", "b"},
+		{id: 11}: {"c"},
+	}, maps.Collect(cm.All()))
+}
+
+func TestConcurrentMap_Parallel(t *testing.T) {
+	const (
+		goroutineCnt = 64
+		loopCnt      = 1000
+		keyCnt       = 128
+	)
+
+	tcs := []struct {
+		name string
+		cm   *ConcurrentMap[testKey, int]
+	}{
+		{name: "comparable key", cm: NewConcurrentMap[testKey, int](8)},
+		{name: "hashable key", cm: NewConcurrentHashMap[testKey, int](8)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var computedCnt atomic.Int64
+
+			var wg sync.WaitGroup
+			for g := range goroutineCnt {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := range loopCnt {
+						key := testKey{id: uint64((g + i) % keyCnt)}
+
+						tc.cm.Merge(key, 1, func(oldVal, val int) int { return oldVal + val })
+						tc.cm.ComputeIfAbsent(testKey{id: uint64(keyCnt + i%keyCnt)}, func(testKey) int {
+							computedCnt.Add(1)
+							return 0
+						})
+						_, _ = tc.cm.Get(key)
+
+						if i%100 == 0 {
+							tc.cm.Iter(func(testKey, int) bool { return true })
+							_ = tc.cm.Size()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// every key is computed only once
+			assert.Equal(t, int64(keyCnt), computedCnt.Load())
+
+			total := 0
+			for i := range uint64(keyCnt) {
+				val, ok := tc.cm.Get(testKey{id: i})
+				assert.True(t, ok)
+				total += val
+			}
+			assert.Equal(t, goroutineCnt*loopCnt, total)
+			assert.Equal(t, int64(2*keyCnt), tc.cm.Size())
+		})
+	}
+}
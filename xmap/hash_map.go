@@ -1,6 +1,8 @@
 package xmap
 
 import (
+	"iter"
+
 	"github.com/JrMarcco/jit/xsync"
 )
 
@@ -156,6 +158,29 @@ func (h *HashMap[K, V]) Iter(visitFunc func(key K, val V) bool) {
 	}
 }
 
+// All returns an iterator over key-value pairs in no particular order.
+func (h *HashMap[K, V]) All() iter.Seq2[K, V] {
+	return h.Iter
+}
+
+// KeysSeq returns an iterator over keys in no particular order.
+func (h *HashMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		h.Iter(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// ValsSeq returns an iterator over values in no particular order.
+func (h *HashMap[K, V]) ValsSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		h.Iter(func(_ K, val V) bool {
+			return yield(val)
+		})
+	}
+}
+
 func NewHashMap[K Hashable, V any](size int) *HashMap[K, V] {
 	return &HashMap[K, V]{
 		m:    make(map[uint64]*node[K, V], size),
@@ -1,6 +1,8 @@
 package xmap
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -297,3 +299,22 @@ func TestHashMap_Kvs(t *testing.T) {
 		})
 	}
 }
+
+func TestHashMap_Seq(t *testing.T) {
+	hm := NewHashMap[testKey, string](8)
+	want := map[testKey]string{{id: 1}: "1", {id: 2}: "2", {id: 11}: "11"}
+	for k, v := range want {
+		assert.NoError(t, hm.Put(k, v))
+	}
+
+	assert.Equal(t, want, maps.Collect(hm.All()))
+	assert.ElementsMatch(t, []testKey{{id: 1}, {id: 2}, {id: 11}}, slices.Collect(hm.KeysSeq()))
+	assert.ElementsMatch(t, []string{"1", "2", "11"}, slices.Collect(hm.ValsSeq()))
+
+	cnt := 0
+	for range hm.All() {
+		cnt++
+		break
+	}
+	assert.Equal(t, 1, cnt)
+}
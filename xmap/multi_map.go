@@ -1,6 +1,10 @@
 package xmap
 
-import "github.com/JrMarcco/jit"
+import (
+	"iter"
+
+	"github.com/JrMarcco/jit"
+)
 
 type MultiMap[K any, V any] struct {
 	m imap[K, []V]
@@ -59,6 +63,30 @@ func (m *MultiMap[K, V]) Iter(visitFunc func(key K, val V) bool) {
 	})
 }
 
+// All returns an iterator over every key-value pair,
+// a key with multiple values is yielded once per value.
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return m.Iter
+}
+
+// KeysSeq returns an iterator over keys.
+func (m *MultiMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.m.Iter(func(key K, _ []V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// ValsSeq returns an iterator over the copy of values of each key.
+func (m *MultiMap[K, V]) ValsSeq() iter.Seq[[]V] {
+	return func(yield func([]V) bool) {
+		m.m.Iter(func(_ K, vals []V) bool {
+			return yield(append([]V{}, vals...))
+		})
+	}
+}
+
 func NewMultiTreeMap[K comparable, V any](cmp jit.Comparator[K]) (*MultiMap[K, V], error) {
 	treeMap, err := NewTreeMap[K, []V](cmp)
 	if err != nil {
@@ -1 +1,36 @@
 package xmap
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiMap_Seq(t *testing.T) {
+	mm, err := NewMultiTreeMap[int, string](cmp())
+	require.NoError(t, err)
+
+	require.NoError(t, mm.PuyMany(2, "2a", "2b"))
+	require.NoError(t, mm.Put(1, "1a"))
+
+	keys, vals := make([]int, 0), make([]string, 0)
+	for key, val := range mm.All() {
+		keys = append(keys, key)
+		vals = append(vals, val)
+	}
+	assert.Equal(t, []int{1, 2, 2}, keys)
+	assert.Equal(t, []string{"1a", "2a", "2b"}, vals)
+
+	assert.Equal(t, []int{1, 2}, slices.Collect(mm.KeysSeq()))
+
+	valsSeq := slices.Collect(mm.ValsSeq())
+	assert.Equal(t, [][]string{{"1a"}, {"2a", "2b"}}, valsSeq)
+
+	// modify the yielded values should not affect the map
+	valsSeq[0][0] = "changed"
+	got, ok := mm.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1a"}, got)
+}
@@ -2,6 +2,7 @@ package xmap
 
 import (
 	"errors"
+	"iter"
 
 	"github.com/JrMarcco/jit"
 
@@ -51,6 +52,114 @@ func (tm *TreeMap[K, V]) Iter(visitFunc func(key K, val V) bool) {
 	tm.tree.Iter(visitFunc)
 }
 
+// All returns an iterator over key-value pairs in ascending key order.
+func (tm *TreeMap[K, V]) All() iter.Seq2[K, V] {
+	return tm.tree.Iter
+}
+
+// KeysSeq returns an iterator over keys in ascending order.
+func (tm *TreeMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		tm.tree.Iter(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// ValsSeq returns an iterator over values in ascending key order.
+func (tm *TreeMap[K, V]) ValsSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		tm.tree.Iter(func(_ K, val V) bool {
+			return yield(val)
+		})
+	}
+}
+
+// Backward returns an iterator over key-value pairs in descending key order.
+func (tm *TreeMap[K, V]) Backward() iter.Seq2[K, V] {
+	return tm.tree.IterDesc
+}
+
+// IterDesc traversal all the key-value pairs in descending key order.
+func (tm *TreeMap[K, V]) IterDesc(visitFunc func(key K, val V) bool) {
+	tm.tree.IterDesc(visitFunc)
+}
+
+// IterRange traversal the key-value pairs whose key is in [from, to) in ascending key order.
+func (tm *TreeMap[K, V]) IterRange(from K, to K, visitFunc func(key K, val V) bool) {
+	tm.tree.IterRange(from, to, visitFunc)
+}
+
+// IterRangeDesc traversal the key-value pairs whose key is in [from, to) in descending key order.
+func (tm *TreeMap[K, V]) IterRangeDesc(from K, to K, visitFunc func(key K, val V) bool) {
+	tm.tree.IterRangeDesc(from, to, visitFunc)
+}
+
+// Floor returns the greatest key less than or equal to the given key.
+func (tm *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	k, v, err := tm.tree.Floor(key)
+	return k, v, err == nil
+}
+
+// Ceiling returns the least key greater than or equal to the given key.
+func (tm *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	k, v, err := tm.tree.Ceiling(key)
+	return k, v, err == nil
+}
+
+// Lower returns the greatest key strictly less than the given key.
+func (tm *TreeMap[K, V]) Lower(key K) (K, V, bool) {
+	k, v, err := tm.tree.Lower(key)
+	return k, v, err == nil
+}
+
+// Higher returns the least key strictly greater than the given key.
+func (tm *TreeMap[K, V]) Higher(key K) (K, V, bool) {
+	k, v, err := tm.tree.Higher(key)
+	return k, v, err == nil
+}
+
+// First returns the least key of the map.
+func (tm *TreeMap[K, V]) First() (K, V, bool) {
+	k, v, err := tm.tree.First()
+	return k, v, err == nil
+}
+
+// Last returns the greatest key of the map.
+func (tm *TreeMap[K, V]) Last() (K, V, bool) {
+	k, v, err := tm.tree.Last()
+	return k, v, err == nil
+}
+
+// PollFirst removes and returns the least key of the map.
+func (tm *TreeMap[K, V]) PollFirst() (K, V, bool) {
+	k, v, err := tm.tree.PollFirst()
+	return k, v, err == nil
+}
+
+// PollLast removes and returns the greatest key of the map.
+func (tm *TreeMap[K, V]) PollLast() (K, V, bool) {
+	k, v, err := tm.tree.PollLast()
+	return k, v, err == nil
+}
+
+// Rank returns the number of keys strictly less than the given key.
+// If the key exists, the rank is its 0-based index in ascending order.
+func (tm *TreeMap[K, V]) Rank(key K) int64 {
+	return tm.tree.Rank(key)
+}
+
+// Select returns the key-value pair at the given 0-based index in ascending order.
+func (tm *TreeMap[K, V]) Select(index int64) (K, V, bool) {
+	k, v, err := tm.tree.Select(index)
+	return k, v, err == nil
+}
+
+// CountRange returns the number of keys in [lo, hi).
+func (tm *TreeMap[K, V]) CountRange(lo K, hi K) int64 {
+	return tm.tree.CountRange(lo, hi)
+}
+
 func (tm *TreeMap[K, V]) KeyVals() ([]K, []V) {
 	return tm.tree.Kvs()
 }
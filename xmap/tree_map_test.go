@@ -1,6 +1,8 @@
 package xmap
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/JrMarcco/jit"
@@ -207,3 +209,171 @@ func TestTreeMap_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestTreeMap_Navigation(t *testing.T) {
+	m := map[int]string{10: "10", 20: "20", 30: "30", 40: "40"}
+
+	tcs := []struct {
+		name    string
+		navFunc func(tm *TreeMap[int, string]) (int, string, bool)
+		wantKey int
+		wantVal string
+		wantRes bool
+	}{
+		{
+			name:    "floor",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Floor(25) },
+			wantKey: 20,
+			wantVal: "20",
+			wantRes: true,
+		}, {
+			name:    "floor non-existent",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Floor(5) },
+			wantRes: false,
+		}, {
+			name:    "ceiling",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Ceiling(25) },
+			wantKey: 30,
+			wantVal: "30",
+			wantRes: true,
+		}, {
+			name:    "ceiling non-existent",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Ceiling(45) },
+			wantRes: false,
+		}, {
+			name:    "lower",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Lower(20) },
+			wantKey: 10,
+			wantVal: "10",
+			wantRes: true,
+		}, {
+			name:    "higher",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Higher(20) },
+			wantKey: 30,
+			wantVal: "30",
+			wantRes: true,
+		}, {
+			name:    "first",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.First() },
+			wantKey: 10,
+			wantVal: "10",
+			wantRes: true,
+		}, {
+			name:    "last",
+			navFunc: func(tm *TreeMap[int, string]) (int, string, bool) { return tm.Last() },
+			wantKey: 40,
+			wantVal: "40",
+			wantRes: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			treeMap, err := NewTreeMapWithMap(cmp(), m)
+			require.NoError(t, err)
+
+			key, val, ok := tc.navFunc(treeMap)
+			assert.Equal(t, tc.wantRes, ok)
+			assert.Equal(t, tc.wantKey, key)
+			assert.Equal(t, tc.wantVal, val)
+		})
+	}
+}
+
+func TestTreeMap_Poll(t *testing.T) {
+	treeMap, err := NewTreeMapWithMap(cmp(), map[int]string{1: "1", 2: "2", 3: "3"})
+	require.NoError(t, err)
+
+	key, val, ok := treeMap.PollFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "1", val)
+
+	key, val, ok = treeMap.PollLast()
+	assert.True(t, ok)
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "3", val)
+
+	assert.Equal(t, int64(1), treeMap.Size())
+	assert.Equal(t, []int{2}, treeMap.Keys())
+
+	_, _, ok = treeMap.PollFirst()
+	assert.True(t, ok)
+	_, _, ok = treeMap.PollLast()
+	assert.False(t, ok)
+}
+
+func TestTreeMap_IterRange(t *testing.T) {
+	treeMap, err := NewTreeMapWithMap(cmp(), map[int]string{1: "1", 2: "2", 3: "3", 4: "4", 5: "5"})
+	require.NoError(t, err)
+
+	vals := make([]string, 0)
+	treeMap.IterRange(2, 5, func(_ int, val string) bool {
+		vals = append(vals, val)
+		return true
+	})
+	assert.Equal(t, []string{"2", "3", "4"}, vals)
+
+	vals = vals[:0]
+	treeMap.IterRangeDesc(2, 5, func(_ int, val string) bool {
+		vals = append(vals, val)
+		return true
+	})
+	assert.Equal(t, []string{"4", "3", "2"}, vals)
+
+	vals = vals[:0]
+	treeMap.IterDesc(func(_ int, val string) bool {
+		vals = append(vals, val)
+		return len(vals) < 2
+	})
+	assert.Equal(t, []string{"5", "4"}, vals)
+}
+
+func TestTreeMap_OrderStatistic(t *testing.T) {
+	treeMap, err := NewTreeMapWithMap(cmp(), map[int]string{10: "10", 20: "20", 30: "30", 40: "40"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(0), treeMap.Rank(10))
+	assert.Equal(t, int64(2), treeMap.Rank(30))
+	assert.Equal(t, int64(2), treeMap.Rank(25))
+	assert.Equal(t, int64(4), treeMap.Rank(50))
+
+	key, val, ok := treeMap.Select(1)
+	assert.True(t, ok)
+	assert.Equal(t, 20, key)
+	assert.Equal(t, "20", val)
+
+	_, _, ok = treeMap.Select(4)
+	assert.False(t, ok)
+
+	assert.Equal(t, int64(2), treeMap.CountRange(15, 35))
+
+	_, _ = treeMap.Del(20)
+	assert.Equal(t, int64(1), treeMap.Rank(30))
+	assert.Equal(t, int64(1), treeMap.CountRange(15, 35))
+}
+
+func TestTreeMap_Seq(t *testing.T) {
+	m := map[int]string{3: "3", 1: "1", 2: "2"}
+	treeMap, err := NewTreeMapWithMap(cmp(), m)
+	require.NoError(t, err)
+
+	assert.Equal(t, m, maps.Collect(treeMap.All()))
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(treeMap.KeysSeq()))
+	assert.Equal(t, []string{"1", "2", "3"}, slices.Collect(treeMap.ValsSeq()))
+
+	keys := make([]int, 0)
+	for key := range treeMap.Backward() {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{3, 2, 1}, keys)
+
+	keys = keys[:0]
+	for key := range treeMap.All() {
+		keys = append(keys, key)
+		if key == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, keys)
+}
@@ -0,0 +1,64 @@
+package xset
+
+import (
+	"iter"
+
+	"github.com/JrMarcco/jit/xmap"
+)
+
+var _ Set[any] = (*ConcurrentSet[any])(nil)
+
+// ConcurrentSet is a thread-safe set built on xmap.ConcurrentMap.
+type ConcurrentSet[T any] struct {
+	cm *xmap.ConcurrentMap[T, struct{}]
+}
+
+func (s *ConcurrentSet[T]) Add(elem T) {
+	_ = s.cm.Put(elem, struct{}{})
+}
+
+// AddIfAbsent atomically adds the element and reports whether it was absent before.
+func (s *ConcurrentSet[T]) AddIfAbsent(elem T) bool {
+	_, loaded := s.cm.ComputeIfAbsent(elem, func(T) struct{} { return struct{}{} })
+	return !loaded
+}
+
+func (s *ConcurrentSet[T]) Del(elem T) {
+	_, _ = s.cm.Del(elem)
+}
+
+func (s *ConcurrentSet[T]) Exist(elem T) bool {
+	_, ok := s.cm.Get(elem)
+	return ok
+}
+
+func (s *ConcurrentSet[T]) Elems() []T {
+	return s.cm.Keys()
+}
+
+// All returns an iterator over elements in no particular order.
+func (s *ConcurrentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.cm.Iter(func(key T, _ struct{}) bool {
+			return yield(key)
+		})
+	}
+}
+
+func (s *ConcurrentSet[T]) Size() int {
+	return int(s.cm.Size())
+}
+
+// NewConcurrentSet creates a concurrent set for comparable elements.
+func NewConcurrentSet[T comparable](shardCnt int) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{
+		cm: xmap.NewConcurrentMap[T, struct{}](shardCnt),
+	}
+}
+
+// NewConcurrentHashSet creates a concurrent set for xmap.Hashable elements.
+func NewConcurrentHashSet[T xmap.Hashable](shardCnt int) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{
+		cm: xmap.NewConcurrentHashMap[T, struct{}](shardCnt),
+	}
+}
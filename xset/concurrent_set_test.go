@@ -0,0 +1,73 @@
+package xset
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hashKey struct {
+	id uint64
+}
+
+func (k hashKey) Hash() uint64 {
+	return k.id % 4
+}
+
+func (k hashKey) Equals(other any) bool {
+	val, ok := other.(hashKey)
+	return ok && k.id == val.id
+}
+
+func TestConcurrentSet(t *testing.T) {
+	s := NewConcurrentSet[int](4)
+	for _, elem := range []int{1, 2, 3, 3} {
+		s.Add(elem)
+	}
+
+	assert.Equal(t, 3, s.Size())
+	assert.True(t, s.Exist(1))
+	assert.False(t, s.Exist(4))
+	assert.ElementsMatch(t, []int{1, 2, 3}, s.Elems())
+	assert.ElementsMatch(t, []int{1, 2, 3}, slices.Collect(s.All()))
+
+	assert.True(t, s.AddIfAbsent(4))
+	assert.False(t, s.AddIfAbsent(4))
+
+	s.Del(1)
+	assert.False(t, s.Exist(1))
+	assert.ElementsMatch(t, []int{2, 3, 4}, s.Elems())
+}
+
+func TestConcurrentHashSet_Parallel(t *testing.T) {
+	const (
+		goroutineCnt = 64
+		elemCnt      = 512
+	)
+
+	s := NewConcurrentHashSet[hashKey](8)
+
+	var addedCnt atomic.Int64
+	var wg sync.WaitGroup
+	for range goroutineCnt {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range uint64(elemCnt) {
+				if s.AddIfAbsent(hashKey{id: i}) {
+					addedCnt.Add(1)
+				}
+				_ = s.Exist(hashKey{id: i})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// each element is added exactly once
+	assert.Equal(t, int64(elemCnt), addedCnt.Load())
+	assert.Equal(t, elemCnt, s.Size())
+}
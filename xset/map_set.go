@@ -1,5 +1,10 @@
 package xset
 
+import (
+	"iter"
+	"maps"
+)
+
 var _ Set[any] = (*MapSet[any])(nil)
 
 type MapSet[T comparable] struct {
@@ -27,10 +32,98 @@ func (s *MapSet[T]) Elems() []T {
 	return keys
 }
 
+// All returns an iterator over elements in no particular order.
+func (s *MapSet[T]) All() iter.Seq[T] {
+	return maps.Keys(s.m)
+}
+
 func (s *MapSet[T]) Size() int {
 	return len(s.m)
 }
 
+// Clone returns a shallow copy of the set.
+func (s *MapSet[T]) Clone() *MapSet[T] {
+	return &MapSet[T]{
+		m: maps.Clone(s.m),
+	}
+}
+
+// Union returns a new set with the elements in s or other.
+func (s *MapSet[T]) Union(other *MapSet[T]) *MapSet[T] {
+	res := NewMapSet[T](len(s.m) + len(other.m))
+	maps.Copy(res.m, s.m)
+	maps.Copy(res.m, other.m)
+	return res
+}
+
+// Intersect returns a new set with the elements in both s and other.
+func (s *MapSet[T]) Intersect(other *MapSet[T]) *MapSet[T] {
+	// iterate the smaller set
+	small, large := s, other
+	if len(small.m) > len(large.m) {
+		small, large = large, small
+	}
+
+	res := NewMapSet[T](len(small.m))
+	for key := range small.m {
+		if large.Exist(key) {
+			res.Add(key)
+		}
+	}
+	return res
+}
+
+// Difference returns a new set with the elements in s but not in other.
+func (s *MapSet[T]) Difference(other *MapSet[T]) *MapSet[T] {
+	res := NewMapSet[T](len(s.m))
+	for key := range s.m {
+		if !other.Exist(key) {
+			res.Add(key)
+		}
+	}
+	return res
+}
+
+// SymmetricDifference returns a new set with the elements in either s or other but not in both.
+func (s *MapSet[T]) SymmetricDifference(other *MapSet[T]) *MapSet[T] {
+	res := NewMapSet[T](len(s.m) + len(other.m))
+	for key := range s.m {
+		if !other.Exist(key) {
+			res.Add(key)
+		}
+	}
+	for key := range other.m {
+		if !s.Exist(key) {
+			res.Add(key)
+		}
+	}
+	return res
+}
+
+// IsSubset reports whether every element of s is in other.
+func (s *MapSet[T]) IsSubset(other *MapSet[T]) bool {
+	if len(s.m) > len(other.m) {
+		return false
+	}
+
+	for key := range s.m {
+		if !other.Exist(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every element of other is in s.
+func (s *MapSet[T]) IsSuperset(other *MapSet[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal reports whether s and other contain the same elements.
+func (s *MapSet[T]) Equal(other *MapSet[T]) bool {
+	return len(s.m) == len(other.m) && s.IsSubset(other)
+}
+
 func NewMapSet[T comparable](size int) *MapSet[T] {
 	return &MapSet[T]{
 		m: make(map[T]struct{}, size),
@@ -1,6 +1,7 @@
 package xset
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -119,3 +120,121 @@ func TestMapSet_Keys(t *testing.T) {
 		})
 	}
 }
+
+func TestMapSet_All(t *testing.T) {
+	s := NewMapSet[int](4)
+	for _, elem := range []int{1, 2, 3} {
+		s.Add(elem)
+	}
+
+	assert.ElementsMatch(t, []int{1, 2, 3}, slices.Collect(s.All()))
+}
+
+func mapSetOf(elems ...int) *MapSet[int] {
+	s := NewMapSet[int](len(elems))
+	for _, elem := range elems {
+		s.Add(elem)
+	}
+	return s
+}
+
+func TestMapSet_Algebra(t *testing.T) {
+	tcs := []struct {
+		name         string
+		src          []int
+		dst          []int
+		wantUnion    []int
+		wantInter    []int
+		wantDiff     []int
+		wantSymmDiff []int
+		wantSubset   bool
+		wantSuperset bool
+		wantEqual    bool
+	}{
+		{
+			name:         "overlapping",
+			src:          []int{1, 2, 3},
+			dst:          []int{2, 3, 4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{2, 3},
+			wantDiff:     []int{1},
+			wantSymmDiff: []int{1, 4},
+		}, {
+			name:         "disjoint",
+			src:          []int{1, 2},
+			dst:          []int{3, 4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{},
+			wantDiff:     []int{1, 2},
+			wantSymmDiff: []int{1, 2, 3, 4},
+		}, {
+			name:         "subset",
+			src:          []int{1, 2},
+			dst:          []int{1, 2, 3},
+			wantUnion:    []int{1, 2, 3},
+			wantInter:    []int{1, 2},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{3},
+			wantSubset:   true,
+		}, {
+			name:         "superset",
+			src:          []int{1, 2, 3},
+			dst:          []int{1, 3},
+			wantUnion:    []int{1, 2, 3},
+			wantInter:    []int{1, 3},
+			wantDiff:     []int{2},
+			wantSymmDiff: []int{2},
+			wantSuperset: true,
+		}, {
+			name:         "equal",
+			src:          []int{1, 2, 3},
+			dst:          []int{3, 2, 1},
+			wantUnion:    []int{1, 2, 3},
+			wantInter:    []int{1, 2, 3},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{},
+			wantSubset:   true,
+			wantSuperset: true,
+			wantEqual:    true,
+		}, {
+			name:         "empty",
+			src:          []int{},
+			dst:          []int{},
+			wantUnion:    []int{},
+			wantInter:    []int{},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{},
+			wantSubset:   true,
+			wantSuperset: true,
+			wantEqual:    true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			src, dst := mapSetOf(tc.src...), mapSetOf(tc.dst...)
+
+			assert.ElementsMatch(t, tc.wantUnion, src.Union(dst).Elems())
+			assert.ElementsMatch(t, tc.wantInter, src.Intersect(dst).Elems())
+			assert.ElementsMatch(t, tc.wantDiff, src.Difference(dst).Elems())
+			assert.ElementsMatch(t, tc.wantSymmDiff, src.SymmetricDifference(dst).Elems())
+			assert.Equal(t, tc.wantSubset, src.IsSubset(dst))
+			assert.Equal(t, tc.wantSuperset, src.IsSuperset(dst))
+			assert.Equal(t, tc.wantEqual, src.Equal(dst))
+
+			// operands are not modified
+			assert.ElementsMatch(t, tc.src, src.Elems())
+			assert.ElementsMatch(t, tc.dst, dst.Elems())
+		})
+	}
+}
+
+func TestMapSet_Clone(t *testing.T) {
+	s := mapSetOf(1, 2, 3)
+	c := s.Clone()
+	assert.True(t, s.Equal(c))
+
+	c.Add(4)
+	assert.False(t, s.Exist(4))
+	assert.Equal(t, 3, s.Size())
+}
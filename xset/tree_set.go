@@ -1,6 +1,8 @@
 package xset
 
 import (
+	"iter"
+
 	"github.com/JrMarcco/jit"
 	"github.com/JrMarcco/jit/xmap"
 )
@@ -8,7 +10,8 @@ import (
 var _ Set[any] = (*TreeSet[any])(nil)
 
 type TreeSet[T any] struct {
-	tm *xmap.TreeMap[T, struct{}]
+	tm  *xmap.TreeMap[T, struct{}]
+	cmp jit.Comparator[T]
 }
 
 func (s *TreeSet[T]) Add(elem T) {
@@ -28,11 +31,227 @@ func (s *TreeSet[T]) Elems() []T {
 	return s.tm.Keys()
 }
 
+func (s *TreeSet[T]) Size() int {
+	return int(s.tm.Size())
+}
+
+// Clone returns a shallow copy of the set.
+func (s *TreeSet[T]) Clone() *TreeSet[T] {
+	res := s.newEmpty()
+	for _, elem := range s.Elems() {
+		res.Add(elem)
+	}
+	return res
+}
+
+// Union returns a new set with the elements in s or other.
+//
+// The set operations of TreeSet merge the ordered elements of both sets,
+// so s and other are required to be ordered by the same comparator.
+func (s *TreeSet[T]) Union(other *TreeSet[T]) *TreeSet[T] {
+	return s.merge(other, true, true, true)
+}
+
+// Intersect returns a new set with the elements in both s and other.
+func (s *TreeSet[T]) Intersect(other *TreeSet[T]) *TreeSet[T] {
+	return s.merge(other, false, true, false)
+}
+
+// Difference returns a new set with the elements in s but not in other.
+func (s *TreeSet[T]) Difference(other *TreeSet[T]) *TreeSet[T] {
+	return s.merge(other, true, false, false)
+}
+
+// SymmetricDifference returns a new set with the elements in either s or other but not in both.
+func (s *TreeSet[T]) SymmetricDifference(other *TreeSet[T]) *TreeSet[T] {
+	return s.merge(other, true, false, true)
+}
+
+// IsSubset reports whether every element of s is in other.
+func (s *TreeSet[T]) IsSubset(other *TreeSet[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+
+	src, dst := s.Elems(), other.Elems()
+
+	j := 0
+	for _, elem := range src {
+		// skip the elements of other that are less than the current element
+		for j < len(dst) && s.cmp(dst[j], elem) < 0 {
+			j++
+		}
+
+		if j == len(dst) || s.cmp(dst[j], elem) != 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// IsSuperset reports whether every element of other is in s.
+func (s *TreeSet[T]) IsSuperset(other *TreeSet[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal reports whether s and other contain the same elements.
+func (s *TreeSet[T]) Equal(other *TreeSet[T]) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
+// merge walks the ordered elements of s and other at the same time.
+//
+//	keepSrc:  keep the elements only in s.
+//	keepBoth: keep the elements both in s and other.
+//	keepDst:  keep the elements only in other.
+func (s *TreeSet[T]) merge(other *TreeSet[T], keepSrc, keepBoth, keepDst bool) *TreeSet[T] {
+	res := s.newEmpty()
+	src, dst := s.Elems(), other.Elems()
+
+	i, j := 0, 0
+	for i < len(src) && j < len(dst) {
+		cmp := s.cmp(src[i], dst[j])
+		switch {
+		case cmp < 0:
+			if keepSrc {
+				res.Add(src[i])
+			}
+			i++
+		case cmp > 0:
+			if keepDst {
+				res.Add(dst[j])
+			}
+			j++
+		default:
+			if keepBoth {
+				res.Add(src[i])
+			}
+			i++
+			j++
+		}
+	}
+
+	if keepSrc {
+		for ; i < len(src); i++ {
+			res.Add(src[i])
+		}
+	}
+
+	if keepDst {
+		for ; j < len(dst); j++ {
+			res.Add(dst[j])
+		}
+	}
+
+	return res
+}
+
+// newEmpty creates an empty set with the same comparator of s.
+func (s *TreeSet[T]) newEmpty() *TreeSet[T] {
+	// comparator of s is never nil, error can be ignored.
+	res, _ := NewTreeSet[T](s.cmp)
+	return res
+}
+
+// All returns an iterator over elements in ascending order.
+func (s *TreeSet[T]) All() iter.Seq[T] {
+	return s.tm.KeysSeq()
+}
+
+// Backward returns an iterator over elements in descending order.
+func (s *TreeSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.tm.IterDesc(func(key T, _ struct{}) bool {
+			return yield(key)
+		})
+	}
+}
+
+// Rank returns the number of elements strictly less than the given element.
+// If the element exists, the rank is its 0-based index in ascending order.
+func (s *TreeSet[T]) Rank(elem T) int64 {
+	return s.tm.Rank(elem)
+}
+
+// Select returns the element at the given 0-based index in ascending order.
+func (s *TreeSet[T]) Select(index int64) (T, bool) {
+	key, _, ok := s.tm.Select(index)
+	return key, ok
+}
+
+// CountRange returns the number of elements in [lo, hi).
+func (s *TreeSet[T]) CountRange(lo T, hi T) int64 {
+	return s.tm.CountRange(lo, hi)
+}
+
+// IterRange traversal the elements in [from, to) in ascending order.
+func (s *TreeSet[T]) IterRange(from T, to T, visitFunc func(elem T) bool) {
+	s.tm.IterRange(from, to, func(key T, _ struct{}) bool {
+		return visitFunc(key)
+	})
+}
+
+// IterRangeDesc traversal the elements in [from, to) in descending order.
+func (s *TreeSet[T]) IterRangeDesc(from T, to T, visitFunc func(elem T) bool) {
+	s.tm.IterRangeDesc(from, to, func(key T, _ struct{}) bool {
+		return visitFunc(key)
+	})
+}
+
+// Floor returns the greatest element less than or equal to the given element.
+func (s *TreeSet[T]) Floor(elem T) (T, bool) {
+	key, _, ok := s.tm.Floor(elem)
+	return key, ok
+}
+
+// Ceiling returns the least element greater than or equal to the given element.
+func (s *TreeSet[T]) Ceiling(elem T) (T, bool) {
+	key, _, ok := s.tm.Ceiling(elem)
+	return key, ok
+}
+
+// Lower returns the greatest element strictly less than the given element.
+func (s *TreeSet[T]) Lower(elem T) (T, bool) {
+	key, _, ok := s.tm.Lower(elem)
+	return key, ok
+}
+
+// Higher returns the least element strictly greater than the given element.
+func (s *TreeSet[T]) Higher(elem T) (T, bool) {
+	key, _, ok := s.tm.Higher(elem)
+	return key, ok
+}
+
+// First returns the least element of the set.
+func (s *TreeSet[T]) First() (T, bool) {
+	key, _, ok := s.tm.First()
+	return key, ok
+}
+
+// Last returns the greatest element of the set.
+func (s *TreeSet[T]) Last() (T, bool) {
+	key, _, ok := s.tm.Last()
+	return key, ok
+}
+
+// PollFirst removes and returns the least element of the set.
+func (s *TreeSet[T]) PollFirst() (T, bool) {
+	key, _, ok := s.tm.PollFirst()
+	return key, ok
+}
+
+// PollLast removes and returns the greatest element of the set.
+func (s *TreeSet[T]) PollLast() (T, bool) {
+	key, _, ok := s.tm.PollLast()
+	return key, ok
+}
+
 func NewTreeSet[T any](cmp jit.Comparator[T]) (*TreeSet[T], error) {
 	tm, err := xmap.NewTreeMap[T, struct{}](cmp)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TreeSet[T]{tm: tm}, nil
+	return &TreeSet[T]{tm: tm, cmp: cmp}, nil
 }
@@ -1,6 +1,7 @@
 package xset
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/JrMarcco/jit"
@@ -141,3 +142,220 @@ func TestTreeSet_Exist(t *testing.T) {
 		})
 	}
 }
+
+func TestTreeSet_Navigation(t *testing.T) {
+	s, err := NewTreeSet(cmp)
+	require.NoError(t, err)
+
+	for _, elem := range []int{10, 30, 20, 40} {
+		s.Add(elem)
+	}
+
+	tcs := []struct {
+		name     string
+		navFunc  func(elem int) (int, bool)
+		elem     int
+		wantElem int
+		wantRes  bool
+	}{
+		{name: "floor", navFunc: s.Floor, elem: 25, wantElem: 20, wantRes: true},
+		{name: "floor non-existent", navFunc: s.Floor, elem: 5, wantRes: false},
+		{name: "ceiling", navFunc: s.Ceiling, elem: 25, wantElem: 30, wantRes: true},
+		{name: "ceiling non-existent", navFunc: s.Ceiling, elem: 45, wantRes: false},
+		{name: "lower", navFunc: s.Lower, elem: 30, wantElem: 20, wantRes: true},
+		{name: "higher", navFunc: s.Higher, elem: 30, wantElem: 40, wantRes: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			elem, ok := tc.navFunc(tc.elem)
+			assert.Equal(t, tc.wantRes, ok)
+			assert.Equal(t, tc.wantElem, elem)
+		})
+	}
+
+	first, ok := s.First()
+	assert.True(t, ok)
+	assert.Equal(t, 10, first)
+
+	last, ok := s.Last()
+	assert.True(t, ok)
+	assert.Equal(t, 40, last)
+
+	elems := make([]int, 0)
+	s.IterRange(15, 40, func(elem int) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	assert.Equal(t, []int{20, 30}, elems)
+
+	elems = elems[:0]
+	s.IterRangeDesc(15, 41, func(elem int) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	assert.Equal(t, []int{40, 30, 20}, elems)
+
+	first, ok = s.PollFirst()
+	assert.True(t, ok)
+	assert.Equal(t, 10, first)
+
+	last, ok = s.PollLast()
+	assert.True(t, ok)
+	assert.Equal(t, 40, last)
+
+	assert.Equal(t, []int{20, 30}, s.Elems())
+}
+
+func TestTreeSet_OrderStatistic(t *testing.T) {
+	s, err := NewTreeSet(cmp)
+	require.NoError(t, err)
+
+	for _, elem := range []int{40, 10, 30, 20} {
+		s.Add(elem)
+	}
+
+	assert.Equal(t, int64(1), s.Rank(20))
+	assert.Equal(t, int64(3), s.Rank(35))
+
+	elem, ok := s.Select(3)
+	assert.True(t, ok)
+	assert.Equal(t, 40, elem)
+
+	_, ok = s.Select(-1)
+	assert.False(t, ok)
+
+	assert.Equal(t, int64(3), s.CountRange(10, 40))
+}
+
+func TestTreeSet_Seq(t *testing.T) {
+	s, err := NewTreeSet(cmp)
+	require.NoError(t, err)
+
+	for _, elem := range []int{3, 1, 2} {
+		s.Add(elem)
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(s.All()))
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(s.Backward()))
+}
+
+func treeSetOf(t *testing.T, elems ...int) *TreeSet[int] {
+	s, err := NewTreeSet(cmp)
+	require.NoError(t, err)
+
+	for _, elem := range elems {
+		s.Add(elem)
+	}
+	return s
+}
+
+func TestTreeSet_Algebra(t *testing.T) {
+	tcs := []struct {
+		name         string
+		src          []int
+		dst          []int
+		wantUnion    []int
+		wantInter    []int
+		wantDiff     []int
+		wantSymmDiff []int
+		wantSubset   bool
+		wantSuperset bool
+		wantEqual    bool
+	}{
+		{
+			name:         "overlapping",
+			src:          []int{1, 2, 3},
+			dst:          []int{2, 3, 4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{2, 3},
+			wantDiff:     []int{1},
+			wantSymmDiff: []int{1, 4},
+		}, {
+			name:         "disjoint and interleaved",
+			src:          []int{1, 3, 5},
+			dst:          []int{2, 4, 6},
+			wantUnion:    []int{1, 2, 3, 4, 5, 6},
+			wantInter:    []int{},
+			wantDiff:     []int{1, 3, 5},
+			wantSymmDiff: []int{1, 2, 3, 4, 5, 6},
+		}, {
+			name:         "subset",
+			src:          []int{2, 4},
+			dst:          []int{1, 2, 3, 4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{2, 4},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{1, 3},
+			wantSubset:   true,
+		}, {
+			name:         "superset",
+			src:          []int{1, 2, 3, 4},
+			dst:          []int{4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{4},
+			wantDiff:     []int{1, 2, 3},
+			wantSymmDiff: []int{1, 2, 3},
+			wantSuperset: true,
+		}, {
+			name:         "same size but not equal",
+			src:          []int{1, 2, 3},
+			dst:          []int{1, 2, 4},
+			wantUnion:    []int{1, 2, 3, 4},
+			wantInter:    []int{1, 2},
+			wantDiff:     []int{3},
+			wantSymmDiff: []int{3, 4},
+		}, {
+			name:         "equal",
+			src:          []int{3, 1, 2},
+			dst:          []int{1, 2, 3},
+			wantUnion:    []int{1, 2, 3},
+			wantInter:    []int{1, 2, 3},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{},
+			wantSubset:   true,
+			wantSuperset: true,
+			wantEqual:    true,
+		}, {
+			name:         "empty",
+			src:          []int{},
+			dst:          []int{},
+			wantUnion:    []int{},
+			wantInter:    []int{},
+			wantDiff:     []int{},
+			wantSymmDiff: []int{},
+			wantSubset:   true,
+			wantSuperset: true,
+			wantEqual:    true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			src, dst := treeSetOf(t, tc.src...), treeSetOf(t, tc.dst...)
+
+			assert.Equal(t, tc.wantUnion, src.Union(dst).Elems())
+			assert.Equal(t, tc.wantInter, src.Intersect(dst).Elems())
+			assert.Equal(t, tc.wantDiff, src.Difference(dst).Elems())
+			assert.Equal(t, tc.wantSymmDiff, src.SymmetricDifference(dst).Elems())
+			assert.Equal(t, tc.wantSubset, src.IsSubset(dst))
+			assert.Equal(t, tc.wantSuperset, src.IsSuperset(dst))
+			assert.Equal(t, tc.wantEqual, src.Equal(dst))
+
+			// operands are not modified
+			assert.ElementsMatch(t, tc.src, src.Elems())
+			assert.ElementsMatch(t, tc.dst, dst.Elems())
+		})
+	}
+}
+
+func TestTreeSet_Clone(t *testing.T) {
+	s := treeSetOf(t, 3, 1, 2)
+	c := s.Clone()
+	assert.True(t, s.Equal(c))
+	assert.Equal(t, []int{1, 2, 3}, c.Elems())
+
+	c.Add(4)
+	assert.False(t, s.Exist(4))
+	assert.Equal(t, 3, s.Size())
+}